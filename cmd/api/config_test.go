@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// writeFile writes content to the file name of a temporary directory, and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Setup
+
+	passwordFile := writeFile(t, "admin_password", "hunter22\n")
+	yamlConfig := writeFile(t, "config.yaml", `
+port: "8080"
+database_max_open_conns: 10
+jwt_access_token_ttl: 5m
+admin_password_file: `+passwordFile+`
+`)
+	tomlConfig := writeFile(t, "config.toml", `
+rate_limit_store = "postgres"
+rate_limit_read_rate = 2.5
+`)
+
+	// Test
+
+	t.Run("defaults", func(t *testing.T) {
+		got, err := loadConfig("")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if got != defaultConfig {
+			t.Errorf("got config %+v, want %+v", got, defaultConfig)
+		}
+	})
+	t.Run("YAML file, overridden by the environment", func(t *testing.T) {
+		t.Setenv("DATABASE_MAX_OPEN_CONNS", "20")
+		got, err := loadConfig(yamlConfig)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		want := defaultConfig
+		want.Port, want.DatabaseMaxOpenConns, want.JWTAccessTokenTTL, want.AdminPassword = "8080", 20, 5*time.Minute, "hunter22"
+		if got != want {
+			t.Errorf("got config %+v, want %+v", got, want)
+		}
+	})
+	t.Run("TOML file", func(t *testing.T) {
+		got, err := loadConfig(tomlConfig)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if got.RateLimitStore != "postgres" || got.RateLimitReadRate != 2.5 {
+			t.Errorf("got config %+v, want the rate limits of the file", got)
+		}
+	})
+	t.Run("secret file of the environment", func(t *testing.T) {
+		t.Setenv("DATABASE_URL_FILE", writeFile(t, "database_url", "postgres://rental:hunter22@db:5432/rental\n"))
+		got, err := loadConfig("")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if got.DatabaseURL != "postgres://rental:hunter22@db:5432/rental" {
+			t.Errorf("got database_url %q, want the URL of the file", got.DatabaseURL)
+		}
+	})
+	t.Run("secret set twice", func(t *testing.T) {
+		t.Setenv("ADMIN_PASSWORD", "hunter23")
+		if _, err := loadConfig(yamlConfig); err == nil {
+			t.Errorf("got nil error, want error")
+		}
+	})
+	t.Run("unknown setting", func(t *testing.T) {
+		if _, err := loadConfig(writeFile(t, "config.yaml", "prot: 8080\n")); err == nil || !strings.Contains(err.Error(), "prot") {
+			t.Errorf("got error %v, want unknown setting error", err)
+		}
+	})
+	t.Run("invalid setting", func(t *testing.T) {
+		t.Setenv("DATABASE_MAX_OPEN_CONNS", "0")
+		if _, err := loadConfig(""); err == nil || !strings.Contains(err.Error(), "database_max_open_conns") {
+			t.Errorf("got error %v, want invalid database_max_open_conns error", err)
+		}
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(config *Config)
+		wantErr string
+	}{
+		{name: "Valid", change: func(config *Config) {}},
+		{name: "Connection string", change: func(config *Config) { config.DatabaseURL = "host=localhost dbname=rental" }},
+		{name: "Invalid port", change: func(config *Config) { config.Port = "http" }, wantErr: "port"},
+		{name: "Same ports", change: func(config *Config) { config.AdminPort = config.Port }, wantErr: "admin_port"},
+		{name: "MySQL URL", change: func(config *Config) { config.DatabaseURL = "mysql://localhost/rental" }, wantErr: "database_url"},
+		{name: "No connections", change: func(config *Config) { config.DatabaseMaxOpenConns = 0 }, wantErr: "database_max_open_conns"},
+		{name: "Short admin password", change: func(config *Config) { config.AdminPassword = "hunter" }, wantErr: "admin_password"},
+		{name: "Unknown log level", change: func(config *Config) { config.LogLevel = "trace" }, wantErr: "log_level"},
+		{name: "Refresh tokens expiring first", change: func(config *Config) { config.JWTRefreshTokenTTL = time.Minute }, wantErr: "jwt_refresh_token_ttl"},
+		{name: "Unknown rate limit store", change: func(config *Config) { config.RateLimitStore = "redis" }, wantErr: "rate_limit_store"},
+		{name: "Negative rate", change: func(config *Config) { config.RateLimitWriteRate = -1 }, wantErr: "rate_limit_write_rate"},
+		{name: "Invalid sunset", change: func(config *Config) { config.APIV1Sunset = "soon" }, wantErr: "api_v1_sunset"},
+		{name: "Invalid dependencies", change: func(config *Config) { config.ReadinessDependencies = "pricing" }, wantErr: "readiness_dependencies"},
+		{name: "Unknown exporter", change: func(config *Config) { config.TracingExporter = "jaeger" }, wantErr: "tracing_exporter"},
+		{name: "Sample ratio above 1", change: func(config *Config) { config.TracingSampleRatio = 2 }, wantErr: "tracing_sample_ratio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := defaultConfig
+			tt.change(&config)
+			err := config.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("got error %v, want %s error", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrintConfig(t *testing.T) {
+	config := defaultConfig
+	config.DatabaseURL = "postgres://rental:hunter22@db:5432/rental"
+	config.AdminPassword = "hunter22"
+
+	var out bytes.Buffer
+	if err := printConfig(&out, config); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if strings.Contains(out.String(), "hunter22") {
+		t.Errorf("got config %s, want secrets redacted", out.String())
+	}
+	for _, line := range []string{"port: \"9090\"\n", "database_url: postgres://rental:xxxxx@db:5432/rental\n", "jwt_access_token_ttl: 15m0s\n"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("got config %s, want line %q", out.String(), line)
+		}
+	}
+}
+
+func TestReloadOnSIGHUP(t *testing.T) {
+	// Setup
+
+	path := writeFile(t, "config.yaml", "log_level: info\n")
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	applied := make(chan Config, 1)
+	reloadOnSIGHUP(ctx, path, config, func(config Config) {
+		applied <- config
+	})
+
+	// Test
+
+	os.WriteFile(path, []byte("log_level: debug\nrate_limit_read_burst: 5\nport: \"8080\"\n"), 0600)
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	select {
+	case got := <-applied:
+		if got.logLevel() != slog.LevelDebug || got.RateLimitReadBurst != 5 {
+			t.Errorf("got config %+v, want debug level and read burst 5", got)
+		}
+		if got.Port != defaultConfig.Port {
+			t.Errorf("got port %s, want %s until restart", got.Port, defaultConfig.Port)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("got no config applied, want config reloaded")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/shidenkai0/rental/pkg/database"
+)
+
+// migrateUsage documents the migrate subcommand.
+const migrateUsage = `usage: rental migrate up|down [steps]|status|force version
+
+  up              apply the pending migrations
+  down [steps]    revert the latest steps migrations, 1 by default
+  status          print the version of the schema and of the latest migration
+  force version   set the version of the schema once a failed migration was fixed by hand`
+
+// runMigrate runs the migrate subcommand with args on db, printing the status to w.
+func runMigrate(ctx context.Context, db *sql.DB, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Missing migrate command\n%s", migrateUsage)
+	}
+	m, err := database.NewMigrator(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch command, args := args[0], args[1:]; {
+	case command == "up" && len(args) == 0:
+		if err := m.Up(); err != nil {
+			return err
+		}
+	case command == "down" && len(args) <= 1:
+		steps := 1
+		if len(args) == 1 {
+			if steps, err = strconv.Atoi(args[0]); err != nil {
+				return fmt.Errorf("Invalid number of steps %q", args[0])
+			}
+		}
+		if err := m.Down(steps); err != nil {
+			return err
+		}
+	case command == "force" && len(args) == 1:
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("Invalid version %q", args[0])
+		}
+		if err := m.Force(version); err != nil {
+			return err
+		}
+	case command == "status" && len(args) == 0:
+	default:
+		return fmt.Errorf("Invalid migrate command %q\n%s", command, migrateUsage)
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "version: %d\nlatest: %d\ndirty: %t\n", status.Version, status.Latest, status.Dirty)
+	return nil
+}
+
+// migrateUp applies the pending migrations to db on start, holding the
+// migration lock.
+func migrateUp(ctx context.Context, db *sql.DB) error {
+	m, err := database.NewMigrator(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	return m.UpLocked(ctx)
+}
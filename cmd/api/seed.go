@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/database"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"github.com/shidenkai0/rental/pkg/seed"
+)
+
+// seedUsage documents the seed subcommand.
+const seedUsage = `usage: rental seed [-seed n] [-cars n] [-customers n] [-rentals n] [-end date] [-tenant slug]
+
+Generates cars, customers and a rental history in a tenant, the same flags
+generating the same data. The ids of every table are reset past their largest
+id first, so that the data inserted with explicit ids doesn't collide with
+the data created afterwards.`
+
+// runSeed runs the seed subcommand with args on db, printing what it created to w.
+func runSeed(ctx context.Context, db *sqlx.DB, args []string, w io.Writer) error {
+	config := seed.Config{}
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Int64Var(&config.Seed, "seed", 1, "seed of the random generator")
+	flags.IntVar(&config.Cars, "cars", 50, "number of cars")
+	flags.IntVar(&config.Customers, "customers", 20, "number of customers")
+	flags.IntVar(&config.Rentals, "rentals", 200, "number of rentals over the past year")
+	end := flags.String("end", time.Now().UTC().Format(time.DateOnly), "end of the rental history, as YYYY-MM-DD")
+	tenantSlug := flags.String("tenant", "default", "slug of the tenant to seed")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+		return fmt.Errorf("Invalid seed arguments\n%s", seedUsage)
+	}
+	var err error
+	if config.End, err = time.Parse(time.DateOnly, *end); err != nil {
+		return fmt.Errorf("Invalid end date %q", *end)
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	tenant, err := database.NewDatabaseTenantService(db).GetBySlug(ctx, *tenantSlug)
+	if err != nil {
+		return err
+	}
+	if err := database.ResetSequences(ctx, db); err != nil {
+		return fmt.Errorf("resetting sequences: %w", err)
+	}
+	report, err := seed.Seed(rental.WithTenant(ctx, tenant.ID), seed.Services{
+		Cars:       database.NewDatabaseCarCRUDService(db),
+		Customers:  database.NewDatabaseCustomerCRUDService(db),
+		Rentals:    database.NewDatabaseRentalService(db),
+		Transactor: database.NewDatabaseTransactor(db),
+	}, config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "cars: %d\ncustomers: %d\nrentals: %d\nactive rentals: %d\n", report.Cars, report.Customers, report.Rentals, report.ActiveRentals)
+	return nil
+}
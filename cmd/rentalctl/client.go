@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+	"github.com/shidenkai0/rental/pkg/auth"
+)
+
+// Headers of the conditional requests of the API.
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
+// Content types of the requests of the API.
+const (
+	contentTypeMergePatch = "application/merge-patch+json"
+	contentTypeCSV        = "text/csv"
+	contentTypeNDJSON     = "application/x-ndjson"
+)
+
+// apiError is an error response of the API.
+type apiError struct {
+	Status  int
+	Message string
+}
+
+func (err *apiError) Error() string {
+	if err.Message == "" {
+		return http.StatusText(err.Status)
+	}
+	return fmt.Sprintf("%s (%s)", err.Message, http.StatusText(err.Status))
+}
+
+// client calls version 2 of the API with the credentials of a profile.
+type client struct {
+	http    *http.Client
+	baseURL string
+	profile Profile
+}
+
+// newClient returns a client of the API at baseURL, eg.
+// https://rental.mmess.dev, sending requests with transport.
+func newClient(baseURL string, transport http.RoundTripper, profile Profile) *client {
+	return &client{
+		http:    &http.Client{Transport: transport},
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/v2",
+		profile: profile,
+	}
+}
+
+// send sends a request to path with the credentials of the profile. The
+// responses with an error status are returned as *apiError, along with the
+// response, whose body is left for the caller to close.
+func (c *client) send(ctx context.Context, method, path string, header http.Header, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if c.profile.APIKey != "" {
+		req.Header.Set(auth.HeaderAPIKey, c.profile.APIKey)
+	} else {
+		req.SetBasicAuth(c.profile.Username, c.profile.Password)
+	}
+	if c.profile.Tenant != "" {
+		req.Header.Set(auth.HeaderTenant, c.profile.Tenant)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return resp, nil
+	}
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+	apiErr := &apiError{Status: resp.StatusCode}
+	var errorResponse genv2.ErrorResponse
+	if json.Unmarshal(content, &errorResponse) == nil {
+		apiErr.Message = errorResponse.Message
+	}
+	return resp, apiErr
+}
+
+// do sends a request to path with the JSON encoding of in as body unless nil,
+// and decodes the JSON response into out unless nil. It returns the ETag of
+// the response.
+func (c *client) do(ctx context.Context, method, path string, header http.Header, in, out interface{}) (string, error) {
+	var body io.Reader
+	if in != nil {
+		content, err := json.Marshal(in)
+		if err != nil {
+			return "", err
+		}
+		body = bytes.NewReader(content)
+		if header == nil {
+			header = http.Header{}
+		}
+		if header.Get(echo.HeaderContentType) == "" {
+			header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		}
+	}
+	resp, err := c.send(ctx, method, path, header, body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return "", fmt.Errorf("Decoding response: %w", err)
+		}
+	}
+	return resp.Header.Get(headerETag), nil
+}
+
+// change sends a change of the entity at path, based on its current version.
+func (c *client) change(ctx context.Context, method, path string, header http.Header, in, out interface{}) error {
+	etag, err := c.do(ctx, http.MethodGet, path, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(headerIfMatch, etag)
+	_, err = c.do(ctx, method, path, header, in, out)
+	return err
+}
+
+// joinIDs returns ids as the comma separated list of the ids parameter.
+func joinIDs(ids []int64) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(values, ",")
+}
+
+// ListCars returns the fleet, ordered by ID.
+func (c *client) ListCars(ctx context.Context) ([]genv2.Car, error) {
+	resp, err := c.send(ctx, http.MethodGet, "/car/export?format=ndjson", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	cars := []genv2.Car{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var car genv2.Car
+		if err := json.Unmarshal(scanner.Bytes(), &car); err != nil {
+			return nil, fmt.Errorf("Decoding exported car: %w", err)
+		}
+		cars = append(cars, car)
+	}
+	return cars, scanner.Err()
+}
+
+// GetCars returns the cars ids found, ordered by ID.
+func (c *client) GetCars(ctx context.Context, ids []int64) ([]genv2.Car, error) {
+	cars := []genv2.Car{}
+	_, err := c.do(ctx, http.MethodGet, "/car?ids="+joinIDs(ids), nil, nil, &cars)
+	return cars, err
+}
+
+// CreateCar creates a car.
+func (c *client) CreateCar(ctx context.Context, create genv2.CreateUpdateCarRequest) (genv2.Car, error) {
+	var car genv2.Car
+	_, err := c.do(ctx, http.MethodPost, "/car", nil, create, &car)
+	return car, err
+}
+
+// PatchCar changes the fields of the car id set in patch.
+func (c *client) PatchCar(ctx context.Context, id int64, patch genv2.CarPatch) (genv2.Car, error) {
+	var car genv2.Car
+	header := http.Header{echo.HeaderContentType: {contentTypeMergePatch}}
+	err := c.change(ctx, http.MethodPatch, fmt.Sprintf("/car/%d", id), header, patch, &car)
+	return car, err
+}
+
+// DeleteCar deletes the car id.
+func (c *client) DeleteCar(ctx context.Context, id int64) error {
+	return c.change(ctx, http.MethodDelete, fmt.Sprintf("/car/%d", id), nil, nil, nil)
+}
+
+// ImportCars imports the cars of a CSV or NDJSON file, of content type
+// contentType. In atomic mode, the report of the invalid rows is returned
+// along with the error.
+func (c *client) ImportCars(ctx context.Context, file io.Reader, contentType, mode string) (genv2.CarImportReport, error) {
+	var report genv2.CarImportReport
+	header := http.Header{echo.HeaderContentType: {contentType}}
+	resp, err := c.send(ctx, http.MethodPost, "/car/import?mode="+mode, header, file)
+	if resp != nil {
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusUnprocessableEntity {
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				return report, fmt.Errorf("Decoding response: %w", err)
+			}
+		}
+	}
+	return report, err
+}
+
+// GetCustomers returns the customers ids found, ordered by ID.
+func (c *client) GetCustomers(ctx context.Context, ids []int64) ([]genv2.Customer, error) {
+	customers := []genv2.Customer{}
+	_, err := c.do(ctx, http.MethodGet, "/customer?ids="+joinIDs(ids), nil, nil, &customers)
+	return customers, err
+}
+
+// CreateCustomer creates a customer.
+func (c *client) CreateCustomer(ctx context.Context, create genv2.CreateUpdateCustomerRequest) (genv2.Customer, error) {
+	var customer genv2.Customer
+	_, err := c.do(ctx, http.MethodPost, "/customer", nil, create, &customer)
+	return customer, err
+}
+
+// PatchCustomer changes the fields of the customer id set in patch.
+func (c *client) PatchCustomer(ctx context.Context, id int64, patch genv2.CustomerPatch) (genv2.Customer, error) {
+	var customer genv2.Customer
+	header := http.Header{echo.HeaderContentType: {contentTypeMergePatch}}
+	err := c.change(ctx, http.MethodPatch, fmt.Sprintf("/customer/%d", id), header, patch, &customer)
+	return customer, err
+}
+
+// DeleteCustomer deletes the customer id.
+func (c *client) DeleteCustomer(ctx context.Context, id int64) error {
+	return c.change(ctx, http.MethodDelete, fmt.Sprintf("/customer/%d", id), nil, nil, nil)
+}
+
+// ListCustomerRentals returns the cars rented by the customer id.
+func (c *client) ListCustomerRentals(ctx context.Context, id int64) ([]genv2.Car, error) {
+	cars := []genv2.Car{}
+	_, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/customer/%d/rentals", id), nil, nil, &cars)
+	return cars, err
+}
+
+// Rent rents the car carID to the customer customerID.
+func (c *client) Rent(ctx context.Context, carID, customerID int64) (genv2.Rental, error) {
+	var r genv2.Rental
+	_, err := c.do(ctx, http.MethodPost, "/rentals", nil, genv2.CreateRentalRequest{CarId: carID, CustomerId: &customerID}, &r)
+	return r, err
+}
+
+// Return returns the car of the rental id.
+func (c *client) Return(ctx context.Context, id int64) (genv2.Rental, error) {
+	var r genv2.Rental
+	_, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/rentals/%d/return", id), nil, nil, &r)
+	return r, err
+}
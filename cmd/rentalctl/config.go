@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// defaultProfile is the profile used when none is selected.
+const defaultProfile = "default"
+
+// Profile is the API or database rentalctl connects to, and the credentials
+// it authenticates with.
+type Profile struct {
+	// URL is the base URL of the API, eg. https://rental.mmess.dev.
+	URL string
+	// DatabaseURL is the URL of the database of the services, to serve the
+	// API in process when the API isn't reachable, eg. from a pod.
+	DatabaseURL string
+	// Username and Password are the credentials of a user, used unless APIKey
+	// is set.
+	Username string
+	Password string
+	// APIKey is the API key of a machine client.
+	APIKey string
+	// Tenant is the slug of the tenant to act on, the tenant of the
+	// credentials if empty.
+	Tenant string
+}
+
+// Direct reports whether the profile serves the API in process, over the
+// database.
+func (p Profile) Direct() bool {
+	return p.DatabaseURL != ""
+}
+
+// defaultConfigPath returns the path of the config file of rentalctl,
+// rentalctl/config.yaml in the user config directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "rentalctl", "config.yaml")
+}
+
+// loadProfile loads the profile name of the config file path, a YAML or TOML
+// file of profiles:
+//
+//	profiles:
+//	  prod:
+//	    url: https://rental.mmess.dev
+//	    username: support
+//	    password_file: ~/.rental/password
+//
+// The RENTALCTL_* environment variables, eg. RENTALCTL_PASSWORD, override the
+// settings of the profile, and the *_file settings read secrets from files.
+// The config file is optional, the profile being taken from the environment
+// when it is missing.
+func loadProfile(path, name string) (Profile, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	err := v.ReadInConfig()
+	var notFound viper.ConfigFileNotFoundError
+	if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.As(err, &notFound) {
+		return Profile{}, fmt.Errorf("Reading config %s: %w", path, err)
+	}
+	if name != defaultProfile && !v.IsSet("profiles."+name) {
+		return Profile{}, fmt.Errorf("Profile %q not found in %s", name, path)
+	}
+
+	profile := viper.New()
+	if settings := v.Sub("profiles." + name); settings != nil {
+		if err := profile.MergeConfigMap(settings.AllSettings()); err != nil {
+			return Profile{}, err
+		}
+	}
+	profile.SetEnvPrefix("rentalctl")
+	profile.AutomaticEnv()
+
+	password, err := secret(profile, "password")
+	if err != nil {
+		return Profile{}, err
+	}
+	apiKey, err := secret(profile, "api_key")
+	if err != nil {
+		return Profile{}, err
+	}
+	p := Profile{
+		URL:         strings.TrimSuffix(profile.GetString("url"), "/"),
+		DatabaseURL: profile.GetString("database_url"),
+		Username:    profile.GetString("username"),
+		Password:    password,
+		APIKey:      apiKey,
+		Tenant:      profile.GetString("tenant"),
+	}
+	if (p.URL == "") == (p.DatabaseURL == "") {
+		return Profile{}, fmt.Errorf("Profile %q must set either url or database_url", name)
+	}
+	if p.APIKey == "" && (p.Username == "" || p.Password == "") {
+		return Profile{}, fmt.Errorf("Profile %q must set either api_key or username and password", name)
+	}
+	return p, nil
+}
+
+// secret returns the setting key of profile, or the content of the file of
+// its key_file setting, so that secrets needn't be written in the config.
+func secret(profile *viper.Viper, key string) (string, error) {
+	if value := profile.GetString(key); value != "" {
+		return value, nil
+	}
+	path := profile.GetString(key + "_file")
+	if path == "" {
+		return "", nil
+	}
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, "~/") {
+		path = filepath.Join(home, path[2:])
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Reading %s_file: %w", key, err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
@@ -0,0 +1,390 @@
+// Command rentalctl manages the fleet and the customers of the rental
+// service, through its API or, from a pod, directly over its database.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+)
+
+// directBaseURL is the base URL of the API served in process.
+const directBaseURL = "http://rental.local"
+
+// command is a command of rentalctl, run with the arguments following its name.
+type command struct {
+	usage string
+	run   func(ctx context.Context, c *client, p printer, args []string) error
+}
+
+// commands are the commands of rentalctl, by name.
+var commands = map[string]command{
+	"car list": {
+		usage: "car list",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			if len(args) > 0 {
+				return errUsage
+			}
+			cars, err := c.ListCars(ctx)
+			if err != nil {
+				return err
+			}
+			return p.Cars(cars...)
+		},
+	},
+	"car get": {
+		usage: "car get ID...",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			cars, err := c.GetCars(ctx, ids)
+			if err != nil {
+				return err
+			}
+			return p.Cars(cars...)
+		},
+	},
+	"car create": {
+		usage: "car create -make MAKE -model MODEL -year YEAR",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			var create genv2.CreateUpdateCarRequest
+			flags := newFlagSet("car create")
+			flags.StringVar(&create.Make, "make", "", "make of the car")
+			flags.StringVar(&create.Model, "model", "", "model of the car")
+			flags.IntVar(&create.Year, "year", 0, "year of the car")
+			if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+				return errUsage
+			}
+			car, err := c.CreateCar(ctx, create)
+			if err != nil {
+				return err
+			}
+			return p.Cars(car)
+		},
+	},
+	"car update": {
+		usage: "car update [-make MAKE] [-model MODEL] [-year YEAR] ID",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			var patch genv2.CarPatch
+			flags := newFlagSet("car update")
+			flags.Func("make", "make of the car", func(value string) error {
+				patch.Make = &value
+				return nil
+			})
+			flags.Func("model", "model of the car", func(value string) error {
+				patch.Model = &value
+				return nil
+			})
+			flags.Func("year", "year of the car", func(value string) error {
+				year, err := strconv.Atoi(value)
+				patch.Year = &year
+				return err
+			})
+			if err := flags.Parse(args); err != nil {
+				return errUsage
+			}
+			id, err := parseID(flags.Args())
+			if err != nil {
+				return err
+			}
+			car, err := c.PatchCar(ctx, id, patch)
+			if err != nil {
+				return err
+			}
+			return p.Cars(car)
+		},
+	},
+	"car delete": {
+		usage: "car delete ID",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			id, err := parseID(args)
+			if err != nil {
+				return err
+			}
+			return c.DeleteCar(ctx, id)
+		},
+	},
+	"car import": {
+		usage: "car import [-mode atomic|best_effort] [-format csv|ndjson] FILE",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			flags := newFlagSet("car import")
+			mode := flags.String("mode", "atomic", "whether to import nothing, atomic, or the valid rows, best_effort, when some rows are invalid")
+			format := flags.String("format", "", "format of the file, taken from its extension by default")
+			if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+				return errUsage
+			}
+			path := flags.Arg(0)
+			if *format == "" {
+				*format = strings.TrimPrefix(filepath.Ext(path), ".")
+			}
+			contentType, ok := map[string]string{"csv": contentTypeCSV, "ndjson": contentTypeNDJSON, "jsonl": contentTypeNDJSON}[*format]
+			if !ok {
+				return fmt.Errorf("Unknown format %q, set -format to csv or ndjson", *format)
+			}
+			var file io.Reader = os.Stdin
+			if path != "-" {
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				file = f
+			}
+			report, err := c.ImportCars(ctx, file, contentType, *mode)
+			if printErr := p.ImportReport(report); printErr != nil {
+				return printErr
+			}
+			return err
+		},
+	},
+	"customer get": {
+		usage: "customer get ID...",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			customers, err := c.GetCustomers(ctx, ids)
+			if err != nil {
+				return err
+			}
+			return p.Customers(customers...)
+		},
+	},
+	"customer create": {
+		usage: "customer create -name NAME",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			var create genv2.CreateUpdateCustomerRequest
+			flags := newFlagSet("customer create")
+			flags.StringVar(&create.Name, "name", "", "name of the customer")
+			if err := flags.Parse(args); err != nil || flags.NArg() > 0 {
+				return errUsage
+			}
+			customer, err := c.CreateCustomer(ctx, create)
+			if err != nil {
+				return err
+			}
+			return p.Customers(customer)
+		},
+	},
+	"customer update": {
+		usage: "customer update [-name NAME] ID",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			var patch genv2.CustomerPatch
+			flags := newFlagSet("customer update")
+			flags.Func("name", "name of the customer", func(value string) error {
+				patch.Name = &value
+				return nil
+			})
+			if err := flags.Parse(args); err != nil {
+				return errUsage
+			}
+			id, err := parseID(flags.Args())
+			if err != nil {
+				return err
+			}
+			customer, err := c.PatchCustomer(ctx, id, patch)
+			if err != nil {
+				return err
+			}
+			return p.Customers(customer)
+		},
+	},
+	"customer delete": {
+		usage: "customer delete ID",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			id, err := parseID(args)
+			if err != nil {
+				return err
+			}
+			return c.DeleteCustomer(ctx, id)
+		},
+	},
+	"customer rentals": {
+		usage: "customer rentals ID",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			id, err := parseID(args)
+			if err != nil {
+				return err
+			}
+			cars, err := c.ListCustomerRentals(ctx, id)
+			if err != nil {
+				return err
+			}
+			return p.Cars(cars...)
+		},
+	},
+	"rent": {
+		usage: "rent CAR_ID CUSTOMER_ID",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil || len(ids) != 2 {
+				return errUsage
+			}
+			r, err := c.Rent(ctx, ids[0], ids[1])
+			if err != nil {
+				return err
+			}
+			return p.Rental(r)
+		},
+	},
+	"return": {
+		usage: "return RENTAL_ID",
+		run: func(ctx context.Context, c *client, p printer, args []string) error {
+			id, err := parseID(args)
+			if err != nil {
+				return err
+			}
+			r, err := c.Return(ctx, id)
+			if err != nil {
+				return err
+			}
+			return p.Rental(r)
+		},
+	},
+}
+
+// errUsage is returned by the commands run with invalid arguments.
+var errUsage = fmt.Errorf("Invalid arguments")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "rentalctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run runs rentalctl with args, printing to stdout, and the usage to stderr.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("rentalctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", defaultConfigPath(), "path of the config file of the profiles")
+	profileName := flags.String("profile", defaultProfile, "profile of the config file to use, overridden by RENTALCTL_PROFILE")
+	output := flags.String("output", outputTable, "output format, table or json")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: rentalctl [flags] command\n\ncommands:")
+		usages := make([]string, 0, len(commands))
+		for _, cmd := range commands {
+			usages = append(usages, cmd.usage)
+		}
+		sort.Strings(usages)
+		for _, usage := range usages {
+			fmt.Fprintf(stderr, "  %s\n", usage)
+		}
+		fmt.Fprintln(stderr, "\nflags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *output != outputTable && *output != outputJSON {
+		return fmt.Errorf("Unknown output %q, want table or json", *output)
+	}
+	if profile := os.Getenv("RENTALCTL_PROFILE"); profile != "" {
+		*profileName = profile
+	}
+
+	args = flags.Args()
+	name, cmd, ok := lookupCommand(args)
+	if !ok {
+		flags.Usage()
+		return fmt.Errorf("Unknown command %q", strings.Join(args, " "))
+	}
+	args = args[len(strings.Fields(name)):]
+
+	profile, err := loadProfile(*configPath, *profileName)
+	if err != nil {
+		return err
+	}
+	c, closeClient, err := connect(profile)
+	if err != nil {
+		return err
+	}
+	defer closeClient()
+
+	err = cmd.run(ctx, c, printer{w: stdout, format: *output}, args)
+	if err == errUsage {
+		return fmt.Errorf("%w, usage: rentalctl %s", err, cmd.usage)
+	}
+	return err
+}
+
+// lookupCommand returns the command named by the first words of args.
+func lookupCommand(args []string) (string, command, bool) {
+	for n := 2; n > 0; n-- {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		if cmd, ok := commands[name]; ok {
+			return name, cmd, true
+		}
+	}
+	return "", command{}, false
+}
+
+// connect returns a client of the API of profile, and a function releasing it.
+func connect(profile Profile) (*client, func() error, error) {
+	if !profile.Direct() {
+		return newClient(profile.URL, http.DefaultTransport, profile), func() error { return nil }, nil
+	}
+	server, closeServer, err := newDirectServer(profile.DatabaseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	handler, err := newDirectHandler(server)
+	if err != nil {
+		closeServer()
+		return nil, nil, err
+	}
+	return newClient(directBaseURL, handlerTransport{handler: handler}, profile), closeServer, nil
+}
+
+// newFlagSet returns the flag set of the command name.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+// parseID returns the ID of args, which must hold a single ID.
+func parseID(args []string) (int64, error) {
+	if len(args) != 1 {
+		return 0, errUsage
+	}
+	ids, err := parseIDs(args)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// parseIDs returns the IDs of args, which must hold at least one ID.
+func parseIDs(args []string) ([]int64, error) {
+	if len(args) == 0 {
+		return nil, errUsage
+	}
+	ids := make([]int64, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("Invalid ID %q", arg)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// setupAPI serves the API over mock services, with an operator user
+// support:hunter22, the car 1 and the customer 1 in the default tenant, and
+// selects it with the environment.
+func setupAPI(t *testing.T) *httptest.Server {
+	ctx := rental.WithTenant(context.Background(), rental.DefaultTenantID)
+	users := mock.NewMockUserCRUDService()
+	operator := rental.User{Username: "support", Role: rental.RoleOperator}
+	if err := operator.SetPassword("hunter22"); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	users.Create(ctx, operator)
+	cars := mock.NewMockCarCRUDService()
+	cars.Create(ctx, rental.Car{ID: 1, Make: "Renault", Model: "Clio", Year: 2020})
+	customers := mock.NewMockCustomerCRUDService()
+	customers.Create(ctx, rental.Customer{ID: 1, Name: "Ada"})
+	server := api.NewServer(cars, customers, mock.NewMockRentalService(), users, mock.NewMockAPIKeyService(), mock.NewMockAuditLogService(), mock.NewMockTenantService(), mock.NewMockTransactor())
+	handler, err := newDirectHandler(server)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	t.Setenv("RENTALCTL_URL", ts.URL)
+	t.Setenv("RENTALCTL_USERNAME", "support")
+	t.Setenv("RENTALCTL_PASSWORD", "hunter22")
+	return ts
+}
+
+// runCommand runs rentalctl with args and a missing config file, and returns its output.
+func runCommand(t *testing.T, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-config", filepath.Join(t.TempDir(), "config.yaml")}, args...)
+	err := run(context.Background(), args, &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestRun(t *testing.T) {
+	// Setup
+
+	setupAPI(t)
+
+	// Test
+
+	if _, err := runCommand(t, "car", "update", "-year", "2021", "1"); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	out, err := runCommand(t, "-output", "json", "rent", "1", "1")
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	var r genv2.Rental
+	if err := json.Unmarshal([]byte(out), &r); err != nil {
+		t.Fatalf("got error %v, want JSON rental", err)
+	}
+	if r.CarId != 1 || r.CustomerId != 1 {
+		t.Errorf("got rental of car %d by customer %d, want car 1 by customer 1", r.CarId, r.CustomerId)
+	}
+
+	out, err = runCommand(t, "car", "list")
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || strings.Join(strings.Fields(lines[1]), " ") != "1 Renault Clio 2021 1" {
+		t.Errorf("got table %q, want car 1 updated and rented by customer 1", out)
+	}
+
+	if _, err := runCommand(t, "rent", "1", "1"); err == nil || !strings.Contains(err.Error(), "Conflict") {
+		t.Errorf("got error %v renting a rented car, want conflict", err)
+	}
+	if _, err := runCommand(t, "return", "1"); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if _, err := runCommand(t, "car", "delete", "1"); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if out, _ := runCommand(t, "-output", "json", "car", "get", "1"); strings.TrimSpace(out) != "[]" {
+		t.Errorf("got cars %s, want car 1 deleted", out)
+	}
+	fleet := filepath.Join(t.TempDir(), "fleet.csv")
+	os.WriteFile(fleet, []byte("make,model,year\nPeugeot,208,2019\nPeugeot,,2019\n"), 0600)
+	out, err = runCommand(t, "car", "import", "-mode", "best_effort", fleet)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if !strings.HasPrefix(out, "1 cars imported") || !strings.Contains(out, "3 ") {
+		t.Errorf("got import report %q, want 1 car imported and line 3 invalid", out)
+	}
+
+	if _, err := runCommand(t, "car", "get", "car-1"); err == nil {
+		t.Errorf("got nil error for an invalid ID, want error")
+	}
+	if _, err := runCommand(t, "car", "sell", "1"); err == nil {
+		t.Errorf("got nil error for an unknown command, want error")
+	}
+}
+
+func TestRun_Unauthorized(t *testing.T) {
+	setupAPI(t)
+	t.Setenv("RENTALCTL_PASSWORD", "wrong")
+
+	_, err := runCommand(t, "car", "list")
+	if apiErr, ok := err.(*apiError); !ok || apiErr.Status != 401 {
+		t.Errorf("got error %v, want 401 Unauthorized", err)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	// Setup
+
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	os.WriteFile(passwordFile, []byte("hunter22\n"), 0600)
+	config := filepath.Join(dir, "config.yaml")
+	os.WriteFile(config, []byte(`profiles:
+  prod:
+    url: https://rental.mmess.dev/
+    username: support
+    password_file: `+passwordFile+`
+    tenant: lyon
+  pod:
+    database_url: postgres://rental@localhost:5432/rental
+    api_key: rk_123
+`), 0600)
+
+	// Test
+
+	got, err := loadProfile(config, "prod")
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	want := Profile{URL: "https://rental.mmess.dev", Username: "support", Password: "hunter22", Tenant: "lyon"}
+	if got != want {
+		t.Errorf("got profile %+v, want %+v", got, want)
+	}
+
+	t.Setenv("RENTALCTL_API_KEY", "rk_456")
+	got, err = loadProfile(config, "pod")
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if !got.Direct() || got.APIKey != "rk_456" {
+		t.Errorf("got profile %+v, want direct profile with the API key of the environment", got)
+	}
+
+	if _, err := loadProfile(config, "staging"); err == nil {
+		t.Errorf("got nil error for a missing profile, want error")
+	}
+}
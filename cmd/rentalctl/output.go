@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+)
+
+// Output formats.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer prints the entities returned by the API, as a table or as JSON.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// print prints v, whose table has a row of columns for each entity.
+func (p printer) print(v interface{}, header []string, rows [][]interface{}) error {
+	if p.format == outputJSON {
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	for i, column := range header {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, column)
+	}
+	fmt.Fprintln(w)
+	for _, row := range rows {
+		for i, column := range row {
+			if i > 0 {
+				fmt.Fprint(w, "\t")
+			}
+			fmt.Fprint(w, column)
+		}
+		fmt.Fprintln(w)
+	}
+	return w.Flush()
+}
+
+// Cars prints cars.
+func (p printer) Cars(cars ...genv2.Car) error {
+	rows := make([][]interface{}, len(cars))
+	for i, car := range cars {
+		renter := "-"
+		if car.RenterId != 0 {
+			renter = fmt.Sprint(car.RenterId)
+		}
+		rows[i] = []interface{}{car.Id, car.Make, car.Model, car.Year, renter}
+	}
+	return p.print(cars, []string{"ID", "MAKE", "MODEL", "YEAR", "RENTER"}, rows)
+}
+
+// Customers prints customers.
+func (p printer) Customers(customers ...genv2.Customer) error {
+	rows := make([][]interface{}, len(customers))
+	for i, customer := range customers {
+		rows[i] = []interface{}{customer.Id, customer.Name}
+	}
+	return p.print(customers, []string{"ID", "NAME"}, rows)
+}
+
+// Rental prints a rental.
+func (p printer) Rental(r genv2.Rental) error {
+	returned := "-"
+	if r.ReturnedAt != nil {
+		returned = r.ReturnedAt.Format(time.RFC3339)
+	}
+	rows := [][]interface{}{{r.Id, r.CarId, r.CustomerId, r.StartedAt.Format(time.RFC3339), returned}}
+	return p.print(r, []string{"ID", "CAR", "CUSTOMER", "STARTED", "RETURNED"}, rows)
+}
+
+// ImportReport prints the report of an import, with a row for each invalid
+// row of the file.
+func (p printer) ImportReport(report genv2.CarImportReport) error {
+	if p.format != outputJSON {
+		fmt.Fprintf(p.w, "%d cars imported\n", report.Imported)
+		if len(report.Errors) == 0 {
+			return nil
+		}
+	}
+	rows := make([][]interface{}, len(report.Errors))
+	for i, err := range report.Errors {
+		rows[i] = []interface{}{err.Line, err.Message}
+	}
+	return p.print(report, []string{"LINE", "ERROR"}, rows)
+}
@@ -0,0 +1,27 @@
+-- CREATE append-only audit_log table with actor, action, entity, before/after states and diff columns
+BEGIN;
+CREATE TABLE audit_log (
+    id serial PRIMARY KEY,
+    actor varchar(255) NOT NULL,
+    action varchar(255) NOT NULL,
+    entity_type varchar(255) NOT NULL,
+    entity_id integer NOT NULL,
+    before jsonb,
+    after jsonb,
+    diff jsonb NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX audit_log_entity_idx ON audit_log (entity_type, entity_id);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+
+-- Reject any attempt to rewrite history
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER audit_log_no_update_delete BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+COMMIT;
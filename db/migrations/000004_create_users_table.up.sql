@@ -0,0 +1,9 @@
+-- CREATE users table with id, username, password_hash, role columns
+BEGIN;
+CREATE TABLE users (
+    id serial PRIMARY KEY,
+    username varchar(255) NOT NULL UNIQUE,
+    password_hash varchar(255) NOT NULL,
+    role varchar(32) NOT NULL CHECK (role IN ('admin', 'agent', 'read-only'))
+);
+COMMIT;
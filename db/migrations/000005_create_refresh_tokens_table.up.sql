@@ -0,0 +1,13 @@
+-- CREATE refresh_tokens table with id, user_id foreign key, token_hash, scope, expiry and revocation columns
+BEGIN;
+CREATE TABLE refresh_tokens (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL,
+    token_hash char(64) NOT NULL UNIQUE,
+    scope text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    revoked_at timestamptz,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+COMMIT;
@@ -0,0 +1,15 @@
+-- CREATE api_keys table with id, name, prefix, key_hash, scope, expiry, last use and revocation columns
+BEGIN;
+CREATE TABLE api_keys (
+    id serial PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    prefix varchar(16) NOT NULL,
+    key_hash char(64) NOT NULL UNIQUE,
+    scope text NOT NULL,
+    created_by varchar(255) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz,
+    last_used_at timestamptz,
+    revoked_at timestamptz
+);
+COMMIT;
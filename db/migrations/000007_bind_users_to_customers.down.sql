@@ -0,0 +1,9 @@
+-- Customer users can't be kept without their customer binding
+BEGIN;
+DROP INDEX cars_customer_id_idx;
+DELETE FROM users WHERE role = 'customer';
+ALTER TABLE users DROP CONSTRAINT users_customer_id_check;
+ALTER TABLE users DROP COLUMN customer_id;
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'agent', 'read-only'));
+COMMIT;
@@ -0,0 +1,8 @@
+-- ALTER users table to add the customer role, bound to a customer through the customer_id foreign key
+BEGIN;
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'agent', 'read-only', 'customer'));
+ALTER TABLE users ADD COLUMN customer_id integer REFERENCES customers (id) ON DELETE CASCADE;
+ALTER TABLE users ADD CONSTRAINT users_customer_id_check CHECK ((role = 'customer') = (customer_id IS NOT NULL));
+CREATE INDEX cars_customer_id_idx ON cars (customer_id);
+COMMIT;
@@ -0,0 +1,16 @@
+-- Operators can't be kept without tenants
+BEGIN;
+UPDATE users SET role = 'admin' WHERE role = 'operator';
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'agent', 'read-only', 'customer'));
+ALTER TABLE users DROP CONSTRAINT users_customer_tenant_fkey;
+ALTER TABLE cars DROP CONSTRAINT cars_customer_tenant_fkey;
+ALTER TABLE customers DROP CONSTRAINT customers_id_tenant_id_key;
+ALTER TABLE audit_log DROP COLUMN tenant_id;
+ALTER TABLE refresh_tokens DROP COLUMN tenant_id;
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+ALTER TABLE users DROP COLUMN tenant_id;
+ALTER TABLE cars DROP COLUMN tenant_id;
+ALTER TABLE customers DROP COLUMN tenant_id;
+DROP TABLE tenants;
+COMMIT;
@@ -0,0 +1,39 @@
+-- CREATE tenants table, and scope customers, cars, users, api_keys, refresh_tokens and audit_log to a tenant.
+-- Existing rows are assigned to the default tenant.
+BEGIN;
+CREATE TABLE tenants (
+    id serial PRIMARY KEY,
+    slug varchar(63) NOT NULL UNIQUE,
+    name varchar(255) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+INSERT INTO tenants (id, slug, name) VALUES (1, 'default', 'Default');
+SELECT setval('tenants_id_seq', 1);
+
+ALTER TABLE customers ADD COLUMN tenant_id integer NOT NULL DEFAULT 1 REFERENCES tenants (id);
+ALTER TABLE cars ADD COLUMN tenant_id integer NOT NULL DEFAULT 1 REFERENCES tenants (id);
+ALTER TABLE users ADD COLUMN tenant_id integer NOT NULL DEFAULT 1 REFERENCES tenants (id);
+ALTER TABLE api_keys ADD COLUMN tenant_id integer NOT NULL DEFAULT 1 REFERENCES tenants (id);
+ALTER TABLE refresh_tokens ADD COLUMN tenant_id integer NOT NULL DEFAULT 1 REFERENCES tenants (id);
+ALTER TABLE audit_log ADD COLUMN tenant_id integer NOT NULL DEFAULT 1 REFERENCES tenants (id);
+ALTER TABLE customers ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE cars ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE refresh_tokens ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE audit_log ALTER COLUMN tenant_id DROP DEFAULT;
+
+CREATE INDEX customers_tenant_id_idx ON customers (tenant_id);
+CREATE INDEX cars_tenant_id_idx ON cars (tenant_id);
+CREATE INDEX users_tenant_id_idx ON users (tenant_id);
+CREATE INDEX api_keys_tenant_id_idx ON api_keys (tenant_id);
+CREATE INDEX audit_log_tenant_id_idx ON audit_log (tenant_id, id);
+
+-- Cars can only be rented by, and users only bound to, customers of their own tenant
+ALTER TABLE customers ADD CONSTRAINT customers_id_tenant_id_key UNIQUE (id, tenant_id);
+ALTER TABLE cars ADD CONSTRAINT cars_customer_tenant_fkey FOREIGN KEY (customer_id, tenant_id) REFERENCES customers (id, tenant_id);
+ALTER TABLE users ADD CONSTRAINT users_customer_tenant_fkey FOREIGN KEY (customer_id, tenant_id) REFERENCES customers (id, tenant_id) ON DELETE CASCADE;
+
+ALTER TABLE users DROP CONSTRAINT users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('operator', 'admin', 'agent', 'read-only', 'customer'));
+COMMIT;
@@ -0,0 +1,11 @@
+-- CREATE rate_limit_buckets table with key, tokens, update time and the time the bucket is full again.
+-- Buckets are transient, the table is unlogged as losing it on a crash only resets the budgets.
+BEGIN;
+CREATE UNLOGGED TABLE rate_limit_buckets (
+    key varchar(255) PRIMARY KEY,
+    tokens double precision NOT NULL,
+    updated_at timestamptz NOT NULL,
+    full_at timestamptz NOT NULL
+);
+CREATE INDEX rate_limit_buckets_full_at_idx ON rate_limit_buckets (full_at);
+COMMIT;
@@ -0,0 +1,17 @@
+-- CREATE idempotency_keys table with the key of each client, the request fingerprint, the recorded response and expiry columns
+BEGIN;
+CREATE TABLE idempotency_keys (
+    id serial PRIMARY KEY,
+    tenant_id integer NOT NULL REFERENCES tenants (id),
+    client varchar(255) NOT NULL,
+    key varchar(255) NOT NULL,
+    fingerprint char(64) NOT NULL,
+    status_code integer,
+    response_headers jsonb,
+    response_body bytea,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz NOT NULL,
+    UNIQUE (tenant_id, client, key)
+);
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+COMMIT;
@@ -0,0 +1,5 @@
+-- DROP version column from cars and customers
+BEGIN;
+ALTER TABLE customers DROP COLUMN version;
+ALTER TABLE cars DROP COLUMN version;
+COMMIT;
@@ -0,0 +1,5 @@
+-- ADD version column to cars and customers, incremented by each update for optimistic concurrency control
+BEGIN;
+ALTER TABLE cars ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE customers ADD COLUMN version integer NOT NULL DEFAULT 1;
+COMMIT;
@@ -0,0 +1,18 @@
+-- CREATE rentals table recording the rentals of cars, and the active rentals of the cars currently rented.
+-- Cars and customers with rentals can't be deleted, so that their history is kept
+BEGIN;
+CREATE TABLE rentals (
+    id serial PRIMARY KEY,
+    tenant_id integer NOT NULL REFERENCES tenants (id),
+    car_id integer NOT NULL REFERENCES cars (id) ON DELETE RESTRICT,
+    customer_id integer NOT NULL REFERENCES customers (id) ON DELETE RESTRICT,
+    started_at timestamp with time zone NOT NULL DEFAULT now(),
+    returned_at timestamp with time zone,
+    CHECK (returned_at IS NULL OR returned_at >= started_at)
+);
+-- A car has at most one active rental
+CREATE UNIQUE INDEX rentals_active_car_id_idx ON rentals (car_id) WHERE returned_at IS NULL;
+CREATE INDEX rentals_customer_id_idx ON rentals (customer_id);
+INSERT INTO rentals (tenant_id, car_id, customer_id)
+    SELECT tenant_id, id, customer_id FROM cars WHERE customer_id IS NOT NULL;
+COMMIT;
@@ -0,0 +1,11 @@
+// Package migrations embeds the migrations of the database schema, so that
+// the binary applies them without shipping the SQL files.
+package migrations
+
+import "embed"
+
+// FS holds the migrations, named after golang-migrate's conventions, eg.
+// 000001_create_customers_table.up.sql.
+//
+//go:embed *.sql
+var FS embed.FS
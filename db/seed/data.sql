@@ -1,17 +0,0 @@
--- Seed cars table
-INSERT INTO cars (id, make, model, year)
-VALUES (1, 'Toyota', 'Corolla', 2000),
-    (2, 'Ford', 'Focus', 2005),
-    (3, 'Honda', 'Civic', 2010),
-    (4, 'Nissan', 'Sentra', 2015),
-    (5, 'Ford', 'Mustang', 2016),
-    (6, 'Toyota', 'Camry', 2017),
-    (7, 'Honda', 'Accord', 2018),
-    (8, 'Nissan', 'Versa', 2019),
-    (9, 'Ford', 'F150', 2020),
-    (10, 'Toyota', 'Prius', 2021);
--- Seed customers table
-INSERT INTO customers (id, name)
-VALUES (1, 'John Doe'),
-    (2, 'Cookie Doe'),
-    (3, 'Pizza Doe');
@@ -0,0 +1,83 @@
+// Package mock provides mock implementations of stateful services.
+package mock
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+type MockAPIKeyService struct {
+	keys map[int]*rental.APIKey
+}
+
+// get returns the API key id if it belongs to the tenant of ctx.
+func (m *MockAPIKeyService) get(ctx context.Context, id int) (*rental.APIKey, bool) {
+	tenantID, _ := rental.TenantFrom(ctx)
+	key, ok := m.keys[id]
+	return key, ok && key.TenantID == tenantID
+}
+
+// Create stores an API key in the Mock state and returns the id.
+func (m *MockAPIKeyService) Create(ctx context.Context, key rental.APIKey) (id int, err error) {
+	key.ID = len(m.keys) + 1
+	key.TenantID, _ = rental.TenantFrom(ctx)
+	key.CreatedAt = time.Now()
+	m.keys[key.ID] = &key
+	return key.ID, nil
+}
+
+// Get fetches an API key from the Mock state.
+func (m *MockAPIKeyService) Get(ctx context.Context, id int) (rental.APIKey, error) {
+	key, ok := m.get(ctx, id)
+	if !ok {
+		return rental.APIKey{}, rental.ErrAPIKeyNotFound
+	}
+	return *key, nil
+}
+
+// GetByHash fetches an API key from the Mock state by hash, whatever its tenant.
+func (m *MockAPIKeyService) GetByHash(ctx context.Context, keyHash string) (rental.APIKey, error) {
+	for _, key := range m.keys {
+		if key.KeyHash == keyHash {
+			return *key, nil
+		}
+	}
+	return rental.APIKey{}, rental.ErrAPIKeyNotFound
+}
+
+// List fetches all API keys from the Mock state, ordered by id.
+func (m *MockAPIKeyService) List(ctx context.Context) ([]rental.APIKey, error) {
+	keys := make([]rental.APIKey, 0, len(m.keys))
+	for id := range m.keys {
+		if key, ok := m.get(ctx, id); ok {
+			keys = append(keys, *key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+// Revoke revokes an API key in the Mock state.
+func (m *MockAPIKeyService) Revoke(ctx context.Context, id int) error {
+	if key, ok := m.get(ctx, id); ok && !key.RevokedAt.Valid {
+		key.RevokedAt = null.TimeFrom(time.Now())
+	}
+	return nil
+}
+
+// Touch records the last use of an API key in the Mock state.
+func (m *MockAPIKeyService) Touch(ctx context.Context, id int, usedAt time.Time) error {
+	if key, ok := m.get(ctx, id); ok {
+		key.LastUsedAt = null.TimeFrom(usedAt)
+	}
+	return nil
+}
+
+// NewMockAPIKeyService returns a new MockAPIKeyService.
+func NewMockAPIKeyService() *MockAPIKeyService {
+	return &MockAPIKeyService{keys: map[int]*rental.APIKey{}}
+}
@@ -0,0 +1,52 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestMockAPIKeyService_GetByHash(t *testing.T) {
+	t.Run("get API key", func(t *testing.T) {
+		apiKey := rental.APIKey{Name: "billing", KeyHash: "a3f1c2", Scope: "cars:read"}
+		mockAPIKeyService := NewMockAPIKeyService()
+		id, err := mockAPIKeyService.Create(context.Background(), apiKey)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		got, err := mockAPIKeyService.GetByHash(context.Background(), apiKey.KeyHash)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got.ID != id || got.Name != apiKey.Name {
+			t.Errorf("got %v, want API key %d named %s", got, id, apiKey.Name)
+		}
+	})
+	t.Run("get non-existent API key", func(t *testing.T) {
+		mockAPIKeyService := NewMockAPIKeyService()
+		_, err := mockAPIKeyService.GetByHash(context.Background(), "a3f1c2")
+		if err != rental.ErrAPIKeyNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrAPIKeyNotFound)
+		}
+	})
+}
+
+func TestMockAPIKeyService_Revoke(t *testing.T) {
+	mockAPIKeyService := NewMockAPIKeyService()
+	id, err := mockAPIKeyService.Create(context.Background(), rental.APIKey{Name: "billing", KeyHash: "a3f1c2", Scope: "cars:read"})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if err := mockAPIKeyService.Revoke(context.Background(), id); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	got, err := mockAPIKeyService.Get(context.Background(), id)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if got.Valid(time.Now()) {
+		t.Errorf("got valid API key, want revoked API key")
+	}
+}
@@ -0,0 +1,62 @@
+// Package mock provides mock implementations of stateful services.
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+type MockAuditLogService struct {
+	entries []rental.AuditEntry
+	tenants []int // Tenant of each entry
+}
+
+// Record appends an entry to the Mock state and returns the id.
+func (m *MockAuditLogService) Record(ctx context.Context, entry rental.AuditEntry) (id int, err error) {
+	tenantID, _ := rental.TenantFrom(ctx)
+	entry.ID = len(m.entries) + 1
+	entry.CreatedAt = time.Now()
+	m.entries = append(m.entries, entry)
+	m.tenants = append(m.tenants, tenantID)
+	return entry.ID, nil
+}
+
+// RecordMany appends entries to the Mock state.
+func (m *MockAuditLogService) RecordMany(ctx context.Context, entries []rental.AuditEntry) error {
+	for _, entry := range entries {
+		if _, err := m.Record(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List fetches the entries matching filter from the Mock state, most recent first.
+func (m *MockAuditLogService) List(ctx context.Context, filter rental.AuditFilter) ([]rental.AuditEntry, error) {
+	tenantID, _ := rental.TenantFrom(ctx)
+	entries := []rental.AuditEntry{}
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		entry := m.entries[i]
+		if m.tenants[i] != tenantID ||
+			(filter.Actor != "" && entry.Actor != filter.Actor) ||
+			(filter.Action != "" && entry.Action != filter.Action) ||
+			(filter.EntityType != "" && entry.EntityType != filter.EntityType) ||
+			(filter.EntityID != 0 && entry.EntityID != filter.EntityID) ||
+			(!filter.Since.IsZero() && entry.CreatedAt.Before(filter.Since)) ||
+			(!filter.Until.IsZero() && !entry.CreatedAt.Before(filter.Until)) {
+			continue
+		}
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// NewMockAuditLogService returns a new MockAuditLogService.
+func NewMockAuditLogService() *MockAuditLogService {
+	return &MockAuditLogService{}
+}
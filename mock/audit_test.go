@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestMockAuditLogService_Record(t *testing.T) {
+	entry := rental.AuditEntry{Actor: "rental", Action: rental.AuditActionCreate, EntityType: rental.AuditEntityCar, EntityID: 1}
+	mockAuditLogService := NewMockAuditLogService()
+	id, err := mockAuditLogService.Record(context.Background(), entry)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	got, err := mockAuditLogService.List(context.Background(), rental.AuditFilter{})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 1 || got[0].ID != id || got[0].Actor != entry.Actor {
+		t.Errorf("got %v, want a single entry %d recorded by %s", got, id, entry.Actor)
+	}
+}
+
+func TestMockAuditLogService_RecordMany(t *testing.T) {
+	entries := []rental.AuditEntry{
+		{Actor: "rental", Action: rental.AuditActionCreate, EntityType: rental.AuditEntityCar, EntityID: 1},
+		{Actor: "rental", Action: rental.AuditActionCreate, EntityType: rental.AuditEntityCar, EntityID: 2},
+	}
+	mockAuditLogService := NewMockAuditLogService()
+	if err := mockAuditLogService.RecordMany(context.Background(), entries); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	got, err := mockAuditLogService.List(context.Background(), rental.AuditFilter{})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d entries, want 2", len(got))
+	}
+}
+
+func TestMockAuditLogService_List(t *testing.T) {
+	mockAuditLogService := NewMockAuditLogService()
+	for _, entityID := range []int{1, 2, 2} {
+		entry := rental.AuditEntry{Actor: "rental", Action: rental.AuditActionUpdate, EntityType: rental.AuditEntityCar, EntityID: entityID}
+		if _, err := mockAuditLogService.Record(context.Background(), entry); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	}
+	t.Run("filter by entity", func(t *testing.T) {
+		got, err := mockAuditLogService.List(context.Background(), rental.AuditFilter{EntityType: rental.AuditEntityCar, EntityID: 2})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(got) != 2 {
+			t.Errorf("got %d entries, want 2", len(got))
+		}
+	})
+	t.Run("limit results", func(t *testing.T) {
+		got, err := mockAuditLogService.List(context.Background(), rental.AuditFilter{Limit: 1})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(got) != 1 || got[0].ID != 3 {
+			t.Errorf("got %v, want the most recent entry", got)
+		}
+	})
+}
@@ -1,43 +1,152 @@
 // Package mock provides mock implementations of stateful services.
 package mock
 
-import "github.com/shidenkai0/rental/pkg/rental"
+import (
+	"context"
+	"sort"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
 
 type MockCarCRUDService struct {
-	cars map[int]*rental.Car
+	cars    map[int]*rental.Car
+	tenants map[int]int // Tenant of each car
+}
+
+// owns returns true if the car carID exists in the Mock state and belongs to the tenant of ctx.
+func (m *MockCarCRUDService) owns(ctx context.Context, carID int) bool {
+	tenantID, _ := rental.TenantFrom(ctx)
+	_, ok := m.cars[carID]
+	return ok && m.tenants[carID] == tenantID
 }
 
 // Create creates a car in the Mock state and returns the id.
-func (m *MockCarCRUDService) Create(car rental.Car) (id int, err error) {
+func (m *MockCarCRUDService) Create(ctx context.Context, car rental.Car) (id int, err error) {
 	if _, ok := m.cars[car.ID]; ok {
 		return 0, rental.ErrCarAlreadyExists
 	}
+	tenantID, _ := rental.TenantFrom(ctx)
 	m.cars[car.ID] = &car
+	m.tenants[car.ID] = tenantID
 	return car.ID, nil
 }
 
+// CreateMany creates cars in the Mock state, with ids following the highest id.
+func (m *MockCarCRUDService) CreateMany(ctx context.Context, cars []rental.Car) ([]int, error) {
+	lastID := 0
+	for id := range m.cars {
+		if id > lastID {
+			lastID = id
+		}
+	}
+	ids := make([]int, len(cars))
+	for i, car := range cars {
+		lastID++
+		car.ID = lastID
+		if _, err := m.Create(ctx, car); err != nil {
+			return nil, err
+		}
+		ids[i] = car.ID
+	}
+	return ids, nil
+}
+
 // Get fetches a car from the Mock state.
-func (m *MockCarCRUDService) Get(id int) (rental.Car, error) {
-	car, ok := m.cars[id]
-	if !ok {
+func (m *MockCarCRUDService) Get(ctx context.Context, id int) (rental.Car, error) {
+	if !m.owns(ctx, id) {
 		return rental.Car{}, rental.ErrCarNotFound
 	}
-	return *car, nil
+	return *m.cars[id], nil
+}
+
+// ForEach calls fn with the cars of the Mock state, ordered by id.
+func (m *MockCarCRUDService) ForEach(ctx context.Context, fn func(car rental.Car) error) error {
+	cars := []rental.Car{}
+	for id, car := range m.cars {
+		if m.owns(ctx, id) {
+			cars = append(cars, *car)
+		}
+	}
+	sort.Slice(cars, func(i, j int) bool { return cars[i].ID < cars[j].ID })
+	for _, car := range cars {
+		if err := fn(car); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListRentedBy fetches the cars rented by a customer from the Mock state, ordered by id.
+func (m *MockCarCRUDService) ListRentedBy(ctx context.Context, customerID int) ([]rental.Car, error) {
+	cars := []rental.Car{}
+	for id, car := range m.cars {
+		if m.owns(ctx, id) && car.RenterID() == customerID {
+			cars = append(cars, *car)
+		}
+	}
+	sort.Slice(cars, func(i, j int) bool { return cars[i].ID < cars[j].ID })
+	return cars, nil
+}
+
+// GetMany fetches the cars of ids from the Mock state, ordered by id.
+func (m *MockCarCRUDService) GetMany(ctx context.Context, ids []int) ([]rental.Car, error) {
+	cars := []rental.Car{}
+	found := map[int]bool{}
+	for _, id := range ids {
+		if m.owns(ctx, id) && !found[id] {
+			found[id] = true
+			cars = append(cars, *m.cars[id])
+		}
+	}
+	sort.Slice(cars, func(i, j int) bool { return cars[i].ID < cars[j].ID })
+	return cars, nil
 }
 
-// Update updates a car in the Mock state.
-func (m *MockCarCRUDService) Update(car rental.Car) error {
+// Update updates a car in the Mock state if it is still at car.Version, and increments its version.
+func (m *MockCarCRUDService) Update(ctx context.Context, car rental.Car) error {
+	if !m.owns(ctx, car.ID) {
+		return rental.ErrCarNotFound
+	}
+	if m.cars[car.ID].Version != car.Version {
+		return rental.ErrCarVersionMismatch
+	}
+	car.Version++
 	m.cars[car.ID] = &car
 	return nil
 }
 
-// Delete deletes a car from the Mock state.
-func (m *MockCarCRUDService) Delete(carID int) error {
+// Patch updates the changed details of a car in the Mock state if it is still at version, and increments its version.
+func (m *MockCarCRUDService) Patch(ctx context.Context, carID int, version int, patch rental.CarPatch) error {
+	if !m.owns(ctx, carID) {
+		return rental.ErrCarNotFound
+	}
+	car := m.cars[carID]
+	if car.Version != version {
+		return rental.ErrCarVersionMismatch
+	}
+	car.Apply(patch)
+	car.Version++
+	return nil
+}
+
+// Delete deletes a car from the Mock state if it is still at version and isn't rented.
+func (m *MockCarCRUDService) Delete(ctx context.Context, carID int, version int) error {
+	if !m.owns(ctx, carID) {
+		return rental.ErrCarNotFound
+	}
+	if m.cars[carID].Version != version {
+		return rental.ErrCarVersionMismatch
+	}
+	// Only the current rentals are known to the Mock state
+	if m.cars[carID].RenterID() != 0 {
+		return rental.ErrCarHasRentals
+	}
 	delete(m.cars, carID)
+	delete(m.tenants, carID)
 	return nil
 }
 
 // NewMockCarCRUDService returns a new MockCarCRUDService.
 func NewMockCarCRUDService() *MockCarCRUDService {
-	return &MockCarCRUDService{cars: map[int]*rental.Car{}}
+	return &MockCarCRUDService{cars: map[int]*rental.Car{}, tenants: map[int]int{}}
 }
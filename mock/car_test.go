@@ -1,10 +1,12 @@
 package mock
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
 )
 
 func TestMockCarCRUDService_Get(t *testing.T) {
@@ -12,7 +14,7 @@ func TestMockCarCRUDService_Get(t *testing.T) {
 		testCarID := 1
 		car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
 		mockCarCRUDService := &MockCarCRUDService{cars: map[int]*rental.Car{1: &car}}
-		got, err := mockCarCRUDService.Get(testCarID)
+		got, err := mockCarCRUDService.Get(context.Background(), testCarID)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
@@ -22,7 +24,7 @@ func TestMockCarCRUDService_Get(t *testing.T) {
 	})
 	t.Run("get non-existent car", func(t *testing.T) {
 		mockCarCRUDService := &MockCarCRUDService{}
-		_, err := mockCarCRUDService.Get(1)
+		_, err := mockCarCRUDService.Get(context.Background(), 1)
 		if err != rental.ErrCarNotFound {
 			t.Errorf(fmt.Sprintf("got error %v, want %v", err, rental.ErrCarNotFound))
 		}
@@ -34,11 +36,11 @@ func TestMockCarCRUDService_Create(t *testing.T) {
 	t.Run("create car", func(t *testing.T) {
 		car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
 		mockCarCRUDService := NewMockCarCRUDService()
-		_, err := mockCarCRUDService.Create(car)
+		_, err := mockCarCRUDService.Create(context.Background(), car)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
-		got, err := mockCarCRUDService.Get(testCarID)
+		got, err := mockCarCRUDService.Get(context.Background(), testCarID)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
@@ -49,11 +51,11 @@ func TestMockCarCRUDService_Create(t *testing.T) {
 	t.Run("create duplicate car", func(t *testing.T) {
 		car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
 		mockCarCRUDService := NewMockCarCRUDService()
-		_, err := mockCarCRUDService.Create(car)
+		_, err := mockCarCRUDService.Create(context.Background(), car)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
-		_, err = mockCarCRUDService.Create(car)
+		_, err = mockCarCRUDService.Create(context.Background(), car)
 		if err != rental.ErrCarAlreadyExists {
 			t.Errorf(fmt.Sprintf("got error %v, want %v", err, rental.ErrCarAlreadyExists))
 		}
@@ -64,16 +66,17 @@ func TestMockCarCRUDService_Update(t *testing.T) {
 	testCarID := 1
 	car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
 	mockCarCRUDService := NewMockCarCRUDService()
-	_, err := mockCarCRUDService.Create(car)
+	_, err := mockCarCRUDService.Create(context.Background(), car)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 	car.Make = "Honda"
-	err = mockCarCRUDService.Update(car)
+	err = mockCarCRUDService.Update(context.Background(), car)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
-	got, err := mockCarCRUDService.Get(testCarID)
+	car.Version++
+	got, err := mockCarCRUDService.Get(context.Background(), testCarID)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
@@ -82,20 +85,106 @@ func TestMockCarCRUDService_Update(t *testing.T) {
 	}
 }
 
+func TestMockCarCRUDService_Update_NotFound(t *testing.T) {
+	mockCarCRUDService := NewMockCarCRUDService()
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+	if err := mockCarCRUDService.Update(context.Background(), car); err != rental.ErrCarNotFound {
+		t.Errorf("got error %v, want %v", err, rental.ErrCarNotFound)
+	}
+	if err := mockCarCRUDService.Delete(context.Background(), car.ID, car.Version); err != rental.ErrCarNotFound {
+		t.Errorf("got error %v, want %v", err, rental.ErrCarNotFound)
+	}
+}
+
+func TestMockCarCRUDService_ListRentedBy(t *testing.T) {
+	rented := rental.Car{ID: 1, CustomerID: null.IntFrom(1), Make: "Toyota", Model: "Corolla", Year: 2015}
+	available := rental.Car{ID: 2, Make: "Ford", Model: "Fiesta", Year: 2016}
+	mockCarCRUDService := NewMockCarCRUDService()
+	for _, car := range []rental.Car{rented, available} {
+		if _, err := mockCarCRUDService.Create(context.Background(), car); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	}
+	got, err := mockCarCRUDService.ListRentedBy(context.Background(), 1)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 1 || got[0] != rented {
+		t.Errorf("got %v, want [%v]", got, rented)
+	}
+}
+
 func TestMockCarCRUDService_Delete(t *testing.T) {
 	testCarID := 1
 	car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
 	mockCarCRUDService := NewMockCarCRUDService()
-	_, err := mockCarCRUDService.Create(car)
+	_, err := mockCarCRUDService.Create(context.Background(), car)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
-	err = mockCarCRUDService.Delete(testCarID)
+	err = mockCarCRUDService.Delete(context.Background(), testCarID, car.Version)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
-	_, err = mockCarCRUDService.Get(testCarID)
+	_, err = mockCarCRUDService.Get(context.Background(), testCarID)
 	if err != rental.ErrCarNotFound {
 		t.Errorf("got error %v, want %v", err, rental.ErrCarNotFound)
 	}
 }
+
+func TestMockCarCRUDService_Delete_Rented(t *testing.T) {
+	car := rental.Car{ID: 1, CustomerID: null.IntFrom(1), Make: "Toyota", Model: "Corolla", Year: 2015}
+	mockCarCRUDService := NewMockCarCRUDService()
+	if _, err := mockCarCRUDService.Create(context.Background(), car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if err := mockCarCRUDService.Delete(context.Background(), car.ID, car.Version); err != rental.ErrCarHasRentals {
+		t.Errorf("got error %v, want %v", err, rental.ErrCarHasRentals)
+	}
+	if _, err := mockCarCRUDService.Get(context.Background(), car.ID); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+}
+
+func TestMockCarCRUDService_Patch(t *testing.T) {
+	testCarID := 1
+	car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
+	mockCarCRUDService := NewMockCarCRUDService()
+	_, err := mockCarCRUDService.Create(context.Background(), car)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	year := 2016
+	err = mockCarCRUDService.Patch(context.Background(), testCarID, car.Version, rental.CarPatch{Year: &year})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	got, err := mockCarCRUDService.Get(context.Background(), testCarID)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	want := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2016, Version: 1}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	err = mockCarCRUDService.Patch(context.Background(), testCarID, car.Version, rental.CarPatch{Year: &year})
+	if err != rental.ErrCarVersionMismatch {
+		t.Errorf("got error %v, want %v", err, rental.ErrCarVersionMismatch)
+	}
+}
+
+func TestMockCarCRUDService_GetMany(t *testing.T) {
+	mockCarCRUDService := NewMockCarCRUDService()
+	for _, car := range []rental.Car{{ID: 1, Make: "Toyota"}, {ID: 2, Make: "Ford"}, {ID: 3, Make: "Renault"}} {
+		if _, err := mockCarCRUDService.Create(context.Background(), car); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	}
+	got, err := mockCarCRUDService.GetMany(context.Background(), []int{3, 1, 3, 4})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 3 {
+		t.Errorf("got %v, want cars 1 and 3", got)
+	}
+}
@@ -2,44 +2,98 @@
 package mock
 
 import (
+	"context"
+	"sort"
+
 	"github.com/shidenkai0/rental/pkg/rental"
 )
 
 type MockCustomerCRUDService struct {
 	customers map[int]*rental.Customer
+	tenants   map[int]int // Tenant of each customer
+}
+
+// owns returns true if the customer customerID exists in the Mock state and belongs to the tenant of ctx.
+func (m *MockCustomerCRUDService) owns(ctx context.Context, customerID int) bool {
+	tenantID, _ := rental.TenantFrom(ctx)
+	_, ok := m.customers[customerID]
+	return ok && m.tenants[customerID] == tenantID
 }
 
 // Create creates a customer in the Mock state and returns the id.
-func (m *MockCustomerCRUDService) Create(customer rental.Customer) (id int, err error) {
+func (m *MockCustomerCRUDService) Create(ctx context.Context, customer rental.Customer) (id int, err error) {
 	if _, ok := m.customers[customer.ID]; ok {
 		return 0, rental.ErrCustomerAlreadyExists
 	}
+	tenantID, _ := rental.TenantFrom(ctx)
 	m.customers[customer.ID] = &customer
+	m.tenants[customer.ID] = tenantID
 	return customer.ID, nil
 }
 
 // Get fetches a customer from the Mock state.
-func (m *MockCustomerCRUDService) Get(id int) (rental.Customer, error) {
-	customer, ok := m.customers[id]
-	if !ok {
+func (m *MockCustomerCRUDService) Get(ctx context.Context, id int) (rental.Customer, error) {
+	if !m.owns(ctx, id) {
 		return rental.Customer{}, rental.ErrCustomerNotFound
 	}
-	return *customer, nil
+	return *m.customers[id], nil
+}
+
+// GetMany fetches the customers of ids from the Mock state, ordered by id.
+func (m *MockCustomerCRUDService) GetMany(ctx context.Context, ids []int) ([]rental.Customer, error) {
+	customers := []rental.Customer{}
+	found := map[int]bool{}
+	for _, id := range ids {
+		if m.owns(ctx, id) && !found[id] {
+			found[id] = true
+			customers = append(customers, *m.customers[id])
+		}
+	}
+	sort.Slice(customers, func(i, j int) bool { return customers[i].ID < customers[j].ID })
+	return customers, nil
 }
 
-// Update updates a customer in the Mock state.
-func (m *MockCustomerCRUDService) Update(customer rental.Customer) error {
+// Update updates a customer in the Mock state if it is still at customer.Version, and increments its version.
+func (m *MockCustomerCRUDService) Update(ctx context.Context, customer rental.Customer) error {
+	if !m.owns(ctx, customer.ID) {
+		return rental.ErrCustomerNotFound
+	}
+	if m.customers[customer.ID].Version != customer.Version {
+		return rental.ErrCustomerVersionMismatch
+	}
+	customer.Version++
 	m.customers[customer.ID] = &customer
 	return nil
 }
 
-// Delete deletes a customer from the Mock state.
-func (m *MockCustomerCRUDService) Delete(customerID int) error {
+// Patch updates the changed details of a customer in the Mock state if it is still at version, and increments its version.
+func (m *MockCustomerCRUDService) Patch(ctx context.Context, customerID int, version int, patch rental.CustomerPatch) error {
+	if !m.owns(ctx, customerID) {
+		return rental.ErrCustomerNotFound
+	}
+	customer := m.customers[customerID]
+	if customer.Version != version {
+		return rental.ErrCustomerVersionMismatch
+	}
+	customer.Apply(patch)
+	customer.Version++
+	return nil
+}
+
+// Delete deletes a customer from the Mock state if it is still at version.
+func (m *MockCustomerCRUDService) Delete(ctx context.Context, customerID int, version int) error {
+	if !m.owns(ctx, customerID) {
+		return rental.ErrCustomerNotFound
+	}
+	if m.customers[customerID].Version != version {
+		return rental.ErrCustomerVersionMismatch
+	}
 	delete(m.customers, customerID)
+	delete(m.tenants, customerID)
 	return nil
 }
 
 // NewMockCustomerCRUDService returns a new MockCustomerCRUDService.
 func NewMockCustomerCRUDService() *MockCustomerCRUDService {
-	return &MockCustomerCRUDService{customers: map[int]*rental.Customer{}}
+	return &MockCustomerCRUDService{customers: map[int]*rental.Customer{}, tenants: map[int]int{}}
 }
@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -12,7 +13,7 @@ func TestMockCustomerCRUDService_Get(t *testing.T) {
 		testCustomerID := 1
 		customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
 		mockCustomerCRUDService := &MockCustomerCRUDService{customers: map[int]*rental.Customer{1: &customer}}
-		got, err := mockCustomerCRUDService.Get(testCustomerID)
+		got, err := mockCustomerCRUDService.Get(context.Background(), testCustomerID)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
@@ -24,7 +25,7 @@ func TestMockCustomerCRUDService_Get(t *testing.T) {
 		testCustomerID := 1
 		mockCustomerCRUDService := &MockCustomerCRUDService{}
 		want := rental.ErrCustomerNotFound
-		_, err := mockCustomerCRUDService.Get(testCustomerID)
+		_, err := mockCustomerCRUDService.Get(context.Background(), testCustomerID)
 		if err != want {
 			t.Errorf(fmt.Sprintf("got error %v, want %v", err, want))
 		}
@@ -36,11 +37,11 @@ func TestMockCustomerCRUDService_Create(t *testing.T) {
 	t.Run("create customer", func(t *testing.T) {
 		customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
 		mockCustomerCRUDService := NewMockCustomerCRUDService()
-		_, err := mockCustomerCRUDService.Create(customer)
+		_, err := mockCustomerCRUDService.Create(context.Background(), customer)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
-		got, err := mockCustomerCRUDService.Get(testCustomerID)
+		got, err := mockCustomerCRUDService.Get(context.Background(), testCustomerID)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
@@ -51,11 +52,11 @@ func TestMockCustomerCRUDService_Create(t *testing.T) {
 	t.Run("create duplicate customer", func(t *testing.T) {
 		customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
 		mockCustomerCRUDService := NewMockCustomerCRUDService()
-		_, err := mockCustomerCRUDService.Create(customer)
+		_, err := mockCustomerCRUDService.Create(context.Background(), customer)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
-		_, err = mockCustomerCRUDService.Create(customer)
+		_, err = mockCustomerCRUDService.Create(context.Background(), customer)
 		if err != rental.ErrCustomerAlreadyExists {
 			t.Errorf(fmt.Sprintf("got error %v, want %v", err, rental.ErrCustomerAlreadyExists))
 		}
@@ -67,15 +68,16 @@ func TestMockCustomerCRUDService_Update(t *testing.T) {
 	testCustomerID := 1
 	customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
 	mockCustomerCRUDService := NewMockCustomerCRUDService()
-	_, err := mockCustomerCRUDService.Create(customer)
+	_, err := mockCustomerCRUDService.Create(context.Background(), customer)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
-	err = mockCustomerCRUDService.Update(customer)
+	err = mockCustomerCRUDService.Update(context.Background(), customer)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
-	got, err := mockCustomerCRUDService.Get(testCustomerID)
+	customer.Version++
+	got, err := mockCustomerCRUDService.Get(context.Background(), testCustomerID)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
@@ -89,16 +91,16 @@ func TestMockCustomerCRUDService_Delete(t *testing.T) {
 	testCustomerID := 1
 	customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
 	mockCustomerCRUDService := NewMockCustomerCRUDService()
-	_, err := mockCustomerCRUDService.Create(customer)
+	_, err := mockCustomerCRUDService.Create(context.Background(), customer)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
-	err = mockCustomerCRUDService.Delete(testCustomerID)
+	err = mockCustomerCRUDService.Delete(context.Background(), testCustomerID, customer.Version)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 	want := rental.ErrCustomerNotFound
-	_, err = mockCustomerCRUDService.Get(testCustomerID)
+	_, err = mockCustomerCRUDService.Get(context.Background(), testCustomerID)
 	if err != want {
 		t.Errorf("got error %v, want %v", err, rental.ErrCustomerNotFound)
 	}
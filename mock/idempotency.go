@@ -0,0 +1,87 @@
+// Package mock provides mock implementations of stateful services.
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+type MockIdempotencyKeyService struct {
+	keys   map[int]*rental.IdempotencyKey
+	lastID int
+}
+
+// find returns the idempotency key of a client in the tenant of ctx.
+func (m *MockIdempotencyKeyService) find(ctx context.Context, client, key string) (*rental.IdempotencyKey, bool) {
+	tenantID, _ := rental.TenantFrom(ctx)
+	for _, idempotencyKey := range m.keys {
+		if idempotencyKey.TenantID == tenantID && idempotencyKey.Client == client && idempotencyKey.Key == key {
+			return idempotencyKey, true
+		}
+	}
+	return nil, false
+}
+
+// Create records an idempotency key in the Mock state and returns the id.
+func (m *MockIdempotencyKeyService) Create(ctx context.Context, key rental.IdempotencyKey) (id int, err error) {
+	if existing, ok := m.find(ctx, key.Client, key.Key); ok {
+		if existing.ExpiresAt.After(time.Now()) {
+			return 0, rental.ErrIdempotencyKeyAlreadyExists
+		}
+		delete(m.keys, existing.ID)
+	}
+	m.lastID++
+	key.ID = m.lastID
+	key.TenantID, _ = rental.TenantFrom(ctx)
+	key.CreatedAt = time.Now()
+	m.keys[key.ID] = &key
+	return key.ID, nil
+}
+
+// Get fetches the idempotency key of a client from the Mock state.
+func (m *MockIdempotencyKeyService) Get(ctx context.Context, client, key string) (rental.IdempotencyKey, error) {
+	idempotencyKey, ok := m.find(ctx, client, key)
+	if !ok {
+		return rental.IdempotencyKey{}, rental.ErrIdempotencyKeyNotFound
+	}
+	return *idempotencyKey, nil
+}
+
+// Complete records the response of the request made with an idempotency key in the Mock state.
+func (m *MockIdempotencyKeyService) Complete(ctx context.Context, key rental.IdempotencyKey) error {
+	tenantID, _ := rental.TenantFrom(ctx)
+	if existing, ok := m.keys[key.ID]; ok && existing.TenantID == tenantID {
+		existing.StatusCode = key.StatusCode
+		existing.ResponseHeaders = key.ResponseHeaders
+		existing.ResponseBody = key.ResponseBody
+	}
+	return nil
+}
+
+// Delete deletes an idempotency key from the Mock state.
+func (m *MockIdempotencyKeyService) Delete(ctx context.Context, id int) error {
+	tenantID, _ := rental.TenantFrom(ctx)
+	if existing, ok := m.keys[id]; ok && existing.TenantID == tenantID {
+		delete(m.keys, id)
+	}
+	return nil
+}
+
+// Purge deletes the idempotency keys that expired before now from the Mock state.
+func (m *MockIdempotencyKeyService) Purge(ctx context.Context, now time.Time) (int64, error) {
+	var purged int64
+	for id, key := range m.keys {
+		if !key.ExpiresAt.After(now) {
+			delete(m.keys, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// NewMockIdempotencyKeyService returns a new MockIdempotencyKeyService.
+func NewMockIdempotencyKeyService() *MockIdempotencyKeyService {
+	return &MockIdempotencyKeyService{keys: map[int]*rental.IdempotencyKey{}}
+}
@@ -0,0 +1,69 @@
+// Package mock provides mock implementations of stateful services.
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+type MockRentalService struct {
+	rentals map[int]*rental.Rental
+	tenants map[int]int // Tenant of each rental
+	lastID  int
+}
+
+// owns returns true if the rental id exists in the Mock state and belongs to the tenant of ctx.
+func (m *MockRentalService) owns(ctx context.Context, id int) bool {
+	tenantID, _ := rental.TenantFrom(ctx)
+	_, ok := m.rentals[id]
+	return ok && m.tenants[id] == tenantID
+}
+
+// Create records a rental in the Mock state and returns the id.
+func (m *MockRentalService) Create(ctx context.Context, r rental.Rental) (id int, err error) {
+	tenantID, _ := rental.TenantFrom(ctx)
+	m.lastID++
+	r.ID = m.lastID
+	m.rentals[r.ID] = &r
+	m.tenants[r.ID] = tenantID
+	return r.ID, nil
+}
+
+// Get fetches a rental from the Mock state.
+func (m *MockRentalService) Get(ctx context.Context, id int) (rental.Rental, error) {
+	if !m.owns(ctx, id) {
+		return rental.Rental{}, rental.ErrRentalNotFound
+	}
+	return *m.rentals[id], nil
+}
+
+// GetActiveByCar fetches the active rental of a car from the Mock state.
+func (m *MockRentalService) GetActiveByCar(ctx context.Context, carID int) (rental.Rental, error) {
+	for id, r := range m.rentals {
+		if m.owns(ctx, id) && r.CarID == carID && r.Active() {
+			return *r, nil
+		}
+	}
+	return rental.Rental{}, rental.ErrRentalNotFound
+}
+
+// End records the return of the car of an active rental in the Mock state.
+func (m *MockRentalService) End(ctx context.Context, id int, returnedAt time.Time) error {
+	if !m.owns(ctx, id) {
+		return rental.ErrRentalNotFound
+	}
+	r := m.rentals[id]
+	if !r.Active() {
+		return rental.ErrRentalAlreadyReturned
+	}
+	r.ReturnedAt = null.TimeFrom(returnedAt)
+	return nil
+}
+
+// NewMockRentalService returns a new MockRentalService.
+func NewMockRentalService() *MockRentalService {
+	return &MockRentalService{rentals: map[int]*rental.Rental{}, tenants: map[int]int{}}
+}
@@ -0,0 +1,39 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestMockRentalService_End(t *testing.T) {
+	mockRentalService := NewMockRentalService()
+	id, err := mockRentalService.Create(context.Background(), rental.Rental{CarID: 1, CustomerID: 1, StartedAt: time.Now()})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if _, err := mockRentalService.GetActiveByCar(context.Background(), 1); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	t.Run("end rental", func(t *testing.T) {
+		if err := mockRentalService.End(context.Background(), id, time.Now()); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if _, err := mockRentalService.GetActiveByCar(context.Background(), 1); err != rental.ErrRentalNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrRentalNotFound)
+		}
+	})
+	t.Run("end returned rental", func(t *testing.T) {
+		if err := mockRentalService.End(context.Background(), id, time.Now()); err != rental.ErrRentalAlreadyReturned {
+			t.Errorf("got error %v, want %v", err, rental.ErrRentalAlreadyReturned)
+		}
+	})
+	t.Run("end non-existent rental", func(t *testing.T) {
+		if err := mockRentalService.End(context.Background(), id+1, time.Now()); err != rental.ErrRentalNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrRentalNotFound)
+		}
+	})
+}
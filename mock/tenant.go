@@ -0,0 +1,59 @@
+// Package mock provides mock implementations of stateful services.
+package mock
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+type MockTenantService struct {
+	tenants map[int]*rental.Tenant
+}
+
+// Create creates a tenant in the Mock state and returns the id.
+func (m *MockTenantService) Create(ctx context.Context, tenant rental.Tenant) (id int, err error) {
+	if _, err := m.GetBySlug(ctx, tenant.Slug); err == nil {
+		return 0, rental.ErrTenantAlreadyExists
+	}
+	tenant.ID = len(m.tenants) + 1
+	tenant.CreatedAt = time.Now()
+	m.tenants[tenant.ID] = &tenant
+	return tenant.ID, nil
+}
+
+// Get fetches a tenant from the Mock state.
+func (m *MockTenantService) Get(ctx context.Context, id int) (rental.Tenant, error) {
+	tenant, ok := m.tenants[id]
+	if !ok {
+		return rental.Tenant{}, rental.ErrTenantNotFound
+	}
+	return *tenant, nil
+}
+
+// GetBySlug fetches a tenant from the Mock state by slug.
+func (m *MockTenantService) GetBySlug(ctx context.Context, slug string) (rental.Tenant, error) {
+	for _, tenant := range m.tenants {
+		if tenant.Slug == slug {
+			return *tenant, nil
+		}
+	}
+	return rental.Tenant{}, rental.ErrTenantNotFound
+}
+
+// List fetches all tenants from the Mock state, ordered by id.
+func (m *MockTenantService) List(ctx context.Context) ([]rental.Tenant, error) {
+	tenants := make([]rental.Tenant, 0, len(m.tenants))
+	for _, tenant := range m.tenants {
+		tenants = append(tenants, *tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].ID < tenants[j].ID })
+	return tenants, nil
+}
+
+// NewMockTenantService returns a new MockTenantService.
+func NewMockTenantService() *MockTenantService {
+	return &MockTenantService{tenants: map[int]*rental.Tenant{}}
+}
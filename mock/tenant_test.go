@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestMockTenantService_Create(t *testing.T) {
+	t.Run("create tenant", func(t *testing.T) {
+		mockTenantService := NewMockTenantService()
+		id, err := mockTenantService.Create(context.Background(), rental.Tenant{Slug: "lyon", Name: "Rental Lyon"})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		got, err := mockTenantService.GetBySlug(context.Background(), "lyon")
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got.ID != id || got.Name != "Rental Lyon" {
+			t.Errorf("got %v, want tenant %d", got, id)
+		}
+	})
+	t.Run("create tenant with a duplicate slug", func(t *testing.T) {
+		mockTenantService := NewMockTenantService()
+		_, err := mockTenantService.Create(context.Background(), rental.Tenant{Slug: "lyon", Name: "Rental Lyon"})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		_, err = mockTenantService.Create(context.Background(), rental.Tenant{Slug: "lyon", Name: "Lyon"})
+		if err != rental.ErrTenantAlreadyExists {
+			t.Errorf("got error %v, want %v", err, rental.ErrTenantAlreadyExists)
+		}
+	})
+}
+
+func TestMockCarCRUDService_TenantIsolation(t *testing.T) {
+	lyon := rental.WithTenant(context.Background(), 2)
+	mockCarCRUDService := NewMockCarCRUDService()
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+	if _, err := mockCarCRUDService.Create(lyon, car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if _, err := mockCarCRUDService.Get(context.Background(), car.ID); err != rental.ErrCarNotFound {
+		t.Errorf("got error %v, want %v", err, rental.ErrCarNotFound)
+	}
+	if got, err := mockCarCRUDService.Get(lyon, car.ID); err != nil || got != car {
+		t.Errorf("got %v, %v, want %v, nil", got, err, car)
+	}
+}
@@ -0,0 +1,60 @@
+// Package mock provides mock implementations of stateful services.
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+type MockRefreshTokenService struct {
+	tokens map[int]*rental.RefreshToken
+}
+
+// Create stores a refresh token in the Mock state and returns the id.
+func (m *MockRefreshTokenService) Create(ctx context.Context, token rental.RefreshToken) (id int, err error) {
+	token.ID = len(m.tokens) + 1
+	token.TenantID, _ = rental.TenantFrom(ctx)
+	token.CreatedAt = time.Now()
+	m.tokens[token.ID] = &token
+	return token.ID, nil
+}
+
+// GetByHash fetches a refresh token from the Mock state by hash, whatever its tenant.
+func (m *MockRefreshTokenService) GetByHash(ctx context.Context, tokenHash string) (rental.RefreshToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			return *token, nil
+		}
+	}
+	return rental.RefreshToken{}, rental.ErrRefreshTokenNotFound
+}
+
+// Revoke revokes a refresh token in the Mock state.
+func (m *MockRefreshTokenService) Revoke(ctx context.Context, tokenID int) error {
+	tenantID, _ := rental.TenantFrom(ctx)
+	token, ok := m.tokens[tokenID]
+	if !ok || token.TenantID != tenantID || token.RevokedAt.Valid {
+		return rental.ErrRefreshTokenRevoked
+	}
+	token.RevokedAt = null.TimeFrom(time.Now())
+	return nil
+}
+
+// RevokeAllForUser revokes all the refresh tokens of a user in the Mock state.
+func (m *MockRefreshTokenService) RevokeAllForUser(ctx context.Context, userID int) error {
+	tenantID, _ := rental.TenantFrom(ctx)
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.TenantID == tenantID && !token.RevokedAt.Valid {
+			token.RevokedAt = null.TimeFrom(time.Now())
+		}
+	}
+	return nil
+}
+
+// NewMockRefreshTokenService returns a new MockRefreshTokenService.
+func NewMockRefreshTokenService() *MockRefreshTokenService {
+	return &MockRefreshTokenService{tokens: map[int]*rental.RefreshToken{}}
+}
@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestMockRefreshTokenService_GetByHash(t *testing.T) {
+	t.Run("get refresh token", func(t *testing.T) {
+		token := rental.RefreshToken{UserID: 1, TokenHash: "a3f1c2", Scope: "cars:read", ExpiresAt: time.Now().Add(time.Hour)}
+		mockRefreshTokenService := NewMockRefreshTokenService()
+		id, err := mockRefreshTokenService.Create(context.Background(), token)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		got, err := mockRefreshTokenService.GetByHash(context.Background(), token.TokenHash)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got.ID != id || got.UserID != token.UserID {
+			t.Errorf("got %v, want token %d of user %d", got, id, token.UserID)
+		}
+	})
+	t.Run("get non-existent refresh token", func(t *testing.T) {
+		mockRefreshTokenService := NewMockRefreshTokenService()
+		_, err := mockRefreshTokenService.GetByHash(context.Background(), "a3f1c2")
+		if err != rental.ErrRefreshTokenNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrRefreshTokenNotFound)
+		}
+	})
+}
+
+func TestMockRefreshTokenService_Revoke(t *testing.T) {
+	token := rental.RefreshToken{UserID: 1, TokenHash: "a3f1c2", Scope: "cars:read", ExpiresAt: time.Now().Add(time.Hour)}
+	mockRefreshTokenService := NewMockRefreshTokenService()
+	id, err := mockRefreshTokenService.Create(context.Background(), token)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if err := mockRefreshTokenService.Revoke(context.Background(), id); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	got, err := mockRefreshTokenService.GetByHash(context.Background(), token.TokenHash)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if got.Valid(time.Now()) {
+		t.Errorf("got valid token, want revoked token")
+	}
+	if err := mockRefreshTokenService.Revoke(context.Background(), id); err != rental.ErrRefreshTokenRevoked {
+		t.Errorf("got error %v, want %v", err, rental.ErrRefreshTokenRevoked)
+	}
+}
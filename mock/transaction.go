@@ -0,0 +1,16 @@
+// Package mock provides mock implementations of stateful services.
+package mock
+
+import "context"
+
+type MockTransactor struct{}
+
+// WithinTransaction runs fn, changes to the Mock state aren't rolled back if it fails.
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// NewMockTransactor returns a new MockTransactor.
+func NewMockTransactor() *MockTransactor {
+	return &MockTransactor{}
+}
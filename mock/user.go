@@ -0,0 +1,83 @@
+// Package mock provides mock implementations of stateful services.
+package mock
+
+import (
+	"context"
+	"sort"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+type MockUserCRUDService struct {
+	users map[int]*rental.User
+}
+
+// Create creates a user in the Mock state and returns the id.
+func (m *MockUserCRUDService) Create(ctx context.Context, user rental.User) (id int, err error) {
+	if _, ok := m.users[user.ID]; ok {
+		return 0, rental.ErrUserAlreadyExists
+	}
+	if _, err := m.GetByUsername(ctx, user.Username); err == nil {
+		return 0, rental.ErrUserAlreadyExists
+	}
+	user.TenantID, _ = rental.TenantFrom(ctx)
+	m.users[user.ID] = &user
+	return user.ID, nil
+}
+
+// Get fetches a user from the Mock state.
+func (m *MockUserCRUDService) Get(ctx context.Context, id int) (rental.User, error) {
+	tenantID, _ := rental.TenantFrom(ctx)
+	user, ok := m.users[id]
+	if !ok || user.TenantID != tenantID {
+		return rental.User{}, rental.ErrUserNotFound
+	}
+	return *user, nil
+}
+
+// GetByUsername fetches a user from the Mock state by username, whatever its tenant.
+func (m *MockUserCRUDService) GetByUsername(ctx context.Context, username string) (rental.User, error) {
+	for _, user := range m.users {
+		if user.Username == username {
+			return *user, nil
+		}
+	}
+	return rental.User{}, rental.ErrUserNotFound
+}
+
+// List fetches all users from the Mock state.
+func (m *MockUserCRUDService) List(ctx context.Context) ([]rental.User, error) {
+	tenantID, _ := rental.TenantFrom(ctx)
+	users := []rental.User{}
+	for _, user := range m.users {
+		if user.TenantID == tenantID {
+			users = append(users, *user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// Update updates a user in the Mock state.
+func (m *MockUserCRUDService) Update(ctx context.Context, user rental.User) error {
+	existing, err := m.Get(ctx, user.ID)
+	if err != nil {
+		return nil
+	}
+	user.TenantID = existing.TenantID
+	m.users[user.ID] = &user
+	return nil
+}
+
+// Delete deletes a user from the Mock state.
+func (m *MockUserCRUDService) Delete(ctx context.Context, userID int) error {
+	if _, err := m.Get(ctx, userID); err == nil {
+		delete(m.users, userID)
+	}
+	return nil
+}
+
+// NewMockUserCRUDService returns a new MockUserCRUDService.
+func NewMockUserCRUDService() *MockUserCRUDService {
+	return &MockUserCRUDService{users: map[int]*rental.User{}}
+}
@@ -0,0 +1,75 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestMockUserCRUDService_GetByUsername(t *testing.T) {
+	t.Run("get user", func(t *testing.T) {
+		user := rental.User{ID: 1, Username: "jane", Role: rental.RoleAgent}
+		mockUserCRUDService := &MockUserCRUDService{users: map[int]*rental.User{1: &user}}
+		got, err := mockUserCRUDService.GetByUsername(context.Background(), "jane")
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got != user {
+			t.Errorf("got %v, want %v", got, user)
+		}
+	})
+	t.Run("get non-existent user", func(t *testing.T) {
+		mockUserCRUDService := NewMockUserCRUDService()
+		_, err := mockUserCRUDService.GetByUsername(context.Background(), "jane")
+		if err != rental.ErrUserNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrUserNotFound)
+		}
+	})
+}
+
+func TestMockUserCRUDService_Create(t *testing.T) {
+	t.Run("create user", func(t *testing.T) {
+		user := rental.User{ID: 1, Username: "jane", Role: rental.RoleAgent}
+		mockUserCRUDService := NewMockUserCRUDService()
+		_, err := mockUserCRUDService.Create(context.Background(), user)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		got, err := mockUserCRUDService.List(context.Background())
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(got) != 1 || got[0] != user {
+			t.Errorf("got %v, want [%v]", got, user)
+		}
+	})
+	t.Run("create user with a duplicate username", func(t *testing.T) {
+		mockUserCRUDService := NewMockUserCRUDService()
+		_, err := mockUserCRUDService.Create(context.Background(), rental.User{ID: 1, Username: "jane", Role: rental.RoleAgent})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		_, err = mockUserCRUDService.Create(context.Background(), rental.User{ID: 2, Username: "jane", Role: rental.RoleAdmin})
+		if err != rental.ErrUserAlreadyExists {
+			t.Errorf("got error %v, want %v", err, rental.ErrUserAlreadyExists)
+		}
+	})
+}
+
+func TestMockUserCRUDService_Delete(t *testing.T) {
+	user := rental.User{ID: 1, Username: "jane", Role: rental.RoleAgent}
+	mockUserCRUDService := NewMockUserCRUDService()
+	_, err := mockUserCRUDService.Create(context.Background(), user)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	err = mockUserCRUDService.Delete(context.Background(), user.ID)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	_, err = mockUserCRUDService.Get(context.Background(), user.ID)
+	if err != rental.ErrUserNotFound {
+		t.Errorf("got error %v, want %v", err, rental.ErrUserNotFound)
+	}
+}
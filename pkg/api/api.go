@@ -3,31 +3,62 @@
 package api
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/events"
+	"github.com/shidenkai0/rental/pkg/metrics"
 	"github.com/shidenkai0/rental/pkg/rental"
 )
 
-func NewServer(carCRUDService rental.CarCRUDService, customerCRUDService rental.CustomerCRUDService) *Server {
+func NewServer(carCRUDService rental.CarCRUDService, customerCRUDService rental.CustomerCRUDService, rentalService rental.RentalService, userCRUDService rental.UserCRUDService, apiKeyService rental.APIKeyService, auditLogService rental.AuditLogService, tenantService rental.TenantService, transactor rental.Transactor) *Server {
 	return &Server{
 		CarCRUDService:      carCRUDService,
 		CustomerCRUDService: customerCRUDService,
+		RentalService:       rentalService,
+		UserCRUDService:     userCRUDService,
+		APIKeyService:       apiKeyService,
+		AuditLogService:     auditLogService,
+		TenantService:       tenantService,
+		Transactor:          transactor,
 	}
 }
 
 type Server struct {
 	CarCRUDService      rental.CarCRUDService
 	CustomerCRUDService rental.CustomerCRUDService
+	RentalService       rental.RentalService
+	UserCRUDService     rental.UserCRUDService
+	APIKeyService       rental.APIKeyService
+	TenantService       rental.TenantService
+	Transactor          rental.Transactor
+	// AuditLogService records the changes, it is optional and disables the
+	// audit log when nil.
+	AuditLogService rental.AuditLogService
+	// Events receives car status changes, it is optional and disables
+	// the event stream when nil.
+	Events *events.Broker
+	// Tokens issues access tokens, it is optional and disables the token
+	// endpoints when nil.
+	Tokens *auth.TokenService
+	// Metrics counts the rentals, it is optional and records nothing when nil.
+	Metrics *metrics.Metrics
 }
 
 // toAPICar converts a rental.Car to an api.Car and deals with nullable fields.
-func toAPICar(car rental.Car) gen.Car {
+// The renter of the car is hidden from self-service customers, unless they
+// are the renter, as they may only see their own rentals.
+func toAPICar(ctx echo.Context, car rental.Car) gen.Car {
 	var renterID int64
 	if car.CustomerID.Valid {
 		renterID = car.CustomerID.Int64
 	}
+	if self, ok := selfServiceCustomer(ctx, rental.ScopeCustomersRead); ok && int(renterID) != self {
+		renterID = 0
+	}
 
 	return gen.Car{
 		Id:       int64(car.ID),
@@ -55,97 +86,154 @@ func (s *Server) CreateCar(ctx echo.Context) error {
 	}
 	car := rental.Car{Make: createCar.Make, Model: createCar.Model, Year: createCar.Year}
 	var err error
-	car.ID, err = s.CarCRUDService.Create(car)
+	car.ID, err = s.CarCRUDService.Create(ctx.Request().Context(), car)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	apiCar := toAPICar(car)
+	s.audit(ctx, rental.AuditActionCreate, rental.AuditEntityCar, car.ID, nil, car)
+	s.publish(ctx, events.CarCreated, car)
+	apiCar := toAPICar(ctx, car)
 	return ctx.JSON(http.StatusCreated, apiCar)
 }
 
 // Deletes a car
 // (DELETE /car/{carId})
-func (s *Server) DeleteCar(ctx echo.Context, carId int64) error {
-	err := s.CarCRUDService.Delete(int(carId))
+func (s *Server) DeleteCar(ctx echo.Context, carId int64, params gen.DeleteCarParams) error {
+	car, err := s.CarCRUDService.Get(ctx.Request().Context(), int(carId))
 	if err == rental.ErrCarNotFound {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	if err := checkIfMatch(params.IfMatch, car.Version); err != nil {
+		return err
+	}
+	err = s.CarCRUDService.Delete(ctx.Request().Context(), car.ID, car.Version)
+	if err == rental.ErrCarNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err == rental.ErrCarHasRentals {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err == rental.ErrCarVersionMismatch {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	s.audit(ctx, rental.AuditActionDelete, rental.AuditEntityCar, car.ID, car, nil)
+	s.publish(ctx, events.CarDeleted, car)
 	return ctx.NoContent(http.StatusNoContent)
 }
 
 // Find car by ID
 // (GET /car/{carId})
 func (s *Server) GetCarById(ctx echo.Context, carId int64) error {
-	car, err := s.CarCRUDService.Get(int(carId))
+	car, err := s.CarCRUDService.Get(ctx.Request().Context(), int(carId))
 	if err == rental.ErrCarNotFound {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	apiCar := toAPICar(car)
+	setETag(ctx, car.Version)
+	apiCar := toAPICar(ctx, car)
 	return ctx.JSON(http.StatusOK, apiCar)
 }
 
 // Updates a car
 // (PUT /car/{carId})
-func (s *Server) UpdateCar(ctx echo.Context, carId int64) error {
+func (s *Server) UpdateCar(ctx echo.Context, carId int64, params gen.UpdateCarParams) error {
 	CreateCar := gen.CreateUpdateCarRequest{}
 	if err := ctx.Bind(&CreateCar); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	car, err := s.CarCRUDService.Get(int(carId))
+	car, err := s.CarCRUDService.Get(ctx.Request().Context(), int(carId))
 	if err == rental.ErrCarNotFound {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	if err := checkIfMatch(params.IfMatch, car.Version); err != nil {
+		return err
+	}
 
 	// Update only updatable fields, ie. not CustomerID
+	before := car
 	car = rental.Car{
 		ID:         car.ID,
 		Make:       CreateCar.Make,
 		CustomerID: car.CustomerID,
 		Model:      CreateCar.Model,
 		Year:       CreateCar.Year,
+		Version:    car.Version,
 	}
 
-	err = s.CarCRUDService.Update(car)
+	err = s.CarCRUDService.Update(ctx.Request().Context(), car)
 	if err == rental.ErrCarNotFound {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
+	if err == rental.ErrCarVersionMismatch {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	apiCar := toAPICar(car)
+	car.Version++
+	s.audit(ctx, rental.AuditActionUpdate, rental.AuditEntityCar, car.ID, before, car)
+	setETag(ctx, car.Version)
+	apiCar := toAPICar(ctx, car)
 	return ctx.JSON(http.StatusOK, apiCar)
 }
 
-func (s *Server) rent(carID int, customerID int) error {
-	car, err := s.CarCRUDService.Get(carID)
+// errForbidden is returned when a principal acts on data it has no access to.
+var errForbidden = echo.NewHTTPError(http.StatusForbidden, "Insufficient permissions")
+
+// selfServiceCustomer returns the customer a request is restricted to, and
+// true if the principal wasn't granted staffScope, ie. it was only authorized
+// through self-service scopes and may only act on its own customer data.
+func selfServiceCustomer(ctx echo.Context, staffScope rental.Scope) (int, bool) {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok || principal.HasScope(staffScope) {
+		return 0, false
+	}
+	return principal.CustomerID, true
+}
+
+// rent rents a car to a customer, returns the car before and after renting.
+func (s *Server) rent(ctx context.Context, carID int, customerID int) (before, after rental.Car, err error) {
+	car, err := s.CarCRUDService.Get(ctx, carID)
 	if err != nil {
-		return err
+		return rental.Car{}, rental.Car{}, err
 	}
 
-	customer, err := s.CustomerCRUDService.Get(customerID)
+	customer, err := s.CustomerCRUDService.Get(ctx, customerID)
 	if err != nil {
-		return err
+		return rental.Car{}, rental.Car{}, err
 	}
 
+	before = car
 	if err := car.Rent(customer.ID); err != nil {
-		return err
+		return rental.Car{}, rental.Car{}, err
+	}
+	if err := s.CarCRUDService.Update(ctx, car); err != nil {
+		return rental.Car{}, rental.Car{}, err
 	}
-	return s.CarCRUDService.Update(car)
+	car.Version++
+	return before, car, nil
 }
 
 // Rent a car
 // (GET /car/{carId}/rent)
 func (s *Server) RentCar(ctx echo.Context, carId int64, params gen.RentCarParams) error {
-	err := s.rent(int(carId), int(params.CustomerId))
+	customerID, err := rentingCustomer(ctx, params.CustomerId, "customerId")
+	if err != nil {
+		return err
+	}
+
+	_, err = s.startRental(ctx, int(carId), customerID)
 	if err == rental.ErrCarNotFound {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
@@ -155,6 +243,9 @@ func (s *Server) RentCar(ctx echo.Context, carId int64, params gen.RentCarParams
 	if err == rental.ErrCarAlreadyRented {
 		return echo.NewHTTPError(http.StatusForbidden, err.Error())
 	}
+	if err == rental.ErrCarVersionMismatch {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -164,19 +255,27 @@ func (s *Server) RentCar(ctx echo.Context, carId int64, params gen.RentCarParams
 // Return a car
 // (GET /car/{carId}/return)
 func (s *Server) ReturnCar(ctx echo.Context, carId int64) error {
-	car, err := s.CarCRUDService.Get(int(carId))
+	car, err := s.CarCRUDService.Get(ctx.Request().Context(), int(carId))
 	if err == rental.ErrCarNotFound {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	err = car.Return()
+	if self, ok := selfServiceCustomer(ctx, rental.ScopeRentalsWrite); ok && car.RenterID() != self {
+		return errForbidden
+	}
+
+	err = s.returnCar(ctx, car)
+	if err == rental.ErrCarNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
 	if err == rental.ErrCarNotRented {
 		return echo.NewHTTPError(http.StatusForbidden, err.Error())
 	}
-
-	err = s.CarCRUDService.Update(car)
+	if err == rental.ErrCarVersionMismatch {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -192,56 +291,118 @@ func (s *Server) CreateCustomer(ctx echo.Context) error {
 	}
 	customer := rental.Customer{Name: createCustomer.Name}
 	var err error
-	customer.ID, err = s.CustomerCRUDService.Create(customer)
+	customer.ID, err = s.CustomerCRUDService.Create(ctx.Request().Context(), customer)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	s.audit(ctx, rental.AuditActionCreate, rental.AuditEntityCustomer, customer.ID, nil, customer)
 	apiCustomer := toAPICustomer(customer)
 	return ctx.JSON(http.StatusCreated, apiCustomer)
 }
 
 // Deletes a customer
 // (DELETE /customer/{customerId})
-func (s *Server) DeleteCustomer(ctx echo.Context, customerId int64) error {
-	err := s.CustomerCRUDService.Delete(int(customerId))
+func (s *Server) DeleteCustomer(ctx echo.Context, customerId int64, params gen.DeleteCustomerParams) error {
+	customer, err := s.CustomerCRUDService.Get(ctx.Request().Context(), int(customerId))
 	if err == rental.ErrCustomerNotFound {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	if err := checkIfMatch(params.IfMatch, customer.Version); err != nil {
+		return err
+	}
+	err = s.CustomerCRUDService.Delete(ctx.Request().Context(), customer.ID, customer.Version)
+	if err == rental.ErrCustomerNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err == rental.ErrCustomerHasRentals {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err == rental.ErrCustomerVersionMismatch {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	s.audit(ctx, rental.AuditActionDelete, rental.AuditEntityCustomer, customer.ID, customer, nil)
 	return ctx.NoContent(http.StatusNoContent)
 }
 
 // Find customer by ID
 // (GET /customer/{customerId})
 func (s *Server) GetCustomerById(ctx echo.Context, customerId int64) error {
-	customer, err := s.CustomerCRUDService.Get(int(customerId))
+	if self, ok := selfServiceCustomer(ctx, rental.ScopeCustomersRead); ok && int(customerId) != self {
+		return errForbidden
+	}
+	customer, err := s.CustomerCRUDService.Get(ctx.Request().Context(), int(customerId))
 	if err == rental.ErrCustomerNotFound {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	setETag(ctx, customer.Version)
 	apiCustomer := toAPICustomer(customer)
 	return ctx.JSON(http.StatusOK, apiCustomer)
 }
 
+// List the cars rented by a customer
+// (GET /customer/{customerId}/rentals)
+func (s *Server) ListCustomerRentals(ctx echo.Context, customerId int64) error {
+	if self, ok := selfServiceCustomer(ctx, rental.ScopeCustomersRead); ok && int(customerId) != self {
+		return errForbidden
+	}
+	customer, err := s.CustomerCRUDService.Get(ctx.Request().Context(), int(customerId))
+	if err == rental.ErrCustomerNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	cars, err := s.CarCRUDService.ListRentedBy(ctx.Request().Context(), customer.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiCars := make([]gen.Car, 0, len(cars))
+	for _, car := range cars {
+		apiCars = append(apiCars, toAPICar(ctx, car))
+	}
+	return ctx.JSON(http.StatusOK, apiCars)
+}
+
 // Updates a customer
 // (PUT /customer/{customerId})
-func (s *Server) UpdateCustomer(ctx echo.Context, customerId int64) error {
+func (s *Server) UpdateCustomer(ctx echo.Context, customerId int64, params gen.UpdateCustomerParams) error {
 	CreateCustomer := gen.CreateUpdateCustomerRequest{}
 	if err := ctx.Bind(&CreateCustomer); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	customer := rental.Customer{ID: int(customerId), Name: CreateCustomer.Name}
-	err := s.CustomerCRUDService.Update(customer)
+	before, err := s.CustomerCRUDService.Get(ctx.Request().Context(), int(customerId))
+	if err == rental.ErrCustomerNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if err := checkIfMatch(params.IfMatch, before.Version); err != nil {
+		return err
+	}
+	customer := rental.Customer{ID: before.ID, Name: CreateCustomer.Name, Version: before.Version}
+	err = s.CustomerCRUDService.Update(ctx.Request().Context(), customer)
 	if err == rental.ErrCustomerNotFound {
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
 	}
+	if err == rental.ErrCustomerVersionMismatch {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	customer.Version++
+	s.audit(ctx, rental.AuditActionUpdate, rental.AuditEntityCustomer, customer.ID, before, customer)
+	setETag(ctx, customer.Version)
 	apiCustomer := toAPICustomer(customer)
 	return ctx.JSON(http.StatusOK, apiCustomer)
 }
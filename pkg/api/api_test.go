@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -15,6 +16,18 @@ import (
 	"gopkg.in/guregu/null.v4"
 )
 
+// ifMatch returns an If-Match header value matching version.
+func ifMatch(version int) *string {
+	tag := etag(version)
+	return &tag
+}
+
+// rentCarParams returns the parameters renting a car to a customer.
+func rentCarParams(customerID int) gen.RentCarParams {
+	id := int64(customerID)
+	return gen.RentCarParams{CustomerId: &id}
+}
+
 func TestServer_CreateCar(t *testing.T) {
 	// Setup
 
@@ -42,7 +55,7 @@ func TestServer_CreateCar(t *testing.T) {
 
 	want := rental.Car{ID: 0, Make: createCar.Make, Model: createCar.Model, Year: createCar.Year}
 
-	got, err := s.CarCRUDService.Get(0) // The ID can't be specified through the API, so it defaults to 0.
+	got, err := s.CarCRUDService.Get(context.Background(), 0) // The ID can't be specified through the API, so it defaults to 0.
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
@@ -60,7 +73,7 @@ func TestServer_DeleteCar(t *testing.T) {
 	testCarID := 1
 
 	car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
-	if _, err := s.CarCRUDService.Create(car); err != nil {
+	if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
@@ -73,7 +86,7 @@ func TestServer_DeleteCar(t *testing.T) {
 
 	// Test
 
-	if err := s.DeleteCar(ctx, int64(testCarID)); err != nil {
+	if err := s.DeleteCar(ctx, int64(testCarID), gen.DeleteCarParams{IfMatch: ifMatch(car.Version)}); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
@@ -81,12 +94,42 @@ func TestServer_DeleteCar(t *testing.T) {
 		t.Errorf("got %d status code, want %d", resp.Code, http.StatusNoContent)
 	}
 
-	_, err := s.CarCRUDService.Get(testCarID)
+	_, err := s.CarCRUDService.Get(context.Background(), testCarID)
 	if err != rental.ErrCarNotFound {
 		t.Errorf("got error %v, want %v", err, rental.ErrCarNotFound)
 	}
 }
 
+func TestServer_DeleteCar_Rented(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{CarCRUDService: mock.NewMockCarCRUDService()}
+
+	car := rental.Car{ID: 1, CustomerID: null.IntFrom(1), Make: "Toyota", Model: "Corolla", Year: 2015}
+	if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	path := fmt.Sprintf("/car/%d", car.ID)
+	req := httptest.NewRequest(http.MethodDelete, path, nil)
+	resp := httptest.NewRecorder()
+	ctx := e.NewContext(req, resp)
+	ctx.SetPath(path)
+
+	// Test
+
+	err := s.DeleteCar(ctx, int64(car.ID), gen.DeleteCarParams{IfMatch: ifMatch(car.Version)})
+	he, ok := err.(*echo.HTTPError)
+	if !ok || he.Code != http.StatusConflict {
+		t.Errorf("got error %v, want %d status code", err, http.StatusConflict)
+	}
+
+	if _, err := s.CarCRUDService.Get(context.Background(), car.ID); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+}
+
 func TestServer_GetCarById(t *testing.T) {
 	// Setup
 
@@ -96,7 +139,7 @@ func TestServer_GetCarById(t *testing.T) {
 	testCarID := 1
 
 	car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
-	if _, err := s.CarCRUDService.Create(car); err != nil {
+	if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
@@ -136,7 +179,7 @@ func TestServer_UpdateCar(t *testing.T) {
 
 	// set CustomerID to make sure CustomerID is kept as-is when updating
 	car := rental.Car{ID: testCarID, Make: "Toyota", CustomerID: null.NewInt(int64(rentedToID), true), Model: "Corolla", Year: 2015}
-	if _, err := s.CarCRUDService.Create(car); err != nil {
+	if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
@@ -152,7 +195,7 @@ func TestServer_UpdateCar(t *testing.T) {
 
 	// Test
 
-	if err := s.UpdateCar(ctx, int64(testCarID)); err != nil {
+	if err := s.UpdateCar(ctx, int64(testCarID), gen.UpdateCarParams{IfMatch: ifMatch(car.Version)}); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
@@ -165,12 +208,12 @@ func TestServer_UpdateCar(t *testing.T) {
 		t.Errorf("got %s, want %s", resp.Body.String(), wantBody)
 	}
 
-	got, err := s.CarCRUDService.Get(testCarID)
+	got, err := s.CarCRUDService.Get(context.Background(), testCarID)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
-	want := rental.Car{ID: testCarID, Make: updateCar.Make, Model: updateCar.Model, CustomerID: null.NewInt(int64(rentedToID), true), Year: updateCar.Year}
+	want := rental.Car{ID: testCarID, Make: updateCar.Make, Model: updateCar.Model, CustomerID: null.NewInt(int64(rentedToID), true), Year: updateCar.Year, Version: car.Version + 1}
 	if got != want {
 		t.Errorf("got %v, want %v", got, want)
 	}
@@ -183,18 +226,18 @@ func TestServer_RentCar(t *testing.T) {
 		e := echo.New()
 		carCRUDService := mock.NewMockCarCRUDService()
 		customerCRUDService := mock.NewMockCustomerCRUDService()
-		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService}
+		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService, RentalService: mock.NewMockRentalService(), Transactor: mock.NewMockTransactor()}
 
 		testCarID := 1
 
 		testCustomerID := 1
 
 		car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
-		if _, err := s.CarCRUDService.Create(car); err != nil {
+		if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
 		customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
-		if _, err := s.CustomerCRUDService.Create(customer); err != nil {
+		if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
 
@@ -207,14 +250,14 @@ func TestServer_RentCar(t *testing.T) {
 
 		// Test
 
-		if err := s.RentCar(ctx, int64(testCarID), gen.RentCarParams{CustomerId: int64(testCustomerID)}); err != nil {
+		if err := s.RentCar(ctx, int64(testCarID), rentCarParams(testCustomerID)); err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
 		if resp.Code != http.StatusNoContent {
 			t.Errorf("got %d status code, want %d", resp.Code, http.StatusNoContent)
 		}
 
-		rentedCar, err := s.CarCRUDService.Get(testCarID)
+		rentedCar, err := s.CarCRUDService.Get(context.Background(), testCarID)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
@@ -228,17 +271,17 @@ func TestServer_RentCar(t *testing.T) {
 		e := echo.New()
 		carCRUDService := mock.NewMockCarCRUDService()
 		customerCRUDService := mock.NewMockCustomerCRUDService()
-		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService}
+		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService, RentalService: mock.NewMockRentalService(), Transactor: mock.NewMockTransactor()}
 
 		testCarID := 1
 		testCustomerID := 1
 
 		car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015, CustomerID: null.IntFrom(int64(testCustomerID))}
-		if _, err := s.CarCRUDService.Create(car); err != nil {
+		if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
 		customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
-		if _, err := s.CustomerCRUDService.Create(customer); err != nil {
+		if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
 
@@ -251,7 +294,7 @@ func TestServer_RentCar(t *testing.T) {
 
 		// Test
 
-		err := s.RentCar(ctx, int64(testCarID), gen.RentCarParams{CustomerId: int64(testCustomerID)})
+		err := s.RentCar(ctx, int64(testCarID), rentCarParams(testCustomerID))
 		if err == nil {
 			t.Errorf("got nil, want error")
 		}
@@ -268,12 +311,12 @@ func TestServer_RentCar(t *testing.T) {
 		e := echo.New()
 		carCRUDService := mock.NewMockCarCRUDService()
 		customerCRUDService := mock.NewMockCustomerCRUDService()
-		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService}
+		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService, RentalService: mock.NewMockRentalService(), Transactor: mock.NewMockTransactor()}
 
 		testCarID := 1
 
 		car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
-		if _, err := s.CarCRUDService.Create(car); err != nil {
+		if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
 
@@ -286,7 +329,7 @@ func TestServer_RentCar(t *testing.T) {
 
 		// Test
 
-		err := s.RentCar(ctx, int64(testCarID), gen.RentCarParams{CustomerId: int64(1)})
+		err := s.RentCar(ctx, int64(testCarID), rentCarParams(1))
 		if err == nil {
 			t.Errorf("got nil, want error")
 		}
@@ -307,17 +350,17 @@ func TestServer_ReturnCar(t *testing.T) {
 		e := echo.New()
 		carCRUDService := mock.NewMockCarCRUDService()
 		customerCRUDService := mock.NewMockCustomerCRUDService()
-		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService}
+		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService, RentalService: mock.NewMockRentalService(), Transactor: mock.NewMockTransactor()}
 
 		testCarID := 1
 		testCustomerID := 1
 
 		car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015, CustomerID: null.IntFrom(int64(testCustomerID))}
-		if _, err := s.CarCRUDService.Create(car); err != nil {
+		if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
 		customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
-		if _, err := s.CustomerCRUDService.Create(customer); err != nil {
+		if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
 
@@ -336,7 +379,7 @@ func TestServer_ReturnCar(t *testing.T) {
 			t.Errorf("got error %v, want nil", err)
 		}
 
-		rentedCar, err := s.CarCRUDService.Get(testCarID)
+		rentedCar, err := s.CarCRUDService.Get(context.Background(), testCarID)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
@@ -350,12 +393,12 @@ func TestServer_ReturnCar(t *testing.T) {
 		e := echo.New()
 		carCRUDService := mock.NewMockCarCRUDService()
 		customerCRUDService := mock.NewMockCustomerCRUDService()
-		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService}
+		s := &Server{CarCRUDService: carCRUDService, CustomerCRUDService: customerCRUDService, RentalService: mock.NewMockRentalService(), Transactor: mock.NewMockTransactor()}
 
 		testCarID := 1
 
 		car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
-		if _, err := s.CarCRUDService.Create(car); err != nil {
+		if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
 
@@ -407,7 +450,7 @@ func TestServer_CreateCustomer(t *testing.T) {
 	}
 
 	want := rental.Customer{ID: 0, Name: createCustomer.Name}
-	got, err := s.CustomerCRUDService.Get(0) // The ID can't be specified through the API, so it defaults to 0.
+	got, err := s.CustomerCRUDService.Get(context.Background(), 0) // The ID can't be specified through the API, so it defaults to 0.
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
@@ -425,7 +468,7 @@ func TestServer_DeleteCustomer(t *testing.T) {
 	testCustomerID := 1
 
 	customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
-	if _, err := s.CustomerCRUDService.Create(customer); err != nil {
+	if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
@@ -438,14 +481,14 @@ func TestServer_DeleteCustomer(t *testing.T) {
 
 	// Test
 
-	if err := s.DeleteCustomer(ctx, int64(testCustomerID)); err != nil {
+	if err := s.DeleteCustomer(ctx, int64(testCustomerID), gen.DeleteCustomerParams{IfMatch: ifMatch(customer.Version)}); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 	if resp.Code != http.StatusNoContent {
 		t.Errorf("got %d status code, want %d", resp.Code, http.StatusNoContent)
 	}
 
-	_, err := s.CustomerCRUDService.Get(testCustomerID)
+	_, err := s.CustomerCRUDService.Get(context.Background(), testCustomerID)
 	if err != rental.ErrCustomerNotFound {
 		t.Errorf("got error %v, want %v", err, rental.ErrCustomerNotFound)
 	}
@@ -459,7 +502,7 @@ func TestServer_GetCustomerById(t *testing.T) {
 	testCustomerID := 1
 
 	customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
-	if _, err := s.CustomerCRUDService.Create(customer); err != nil {
+	if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
@@ -479,7 +522,7 @@ func TestServer_GetCustomerById(t *testing.T) {
 		t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
 	}
 
-	got, err := s.CustomerCRUDService.Get(testCustomerID)
+	got, err := s.CustomerCRUDService.Get(context.Background(), testCustomerID)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
@@ -497,7 +540,7 @@ func TestServer_UpdateCustomer(t *testing.T) {
 	testCustomerID := 1
 
 	customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
-	if _, err := s.CustomerCRUDService.Create(customer); err != nil {
+	if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
@@ -513,7 +556,7 @@ func TestServer_UpdateCustomer(t *testing.T) {
 
 	// Test
 
-	if err := s.UpdateCustomer(ctx, int64(testCustomerID)); err != nil {
+	if err := s.UpdateCustomer(ctx, int64(testCustomerID), gen.UpdateCustomerParams{IfMatch: ifMatch(customer.Version)}); err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
@@ -521,12 +564,12 @@ func TestServer_UpdateCustomer(t *testing.T) {
 		t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
 	}
 
-	got, err := s.CustomerCRUDService.Get(testCustomerID)
+	got, err := s.CustomerCRUDService.Get(context.Background(), testCustomerID)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
-	want := rental.Customer{ID: testCustomerID, Name: updateCustomer.Name}
+	want := rental.Customer{ID: testCustomerID, Name: updateCustomer.Name, Version: customer.Version + 1}
 	if got != want {
 		t.Errorf("got %v, want %v", got, customer)
 	}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+// toAPIAPIKey converts a rental.APIKey to an api.APIKey and deals with nullable fields.
+func toAPIAPIKey(key rental.APIKey) gen.APIKey {
+	scopes := []string{}
+	for _, scope := range key.Scopes() {
+		scopes = append(scopes, string(scope))
+	}
+	return gen.APIKey{
+		Id:         int64(key.ID),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt.Ptr(),
+		LastUsedAt: key.LastUsedAt.Ptr(),
+		RevokedAt:  key.RevokedAt.Ptr(),
+	}
+}
+
+// List API keys
+// (GET /apikey)
+func (s *Server) ListAPIKeys(ctx echo.Context) error {
+	keys, err := s.APIKeyService.List(ctx.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiKeys := make([]gen.APIKey, 0, len(keys))
+	for _, key := range keys {
+		apiKeys = append(apiKeys, toAPIAPIKey(key))
+	}
+	return ctx.JSON(http.StatusOK, apiKeys)
+}
+
+// Create a new API key
+// (POST /apikey)
+func (s *Server) CreateAPIKey(ctx echo.Context) error {
+	createAPIKey := gen.CreateAPIKeyRequest{}
+	if err := ctx.Bind(&createAPIKey); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	scopes := make([]rental.Scope, 0, len(createAPIKey.Scopes))
+	for _, scope := range createAPIKey.Scopes {
+		scopes = append(scopes, rental.Scope(scope))
+	}
+	key, apiKey, err := rental.NewAPIKey(createAPIKey.Name, scopes, null.TimeFromPtr(createAPIKey.ExpiresAt))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Keys can't be used to escalate privileges
+	principal, _ := auth.PrincipalFrom(ctx)
+	for _, scope := range scopes {
+		if !principal.HasScope(scope) {
+			return echo.NewHTTPError(http.StatusForbidden, "Insufficient permissions to grant scope "+string(scope))
+		}
+	}
+
+	apiKey.CreatedBy = actor(ctx)
+	apiKey.ID, err = s.APIKeyService.Create(ctx.Request().Context(), apiKey)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiKey, err = s.APIKeyService.Get(ctx.Request().Context(), apiKey.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	s.audit(ctx, rental.AuditActionCreate, rental.AuditEntityAPIKey, apiKey.ID, nil, apiKey)
+	return ctx.JSON(http.StatusCreated, gen.CreatedAPIKey{Key: key, ApiKey: toAPIAPIKey(apiKey)})
+}
+
+// Find API key by ID
+// (GET /apikey/{apiKeyId})
+func (s *Server) GetAPIKeyById(ctx echo.Context, apiKeyId int64) error {
+	key, err := s.APIKeyService.Get(ctx.Request().Context(), int(apiKeyId))
+	if err == rental.ErrAPIKeyNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return ctx.JSON(http.StatusOK, toAPIAPIKey(key))
+}
+
+// Revokes an API key
+// (DELETE /apikey/{apiKeyId})
+func (s *Server) RevokeAPIKey(ctx echo.Context, apiKeyId int64) error {
+	before, err := s.APIKeyService.Get(ctx.Request().Context(), int(apiKeyId))
+	if err == rental.ErrAPIKeyNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if err := s.APIKeyService.Revoke(ctx.Request().Context(), before.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	after, err := s.APIKeyService.Get(ctx.Request().Context(), before.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	s.audit(ctx, rental.AuditActionRevoke, rental.AuditEntityAPIKey, before.ID, before, after)
+	return ctx.NoContent(http.StatusNoContent)
+}
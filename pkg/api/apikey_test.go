@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestServer_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     rental.Role
+		scopes   []string
+		wantCode int
+	}{
+		{name: "create an API key", role: rental.RoleAdmin, scopes: []string{"cars:read", "rentals:write"}, wantCode: http.StatusCreated},
+		{name: "create an API key with an invalid scope", role: rental.RoleAdmin, scopes: []string{"cars:drive"}, wantCode: http.StatusBadRequest},
+		{name: "create an API key with scopes beyond the caller's", role: rental.RoleReadOnly, scopes: []string{"cars:write"}, wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			e := echo.New()
+			s := &Server{APIKeyService: mock.NewMockAPIKeyService()}
+
+			createAPIKey := gen.CreateAPIKeyRequest{Name: "billing", Scopes: tt.scopes}
+			createAPIKeyJSON, _ := json.Marshal(createAPIKey)
+
+			req := httptest.NewRequest(http.MethodPost, "/apikey", bytes.NewBuffer(createAPIKeyJSON))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+			ctx := e.NewContext(req, resp)
+			ctx.SetPath("/apikey")
+			auth.SetPrincipal(ctx, auth.Principal{Subject: "jane", Role: tt.role, Scopes: tt.role.Scopes()})
+
+			// Test
+
+			err := s.CreateAPIKey(ctx)
+			if tt.wantCode != http.StatusCreated {
+				he, ok := err.(*echo.HTTPError)
+				if !ok || he.Code != tt.wantCode {
+					t.Errorf("got error %v, want %d status code", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if resp.Code != http.StatusCreated {
+				t.Errorf("got %d status code, want %d", resp.Code, http.StatusCreated)
+			}
+
+			var got gen.CreatedAPIKey
+			if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+			stored, err := s.APIKeyService.GetByHash(context.Background(), rental.HashAPIKey(got.Key))
+			if err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+			if stored.ID != int(got.ApiKey.Id) || stored.CreatedBy != "jane" || stored.Scope != "cars:read rentals:write" {
+				t.Errorf("got %v, want the API key created by jane", stored)
+			}
+		})
+	}
+}
+
+func TestServer_RevokeAPIKey(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{APIKeyService: mock.NewMockAPIKeyService()}
+	id, err := s.APIKeyService.Create(context.Background(), rental.APIKey{Name: "billing", KeyHash: "a3f1c2", Scope: "cars:read"})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/apikey/:apiKeyId", nil)
+	resp := httptest.NewRecorder()
+	ctx := e.NewContext(req, resp)
+
+	// Test
+
+	if err := s.RevokeAPIKey(ctx, int64(id)); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if resp.Code != http.StatusNoContent {
+		t.Errorf("got %d status code, want %d", resp.Code, http.StatusNoContent)
+	}
+	got, err := s.APIKeyService.Get(context.Background(), id)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if got.Valid(time.Now()) {
+		t.Errorf("got valid API key, want revoked API key")
+	}
+
+	err = s.RevokeAPIKey(ctx, int64(id+1))
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusNotFound {
+		t.Errorf("got error %v, want %d status code", err, http.StatusNotFound)
+	}
+}
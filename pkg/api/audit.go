@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// actor returns the name of the principal performing the request.
+func actor(ctx echo.Context) string {
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		return principal.Subject
+	}
+	return "anonymous"
+}
+
+// audit records an action performed on an entity in the audit log, if
+// enabled. A nil before or after state means the entity did not exist
+// before or after the action. The action has already been performed at this
+// point, so failures are logged rather than returned to the client.
+func (s *Server) audit(ctx echo.Context, action, entityType string, entityID int, before, after interface{}) {
+	if s.AuditLogService == nil {
+		return
+	}
+	entry, err := rental.NewAuditEntry(actor(ctx), action, entityType, entityID, before, after)
+	if err == nil {
+		_, err = s.AuditLogService.Record(ctx.Request().Context(), entry)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx.Request().Context(), "recording audit entry", "action", action, "entity_type", entityType, "entity_id", entityID, "error", err)
+	}
+}
+
+// toAPIAuditEntry converts a rental.AuditEntry to an api.AuditEntry.
+func toAPIAuditEntry(entry rental.AuditEntry) (gen.AuditEntry, error) {
+	apiEntry := gen.AuditEntry{
+		Id:         int64(entry.ID),
+		Actor:      entry.Actor,
+		Action:     gen.AuditEntryAction(entry.Action),
+		EntityType: gen.AuditEntryEntityType(entry.EntityType),
+		EntityId:   int64(entry.EntityID),
+		CreatedAt:  entry.CreatedAt,
+	}
+	if entry.Before != nil {
+		if err := json.Unmarshal(entry.Before, &apiEntry.Before); err != nil {
+			return gen.AuditEntry{}, err
+		}
+	}
+	if entry.After != nil {
+		if err := json.Unmarshal(entry.After, &apiEntry.After); err != nil {
+			return gen.AuditEntry{}, err
+		}
+	}
+	if err := json.Unmarshal(entry.Diff, &apiEntry.Diff); err != nil {
+		return gen.AuditEntry{}, err
+	}
+	return apiEntry, nil
+}
+
+// errAuditLogDisabled is returned by the audit endpoints when the audit log is disabled.
+var errAuditLogDisabled = echo.NewHTTPError(http.StatusNotImplemented, "Audit log disabled")
+
+// List audit entries
+// (GET /audit)
+func (s *Server) ListAuditEntries(ctx echo.Context, params gen.ListAuditEntriesParams) error {
+	if s.AuditLogService == nil {
+		return errAuditLogDisabled
+	}
+	var filter rental.AuditFilter
+	if params.Actor != nil {
+		filter.Actor = *params.Actor
+	}
+	if params.Action != nil {
+		filter.Action = *params.Action
+	}
+	if params.EntityType != nil {
+		filter.EntityType = *params.EntityType
+	}
+	if params.EntityId != nil {
+		filter.EntityID = int(*params.EntityId)
+	}
+	if params.Since != nil {
+		filter.Since = *params.Since
+	}
+	if params.Until != nil {
+		filter.Until = *params.Until
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > 1000 {
+			return echo.NewHTTPError(http.StatusBadRequest, "limit must be between 1 and 1000")
+		}
+		filter.Limit = *params.Limit
+	}
+
+	entries, err := s.AuditLogService.List(ctx.Request().Context(), filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiEntries := make([]gen.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		apiEntry, err := toAPIAuditEntry(entry)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		apiEntries = append(apiEntries, apiEntry)
+	}
+	return ctx.JSON(http.StatusOK, apiEntries)
+}
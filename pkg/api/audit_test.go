@@ -0,0 +1,114 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestServer_UpdateCar_RecordsAuditEntry(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{CarCRUDService: mock.NewMockCarCRUDService(), AuditLogService: mock.NewMockAuditLogService()}
+
+	testCarID := 1
+
+	car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
+	if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	updateCar := gen.CreateUpdateCarRequest{Make: "Toyota", Model: "Corolla", Year: 2016}
+	updateCarJSON, _ := json.Marshal(updateCar)
+
+	path := fmt.Sprintf("/car/%d", testCarID)
+	req := httptest.NewRequest(http.MethodPut, path, bytes.NewBuffer(updateCarJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+	ctx := e.NewContext(req, resp)
+	ctx.SetPath(path)
+	auth.SetPrincipal(ctx, auth.Principal{Subject: "jane"})
+
+	// Test
+
+	if err := s.UpdateCar(ctx, int64(testCarID), gen.UpdateCarParams{IfMatch: ifMatch(car.Version)}); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	entries, err := s.AuditLogService.List(context.Background(), rental.AuditFilter{})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d audit entries, want 1", len(entries))
+	}
+	got := entries[0]
+	if got.Actor != "jane" || got.Action != rental.AuditActionUpdate || got.EntityType != rental.AuditEntityCar || got.EntityID != testCarID {
+		t.Errorf("got %v, want update of car %d by jane", got, testCarID)
+	}
+	wantDiff := `{"year":{"from":2015,"to":2016}}`
+	if string(got.Diff) != wantDiff {
+		t.Errorf("got diff %s, want %s", got.Diff, wantDiff)
+	}
+}
+
+func TestServer_ListAuditEntries_Disabled(t *testing.T) {
+	s := &Server{}
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/audit", nil), httptest.NewRecorder())
+
+	err := s.ListAuditEntries(ctx, gen.ListAuditEntriesParams{})
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusNotImplemented {
+		t.Errorf("got error %v, want %d status code", err, http.StatusNotImplemented)
+	}
+}
+
+func TestServer_ListAuditEntries(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{AuditLogService: mock.NewMockAuditLogService()}
+
+	for _, entityID := range []int{1, 2} {
+		entry, err := rental.NewAuditEntry("rental", rental.AuditActionDelete, rental.AuditEntityCustomer, entityID, rental.Customer{ID: entityID, Name: "John Doe"}, nil)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if _, err := s.AuditLogService.Record(context.Background(), entry); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?entityId=2", nil)
+	resp := httptest.NewRecorder()
+	ctx := e.NewContext(req, resp)
+	ctx.SetPath("/audit")
+
+	// Test
+
+	entityID := int64(2)
+	if err := s.ListAuditEntries(ctx, gen.ListAuditEntriesParams{EntityId: &entityID}); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if resp.Code != http.StatusOK {
+		t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+	}
+
+	var got []gen.AuditEntry
+	if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 1 || got[0].EntityId != entityID || got[0].Before == nil || got[0].After != nil {
+		t.Errorf("got %v, want deletion of customer %d", got, entityID)
+	}
+}
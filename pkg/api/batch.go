@@ -0,0 +1,299 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/events"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// maxBatchSize is the maximum number of entities fetched or operations performed in a batch.
+const maxBatchSize = 100
+
+// errBatchFailed aborts the transaction of a batch when one of its operations fails.
+var errBatchFailed = fmt.Errorf("Batch operation failed")
+
+// batchIDs converts the IDs of a batch get, at most maxBatchSize.
+func batchIDs(ids genv2.Ids) ([]int, error) {
+	if len(ids) > maxBatchSize {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("At most %d IDs can be fetched at once", maxBatchSize))
+	}
+	converted := make([]int, len(ids))
+	for i, id := range ids {
+		converted[i] = int(id)
+	}
+	return converted, nil
+}
+
+// Find cars by IDs
+// (GET /car)
+func (s *ServerV2) GetCarsByIds(ctx echo.Context, params genv2.GetCarsByIdsParams) error {
+	ids, err := batchIDs(params.Ids)
+	if err != nil {
+		return err
+	}
+	cars, err := s.CarCRUDService.GetMany(ctx.Request().Context(), ids)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiCars := make([]genv2.Car, len(cars))
+	for i, car := range cars {
+		apiCars[i] = genv2.Car(toAPICar(ctx, car))
+	}
+	return ctx.JSON(http.StatusOK, apiCars)
+}
+
+// Find customers by IDs
+// (GET /customer)
+func (s *ServerV2) GetCustomersByIds(ctx echo.Context, params genv2.GetCustomersByIdsParams) error {
+	ids, err := batchIDs(params.Ids)
+	if err != nil {
+		return err
+	}
+	if self, ok := selfServiceCustomer(ctx, rental.ScopeCustomersRead); ok {
+		for _, id := range ids {
+			if id != self {
+				return errForbidden
+			}
+		}
+	}
+	customers, err := s.CustomerCRUDService.GetMany(ctx.Request().Context(), ids)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiCustomers := make([]genv2.Customer, len(customers))
+	for i, customer := range customers {
+		apiCustomers[i] = genv2.Customer(toAPICustomer(customer))
+	}
+	return ctx.JSON(http.StatusOK, apiCustomers)
+}
+
+// Perform operations in a batch
+// (POST /batch)
+func (s *ServerV2) Batch(ctx echo.Context) error {
+	batch := genv2.BatchRequest{}
+	if err := ctx.Bind(&batch); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if len(batch.Operations) == 0 || len(batch.Operations) > maxBatchSize {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("A batch must have between 1 and %d operations", maxBatchSize))
+	}
+
+	// The operations are audited and published once the transaction is
+	// committed, as they would be rolled back otherwise
+	results := make([]genv2.BatchResult, len(batch.Operations))
+	var effects []func()
+	failed := false
+	err := s.Transactor.WithinTransaction(ctx.Request().Context(), func(txCtx context.Context) error {
+		for i, operation := range batch.Operations {
+			result, effect, err := s.batchOperation(ctx, txCtx, operation)
+			if he, ok := err.(*echo.HTTPError); ok && he.Code < http.StatusInternalServerError {
+				message := fmt.Sprint(he.Message)
+				results[i] = genv2.BatchResult{Status: he.Code, Error: &message}
+				failed = true
+				return errBatchFailed
+			}
+			if err != nil {
+				return err
+			}
+			results[i] = result
+			effects = append(effects, effect)
+		}
+		return nil
+	})
+	if failed {
+		for i, result := range results {
+			if result.Error == nil {
+				results[i] = genv2.BatchResult{Status: http.StatusFailedDependency}
+			}
+		}
+		return ctx.JSON(http.StatusUnprocessableEntity, genv2.BatchResponse{Results: results})
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for _, effect := range effects {
+		effect()
+	}
+	return ctx.JSON(http.StatusOK, genv2.BatchResponse{Results: results})
+}
+
+// batchOperation performs an operation of a batch with the services in the
+// transaction of txCtx, and returns its result and its side effects, to
+// apply once the transaction is committed. Failures are returned as
+// *echo.HTTPError.
+func (s *ServerV2) batchOperation(ctx echo.Context, txCtx context.Context, operation genv2.BatchOperation) (genv2.BatchResult, func(), error) {
+	scope := rental.ScopeCarsWrite
+	if operation.Entity == genv2.BatchOperationEntityCustomer {
+		scope = rental.ScopeCustomersWrite
+	}
+	if principal, ok := auth.PrincipalFrom(ctx); ok && !principal.HasScope(scope) {
+		return genv2.BatchResult{}, nil, errForbidden
+	}
+	if operation.Action != genv2.BatchOperationActionCreate && operation.Id == nil {
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, "id is required")
+	}
+
+	switch operation.Entity {
+	case genv2.BatchOperationEntityCar:
+		return s.batchCarOperation(ctx, txCtx, operation)
+	case genv2.BatchOperationEntityCustomer:
+		return s.batchCustomerOperation(ctx, txCtx, operation)
+	default:
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid entity")
+	}
+}
+
+// batchCarOperation performs an operation of a batch on a car.
+func (s *ServerV2) batchCarOperation(ctx echo.Context, txCtx context.Context, operation genv2.BatchOperation) (genv2.BatchResult, func(), error) {
+	if operation.Action == genv2.BatchOperationActionCreate {
+		if operation.Car == nil {
+			return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, "car is required")
+		}
+		car := rental.Car{Make: operation.Car.Make, Model: operation.Car.Model, Year: operation.Car.Year}
+		if err := car.Validate(); err != nil {
+			return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		var err error
+		car.ID, err = s.CarCRUDService.Create(txCtx, car)
+		if err != nil {
+			return genv2.BatchResult{}, nil, err
+		}
+		apiCar := genv2.Car(toAPICar(ctx, car))
+		return genv2.BatchResult{Status: http.StatusCreated, Car: &apiCar}, func() {
+			s.audit(ctx, rental.AuditActionCreate, rental.AuditEntityCar, car.ID, nil, car)
+			s.publish(ctx, events.CarCreated, car)
+		}, nil
+	}
+
+	car, err := s.CarCRUDService.Get(txCtx, int(*operation.Id))
+	if err == rental.ErrCarNotFound {
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return genv2.BatchResult{}, nil, err
+	}
+	if err := checkIfMatch(operation.IfMatch, car.Version); err != nil {
+		return genv2.BatchResult{}, nil, err
+	}
+	before := car
+
+	switch operation.Action {
+	case genv2.BatchOperationActionUpdate:
+		if operation.Car == nil {
+			return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, "car is required")
+		}
+		// Update only updatable fields, ie. not CustomerID
+		car.Make, car.Model, car.Year = operation.Car.Make, operation.Car.Model, operation.Car.Year
+		if err := car.Validate(); err != nil {
+			return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		err = s.CarCRUDService.Update(txCtx, car)
+	case genv2.BatchOperationActionDelete:
+		err = s.CarCRUDService.Delete(txCtx, car.ID, car.Version)
+	default:
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid action")
+	}
+	if err == rental.ErrCarNotFound {
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err == rental.ErrCarVersionMismatch {
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+	if err == rental.ErrCarHasRentals {
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return genv2.BatchResult{}, nil, err
+	}
+
+	if operation.Action == genv2.BatchOperationActionDelete {
+		return genv2.BatchResult{Status: http.StatusNoContent}, func() {
+			s.audit(ctx, rental.AuditActionDelete, rental.AuditEntityCar, car.ID, car, nil)
+			s.publish(ctx, events.CarDeleted, car)
+		}, nil
+	}
+	car.Version++
+	apiCar, tag := genv2.Car(toAPICar(ctx, car)), etag(car.Version)
+	return genv2.BatchResult{Status: http.StatusOK, Car: &apiCar, Etag: &tag}, func() {
+		s.audit(ctx, rental.AuditActionUpdate, rental.AuditEntityCar, car.ID, before, car)
+	}, nil
+}
+
+// batchCustomerOperation performs an operation of a batch on a customer.
+func (s *ServerV2) batchCustomerOperation(ctx echo.Context, txCtx context.Context, operation genv2.BatchOperation) (genv2.BatchResult, func(), error) {
+	if operation.Action == genv2.BatchOperationActionCreate {
+		if operation.Customer == nil {
+			return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, "customer is required")
+		}
+		customer := rental.Customer{Name: operation.Customer.Name}
+		if err := customer.Validate(); err != nil {
+			return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		var err error
+		customer.ID, err = s.CustomerCRUDService.Create(txCtx, customer)
+		if err != nil {
+			return genv2.BatchResult{}, nil, err
+		}
+		apiCustomer := genv2.Customer(toAPICustomer(customer))
+		return genv2.BatchResult{Status: http.StatusCreated, Customer: &apiCustomer}, func() {
+			s.audit(ctx, rental.AuditActionCreate, rental.AuditEntityCustomer, customer.ID, nil, customer)
+		}, nil
+	}
+
+	customer, err := s.CustomerCRUDService.Get(txCtx, int(*operation.Id))
+	if err == rental.ErrCustomerNotFound {
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return genv2.BatchResult{}, nil, err
+	}
+	if err := checkIfMatch(operation.IfMatch, customer.Version); err != nil {
+		return genv2.BatchResult{}, nil, err
+	}
+	before := customer
+
+	switch operation.Action {
+	case genv2.BatchOperationActionUpdate:
+		if operation.Customer == nil {
+			return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, "customer is required")
+		}
+		customer.Name = operation.Customer.Name
+		if err := customer.Validate(); err != nil {
+			return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		err = s.CustomerCRUDService.Update(txCtx, customer)
+	case genv2.BatchOperationActionDelete:
+		err = s.CustomerCRUDService.Delete(txCtx, customer.ID, customer.Version)
+	default:
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid action")
+	}
+	if err == rental.ErrCustomerNotFound {
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err == rental.ErrCustomerVersionMismatch {
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+	}
+	if err == rental.ErrCustomerHasRentals {
+		return genv2.BatchResult{}, nil, echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return genv2.BatchResult{}, nil, err
+	}
+
+	if operation.Action == genv2.BatchOperationActionDelete {
+		return genv2.BatchResult{Status: http.StatusNoContent}, func() {
+			s.audit(ctx, rental.AuditActionDelete, rental.AuditEntityCustomer, customer.ID, customer, nil)
+		}, nil
+	}
+	customer.Version++
+	apiCustomer, tag := genv2.Customer(toAPICustomer(customer)), etag(customer.Version)
+	return genv2.BatchResult{Status: http.StatusOK, Customer: &apiCustomer, Etag: &tag}, func() {
+		s.audit(ctx, rental.AuditActionUpdate, rental.AuditEntityCustomer, customer.ID, before, customer)
+	}, nil
+}
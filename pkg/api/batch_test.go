@@ -0,0 +1,268 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestServerV2_GetCarsByIds(t *testing.T) {
+	// Setup
+
+	s := NewServerV2(newSelfServiceTestServer(t))
+	resp := httptest.NewRecorder()
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v2/car?ids=2,3,1", nil), resp)
+
+	// Test
+
+	if err := s.GetCarsByIds(ctx, genv2.GetCarsByIdsParams{Ids: genv2.Ids{2, 3, 1}}); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	var got []genv2.Car
+	if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if len(got) != 2 || got[0].Id != 1 || got[1].Id != 2 {
+		t.Errorf("got %v, want cars 1 and 2", got)
+	}
+
+	t.Run("too many IDs", func(t *testing.T) {
+		ids := make(genv2.Ids, maxBatchSize+1)
+		err := s.GetCarsByIds(ctx, genv2.GetCarsByIdsParams{Ids: ids})
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusBadRequest {
+			t.Errorf("got error %v, want %d status code", err, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestServerV2_GetCustomersByIds_SelfService(t *testing.T) {
+	s := NewServerV2(newSelfServiceTestServer(t))
+	tests := []struct {
+		name     string
+		ids      genv2.Ids
+		wantCode int
+	}{
+		{name: "get themselves", ids: genv2.Ids{1}, wantCode: http.StatusOK},
+		{name: "get another customer", ids: genv2.Ids{1, 2}, wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			ctx := newCustomerContext(httptest.NewRequest(http.MethodGet, "/v2/customer", nil), resp, 1)
+
+			err := s.GetCustomersByIds(ctx, genv2.GetCustomersByIdsParams{Ids: tt.ids})
+			if tt.wantCode != http.StatusOK {
+				if he, ok := err.(*echo.HTTPError); !ok || he.Code != tt.wantCode {
+					t.Errorf("got error %v, want %d status code", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			var got []genv2.Customer
+			if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if len(got) != 1 || got[0].Id != 1 {
+				t.Errorf("got %v, want customer 1", got)
+			}
+		})
+	}
+}
+
+func TestServerV2_Batch(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		principal    *auth.Principal
+		wantCode     int
+		wantStatuses []int
+	}{
+		{
+			name: "create, update and delete",
+			body: `{"operations": [
+				{"action": "create", "entity": "car", "car": {"make": "Renault", "model": "Clio", "year": 2020}},
+				{"action": "update", "entity": "car", "id": 1, "if_match": "\"0\"", "car": {"make": "Toyota", "model": "Yaris", "year": 2019}},
+				{"action": "delete", "entity": "customer", "id": 1, "if_match": "\"0\""}
+			]}`,
+			wantCode:     http.StatusOK,
+			wantStatuses: []int{http.StatusCreated, http.StatusOK, http.StatusNoContent},
+		},
+		{
+			name: "outdated version",
+			body: `{"operations": [
+				{"action": "create", "entity": "customer", "customer": {"name": "Jim Doe"}},
+				{"action": "update", "entity": "customer", "id": 1, "if_match": "\"3\"", "customer": {"name": "John Smith"}},
+				{"action": "delete", "entity": "car", "id": 1, "if_match": "\"0\""}
+			]}`,
+			wantCode:     http.StatusUnprocessableEntity,
+			wantStatuses: []int{http.StatusFailedDependency, http.StatusPreconditionFailed, http.StatusFailedDependency},
+		},
+		{
+			name: "missing car",
+			body: `{"operations": [
+				{"action": "delete", "entity": "car", "id": 3, "if_match": "\"0\""}
+			]}`,
+			wantCode:     http.StatusUnprocessableEntity,
+			wantStatuses: []int{http.StatusNotFound},
+		},
+		{
+			name: "rented car",
+			body: `{"operations": [
+				{"action": "delete", "entity": "car", "id": 1, "if_match": "\"0\""},
+				{"action": "delete", "entity": "car", "id": 2, "if_match": "\"0\""}
+			]}`,
+			wantCode:     http.StatusUnprocessableEntity,
+			wantStatuses: []int{http.StatusFailedDependency, http.StatusConflict},
+		},
+		{
+			name: "invalid car",
+			body: `{"operations": [
+				{"action": "create", "entity": "car", "car": {"make": "Renault", "model": "Clio", "year": -1}}
+			]}`,
+			wantCode:     http.StatusUnprocessableEntity,
+			wantStatuses: []int{http.StatusBadRequest},
+		},
+		{
+			name: "missing scope",
+			body: `{"operations": [
+				{"action": "create", "entity": "customer", "customer": {"name": "Jim Doe"}}
+			]}`,
+			principal:    &auth.Principal{Subject: "jane", Scopes: []rental.Scope{rental.ScopeCarsWrite}},
+			wantCode:     http.StatusUnprocessableEntity,
+			wantStatuses: []int{http.StatusForbidden},
+		},
+		{
+			name:     "empty batch",
+			body:     `{"operations": []}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			server := newSelfServiceTestServer(t)
+			server.Transactor = mock.NewMockTransactor()
+			s := NewServerV2(server)
+			req := httptest.NewRequest(http.MethodPost, "/v2/batch", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, resp)
+			if tt.principal != nil {
+				auth.SetPrincipal(ctx, *tt.principal)
+			}
+
+			// Test
+
+			err := s.Batch(ctx)
+			if tt.wantStatuses == nil {
+				if he, ok := err.(*echo.HTTPError); !ok || he.Code != tt.wantCode {
+					t.Errorf("got error %v, want %d status code", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("got %d status code, want %d", resp.Code, tt.wantCode)
+			}
+			var got genv2.BatchResponse
+			if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			var statuses []int
+			for _, result := range got.Results {
+				statuses = append(statuses, result.Status)
+			}
+			if !reflect.DeepEqual(statuses, tt.wantStatuses) {
+				t.Errorf("got statuses %v, want %v", statuses, tt.wantStatuses)
+			}
+		})
+	}
+}
+
+// keptCustomers refuses to delete customers, as if they had rentals.
+type keptCustomers struct {
+	*mock.MockCustomerCRUDService
+}
+
+func (s keptCustomers) Delete(ctx context.Context, customerID int, version int) error {
+	return rental.ErrCustomerHasRentals
+}
+
+func TestServerV2_Batch_CustomerWithRentals(t *testing.T) {
+	// Setup
+
+	server := newSelfServiceTestServer(t)
+	server.CustomerCRUDService = keptCustomers{server.CustomerCRUDService.(*mock.MockCustomerCRUDService)}
+	s := NewServerV2(server)
+	body := `{"operations": [{"action": "delete", "entity": "customer", "id": 1, "if_match": "\"0\""}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/batch", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	// Test
+
+	if err := s.Batch(echo.New().NewContext(req, resp)); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Errorf("got %d status code, want %d", resp.Code, http.StatusUnprocessableEntity)
+	}
+	var got genv2.BatchResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if len(got.Results) != 1 || got.Results[0].Status != http.StatusConflict {
+		t.Errorf("got %v, want a %d result", got.Results, http.StatusConflict)
+	}
+}
+
+func TestServerV2_Batch_Results(t *testing.T) {
+	// Setup
+
+	server := newSelfServiceTestServer(t)
+	server.Transactor = mock.NewMockTransactor()
+	s := NewServerV2(server)
+	body := `{"operations": [{"action": "update", "entity": "car", "id": 1, "if_match": "\"0\"", "car": {"make": "Toyota", "model": "Yaris", "year": 2019}}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/batch", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	// Test
+
+	if err := s.Batch(echo.New().NewContext(req, resp)); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	var got genv2.BatchResponse
+	if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	wantCar := genv2.Car{Id: 1, Make: "Toyota", Model: "Yaris", Year: 2019}
+	if len(got.Results) != 1 || got.Results[0].Car == nil || *got.Results[0].Car != wantCar {
+		t.Fatalf("got %v, want updated car %v", got.Results, wantCar)
+	}
+	if etag := got.Results[0].Etag; etag == nil || *etag != `"1"` {
+		t.Errorf("got ETag %v, want \"1\"", etag)
+	}
+	car, err := s.CarCRUDService.Get(context.Background(), 1)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if car.Model != "Yaris" {
+		t.Errorf("got %v, want car updated", car)
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// headerETag is the header carrying the version of an entity.
+const headerETag = "ETag"
+
+// etag returns the entity tag of a version of an entity.
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// setETag sets the ETag header of the response to the version of an entity.
+func setETag(ctx echo.Context, version int) {
+	ctx.Response().Header().Set(headerETag, etag(version))
+}
+
+// checkIfMatch checks that a change is based on the current version of an
+// entity, by comparing the entity tags of the If-Match header to its version.
+// Changes without If-Match are rejected, so that clients can't overwrite
+// changes they haven't seen.
+func checkIfMatch(ifMatch *string, version int) error {
+	if ifMatch == nil || *ifMatch == "" {
+		return echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header is required")
+	}
+	for _, tag := range strings.Split(*ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag(version) {
+			return nil
+		}
+	}
+	return echo.NewHTTPError(http.StatusPreconditionFailed, "If-Match doesn't match the current version")
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestServer_UpdateCar_Preconditions(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{CarCRUDService: mock.NewMockCarCRUDService()}
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015, Version: 3}
+	if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	update := func(params gen.UpdateCarParams) (*httptest.ResponseRecorder, error) {
+		updateCarJSON, _ := json.Marshal(gen.CreateUpdateCarRequest{Make: "Honda", Model: "Civic", Year: 2017})
+		req := httptest.NewRequest(http.MethodPut, "/car/1", bytes.NewBuffer(updateCarJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp := httptest.NewRecorder()
+		return resp, s.UpdateCar(e.NewContext(req, resp), 1, params)
+	}
+
+	// Test
+
+	t.Run("get returns the version", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/car/1", nil)
+		resp := httptest.NewRecorder()
+		if err := s.GetCarById(e.NewContext(req, resp), 1); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got := resp.Header().Get(headerETag); got != `"3"` {
+			t.Errorf("got ETag %s, want \"3\"", got)
+		}
+	})
+	t.Run("missing If-Match", func(t *testing.T) {
+		_, err := update(gen.UpdateCarParams{})
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusPreconditionRequired {
+			t.Errorf("got error %v, want %d status code", err, http.StatusPreconditionRequired)
+		}
+	})
+	t.Run("stale If-Match", func(t *testing.T) {
+		_, err := update(gen.UpdateCarParams{IfMatch: ifMatch(2)})
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusPreconditionFailed {
+			t.Errorf("got error %v, want %d status code", err, http.StatusPreconditionFailed)
+		}
+	})
+	t.Run("current If-Match", func(t *testing.T) {
+		tags := `"2", "3"`
+		resp, err := update(gen.UpdateCarParams{IfMatch: &tags})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got := resp.Header().Get(headerETag); got != `"4"` {
+			t.Errorf("got ETag %s, want \"4\"", got)
+		}
+	})
+}
+
+func TestServer_DeleteCustomer_Preconditions(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{CustomerCRUDService: mock.NewMockCustomerCRUDService()}
+	customer := rental.Customer{ID: 1, Name: "John Doe", Version: 2}
+	if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	deleteCustomer := func(params gen.DeleteCustomerParams) error {
+		req := httptest.NewRequest(http.MethodDelete, "/customer/1", nil)
+		return s.DeleteCustomer(e.NewContext(req, httptest.NewRecorder()), 1, params)
+	}
+
+	// Test
+
+	err := deleteCustomer(gen.DeleteCustomerParams{})
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusPreconditionRequired {
+		t.Errorf("got error %v, want %d status code", err, http.StatusPreconditionRequired)
+	}
+	err = deleteCustomer(gen.DeleteCustomerParams{IfMatch: ifMatch(1)})
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusPreconditionFailed {
+		t.Errorf("got error %v, want %d status code", err, http.StatusPreconditionFailed)
+	}
+	any := "*"
+	if err := deleteCustomer(gen.DeleteCustomerParams{IfMatch: &any}); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if _, err := s.CustomerCRUDService.Get(context.Background(), 1); err != rental.ErrCustomerNotFound {
+		t.Errorf("got error %v, want %v", err, rental.ErrCustomerNotFound)
+	}
+}
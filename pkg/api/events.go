@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/events"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// keepAliveInterval is the interval at which comments are sent on idle event
+// streams, to prevent proxies from closing the connection.
+const keepAliveInterval = 15 * time.Second
+
+// publish publishes a car event to the tenant of the request if the event stream is enabled.
+func (s *Server) publish(ctx echo.Context, eventType events.Type, car rental.Car) {
+	if s.Events != nil {
+		tenantID, _ := rental.TenantFrom(ctx.Request().Context())
+		s.Events.Publish(tenantID, eventType, car)
+	}
+}
+
+// publishImported publishes a summary of the cars imported to the tenant of
+// the request if the event stream is enabled.
+func (s *Server) publishImported(ctx echo.Context, carIDs []int) {
+	if s.Events != nil {
+		tenantID, _ := rental.TenantFrom(ctx.Request().Context())
+		s.Events.PublishImported(tenantID, carIDs)
+	}
+}
+
+// carsImportedEvent is the data of cars.imported events, the
+// CarsImportedEvent of the API spec.
+type carsImportedEvent struct {
+	Id     int64     `json:"id"`
+	Type   string    `json:"type"`
+	CarIds []int     `json:"carIds"`
+	Time   time.Time `json:"time"`
+}
+
+// toAPICarEvent converts an events.Event to an api.CarEvent, for the principal of ctx.
+func toAPICarEvent(ctx echo.Context, event events.Event) gen.CarEvent {
+	return gen.CarEvent{
+		Id:   int64(event.ID),
+		Type: gen.CarEventType(event.Type),
+		Car:  toAPICar(ctx, event.Car),
+		Time: event.Time,
+	}
+}
+
+// writeEvent writes an event to the stream of ctx in the Server-Sent Events format.
+func writeEvent(ctx echo.Context, event events.Event) error {
+	resp := ctx.Response()
+	var data []byte
+	var err error
+	if event.Type == events.CarsImported {
+		data, err = json.Marshal(carsImportedEvent{Id: int64(event.ID), Type: string(event.Type), CarIds: event.CarIDs, Time: event.Time})
+	} else {
+		data, err = json.Marshal(toAPICarEvent(ctx, event))
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(resp, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// Stream car status changes
+// (GET /events)
+func (s *Server) StreamEvents(ctx echo.Context, params gen.StreamEventsParams) error {
+	if s.Events == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, events.ErrBrokerClosed.Error())
+	}
+
+	var lastEventID uint64
+	if params.LastEventID != nil {
+		if *params.LastEventID < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid Last-Event-ID")
+		}
+		lastEventID = uint64(*params.LastEventID)
+	}
+
+	subscription, backlog, err := s.Events.Subscribe(lastEventID)
+	if err == events.ErrHistoryExpired {
+		// The client must refetch the cars, then stream without Last-Event-ID
+		return echo.NewHTTPError(http.StatusGone, err.Error())
+	}
+	if err == events.ErrBrokerClosed {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	defer subscription.Close()
+
+	// The broker carries the events of all tenants, only the events of the
+	// tenant of the request are streamed
+	tenantID, _ := rental.TenantFrom(ctx.Request().Context())
+
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.Header().Set("Connection", "keep-alive")
+	resp.Header().Set("X-Accel-Buffering", "no") // Disable response buffering in nginx ingress
+	resp.WriteHeader(http.StatusOK)
+
+	for _, event := range backlog {
+		if event.TenantID != tenantID {
+			continue
+		}
+		if err := writeEvent(ctx, event); err != nil {
+			return nil
+		}
+	}
+	resp.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	// Errors can't be reported once the stream has started, a failed write
+	// means the client went away so we simply stop streaming.
+	for {
+		select {
+		case <-ctx.Request().Context().Done():
+			return nil
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(resp, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			resp.Flush()
+		case event, ok := <-subscription.Events():
+			if !ok {
+				// Either the server is shutting down or the client is too
+				// slow, it can resume from the last event it received.
+				return nil
+			}
+			if event.TenantID != tenantID {
+				continue
+			}
+			if err := writeEvent(ctx, event); err != nil {
+				return nil
+			}
+			resp.Flush()
+		}
+	}
+}
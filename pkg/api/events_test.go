@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/events"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestServer_StreamEvents(t *testing.T) {
+	t.Run("replay the events of the tenant after the last event ID", func(t *testing.T) {
+		// Setup
+
+		e := echo.New()
+		s := &Server{CarCRUDService: mock.NewMockCarCRUDService(), Events: events.NewBroker(10, 10)}
+
+		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+		s.Events.Publish(1, events.CarCreated, car)
+		s.Events.Publish(1, events.CarRented, car)
+		s.Events.Publish(2, events.CarRented, rental.Car{ID: 2, Make: "Ford", Model: "Fiesta", Year: 2016})
+
+		// Cancel the request context beforehand so that the handler returns
+		// right after replaying the backlog.
+		reqCtx, cancel := context.WithCancel(rental.WithTenant(context.Background(), 1))
+		cancel()
+		req := httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(reqCtx)
+		resp := httptest.NewRecorder()
+		ctx := e.NewContext(req, resp)
+		ctx.SetPath("/events")
+
+		// Test
+
+		lastEventID := int64(1)
+		if err := s.StreamEvents(ctx, gen.StreamEventsParams{LastEventID: &lastEventID}); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if resp.Code != http.StatusOK {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+		}
+		if got := resp.Header().Get(echo.HeaderContentType); got != "text/event-stream" {
+			t.Errorf("got content type %s, want text/event-stream", got)
+		}
+
+		body := resp.Body.String()
+		if strings.Contains(body, "id: 1\n") {
+			t.Errorf("got event 1 in %q, want it skipped", body)
+		}
+		if !strings.Contains(body, "id: 2\nevent: car.rented\ndata: ") {
+			t.Errorf("got %q, want event 2 of type car.rented", body)
+		}
+		if strings.Contains(body, "id: 3\n") {
+			t.Errorf("got event 3 of another tenant in %q, want it skipped", body)
+		}
+	})
+	t.Run("resume after events trimmed from history", func(t *testing.T) {
+		// Setup
+
+		e := echo.New()
+		s := &Server{CarCRUDService: mock.NewMockCarCRUDService(), Events: events.NewBroker(2, 10)}
+
+		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+		s.Events.Publish(1, events.CarCreated, car)
+		s.Events.Publish(1, events.CarRented, car)
+		s.Events.Publish(1, events.CarReturned, car)
+		s.Events.Publish(1, events.CarDeleted, car)
+
+		req := httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(rental.WithTenant(context.Background(), 1))
+		ctx := e.NewContext(req, httptest.NewRecorder())
+		ctx.SetPath("/events")
+
+		// Test
+
+		lastEventID := int64(1)
+		err := s.StreamEvents(ctx, gen.StreamEventsParams{LastEventID: &lastEventID})
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusGone {
+			t.Errorf("got error %v, want %d status code", err, http.StatusGone)
+		}
+	})
+	t.Run("event stream disabled", func(t *testing.T) {
+		// Setup
+
+		e := echo.New()
+		s := &Server{CarCRUDService: mock.NewMockCarCRUDService()}
+
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		resp := httptest.NewRecorder()
+		ctx := e.NewContext(req, resp)
+		ctx.SetPath("/events")
+
+		// Test
+
+		err := s.StreamEvents(ctx, gen.StreamEventsParams{})
+		if err == nil {
+			t.Errorf("got nil, want error")
+		}
+
+		he, _ := err.(*echo.HTTPError)
+
+		got, want := he.Code, http.StatusServiceUnavailable
+		if got != want {
+			t.Errorf("got %d status code, want %d", got, want)
+		}
+	})
+}
+
+func TestServer_RentCar_PublishesEvent(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{
+		CarCRUDService:      mock.NewMockCarCRUDService(),
+		CustomerCRUDService: mock.NewMockCustomerCRUDService(),
+		RentalService:       mock.NewMockRentalService(),
+		Transactor:          mock.NewMockTransactor(),
+		Events:              events.NewBroker(10, 10),
+	}
+
+	testCarID := 1
+	testCustomerID := 1
+
+	car := rental.Car{ID: testCarID, Make: "Toyota", Model: "Corolla", Year: 2015}
+	if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	customer := rental.Customer{ID: testCustomerID, Name: "John Doe"}
+	if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	subscription, _, err := s.Events.Subscribe(0)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	defer subscription.Close()
+
+	path := fmt.Sprintf("/car/%d/rent", testCarID)
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	resp := httptest.NewRecorder()
+	ctx := e.NewContext(req, resp)
+	ctx.SetPath(path)
+
+	// Test
+
+	if err := s.RentCar(ctx, int64(testCarID), rentCarParams(testCustomerID)); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	event := <-subscription.Events()
+	if event.Type != events.CarRented || event.Car.RenterID() != testCustomerID {
+		t.Errorf("got %v, want %s event for car rented to %d", event, events.CarRented, testCustomerID)
+	}
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// Media types of the files of cars imported and exported.
+const (
+	mimeCSV    = "text/csv"
+	mimeNDJSON = "application/x-ndjson"
+)
+
+// Modes of car imports, when some rows are invalid.
+const (
+	importModeAtomic     = "atomic"
+	importModeBestEffort = "best_effort"
+)
+
+// Formats of car exports.
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+)
+
+// csvCarHeader is the header of exported CSV files.
+var csvCarHeader = []string{"id", "make", "model", "year", "renter_id"}
+
+// importedCar is a car read from a row of an imported file, err is set if the row is invalid.
+type importedCar struct {
+	line int
+	car  rental.Car
+	err  error
+}
+
+// Import cars
+// (POST /car/import)
+func (s *ServerV2) ImportCars(ctx echo.Context, params genv2.ImportCarsParams) error {
+	mode := importModeAtomic
+	if params.Mode != nil {
+		mode = string(*params.Mode)
+	}
+	if mode != importModeAtomic && mode != importModeBestEffort {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid import mode")
+	}
+
+	var rows []importedCar
+	var err error
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case mimeCSV:
+		rows, err = readCSVCars(ctx.Request().Body)
+	case mimeNDJSON:
+		rows, err = readNDJSONCars(ctx.Request().Body)
+	default:
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type must be "+mimeCSV+" or "+mimeNDJSON)
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	report := genv2.CarImportReport{Errors: []genv2.CarImportError{}}
+	cars := make([]rental.Car, 0, len(rows))
+	for _, row := range rows {
+		if row.err != nil {
+			report.Errors = append(report.Errors, genv2.CarImportError{Line: row.line, Message: row.err.Error()})
+			continue
+		}
+		cars = append(cars, row.car)
+	}
+	if len(report.Errors) > 0 && mode == importModeAtomic {
+		return ctx.JSON(http.StatusUnprocessableEntity, report)
+	}
+	if len(cars) > 0 {
+		// The cars are audited in the same transaction, in a batch rather
+		// than one by one, so that none is imported without its entry
+		var ids []int
+		err := s.Transactor.WithinTransaction(ctx.Request().Context(), func(txCtx context.Context) error {
+			var err error
+			ids, err = s.CarCRUDService.CreateMany(txCtx, cars)
+			if err != nil || s.AuditLogService == nil {
+				return err
+			}
+			entries := make([]rental.AuditEntry, 0, len(cars))
+			for i, car := range cars {
+				car.ID = ids[i]
+				entry, err := rental.NewAuditEntry(actor(ctx), rental.AuditActionCreate, rental.AuditEntityCar, car.ID, nil, car)
+				if err != nil {
+					return err
+				}
+				entries = append(entries, entry)
+			}
+			return s.AuditLogService.RecordMany(txCtx, entries)
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		s.publishImported(ctx, ids)
+	}
+	report.Imported = len(cars)
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// readCSVCars reads the cars of a CSV file, whose header names the make,
+// model and year columns.
+func readCSVCars(r io.Reader) ([]importedCar, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	makeColumn, hasMake := columns["make"]
+	modelColumn, hasModel := columns["model"]
+	yearColumn, hasYear := columns["year"]
+	if !hasMake || !hasModel || !hasYear {
+		return nil, fmt.Errorf("CSV header must name the make, model and year columns")
+	}
+
+	var rows []importedCar
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, importedCar{line: parseErr.Line, err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		row := importedCar{line: line}
+		row.car.Make = strings.TrimSpace(record[makeColumn])
+		row.car.Model = strings.TrimSpace(record[modelColumn])
+		row.car.Year, row.err = strconv.Atoi(strings.TrimSpace(record[yearColumn]))
+		if row.err != nil {
+			row.err = fmt.Errorf("Car year must be an integer")
+		} else {
+			row.err = row.car.Validate()
+		}
+		rows = append(rows, row)
+	}
+}
+
+// readNDJSONCars reads the cars of a NDJSON file, skipping blank lines.
+func readNDJSONCars(r io.Reader) ([]importedCar, error) {
+	var rows []importedCar
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		row := importedCar{line: line}
+		var car genv2.CreateUpdateCarRequest
+		if err := json.Unmarshal(scanner.Bytes(), &car); err != nil {
+			row.err = err
+		} else {
+			row.car = rental.Car{Make: car.Make, Model: car.Model, Year: car.Year}
+			row.err = row.car.Validate()
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+// Export cars
+// (GET /car/export)
+func (s *ServerV2) ExportCars(ctx echo.Context, params genv2.ExportCarsParams) error {
+	format := exportFormatNDJSON
+	if params.Format != nil {
+		format = string(*params.Format)
+	}
+
+	// Cars are written to the response as they are read from the database,
+	// through a buffer so that errors before the first flush are reported
+	resp := ctx.Response()
+	buffer := bufio.NewWriter(resp)
+	var write func(car rental.Car) error
+	switch format {
+	case exportFormatCSV:
+		resp.Header().Set(echo.HeaderContentType, mimeCSV)
+		writer := csv.NewWriter(buffer)
+		if err := writer.Write(csvCarHeader); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		write = func(car rental.Car) error {
+			apiCar := toAPICar(ctx, car)
+			writer.Write([]string{strconv.Itoa(car.ID), apiCar.Make, apiCar.Model, strconv.Itoa(apiCar.Year), strconv.Itoa(apiCar.RenterId)})
+			writer.Flush()
+			return writer.Error()
+		}
+	case exportFormatNDJSON:
+		resp.Header().Set(echo.HeaderContentType, mimeNDJSON)
+		encoder := json.NewEncoder(buffer)
+		write = func(car rental.Car) error {
+			return encoder.Encode(genv2.Car(toAPICar(ctx, car)))
+		}
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid export format")
+	}
+
+	err := s.CarCRUDService.ForEach(ctx.Request().Context(), write)
+	if err == nil {
+		err = buffer.Flush()
+	}
+	if err != nil && !resp.Committed {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if err != nil {
+		// The status was sent already, the client sees a truncated file
+		slog.ErrorContext(ctx.Request().Context(), "exporting cars", "error", err)
+		return nil
+	}
+	if !resp.Committed {
+		resp.WriteHeader(http.StatusOK)
+	}
+	return nil
+}
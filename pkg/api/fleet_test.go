@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+	"github.com/shidenkai0/rental/pkg/events"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestServerV2_ImportCars(t *testing.T) {
+	atomic, bestEffort := genv2.ImportCarsParamsMode("atomic"), genv2.ImportCarsParamsMode("best_effort")
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		mode        *genv2.ImportCarsParamsMode
+		wantCode    int
+		wantReport  genv2.CarImportReport
+	}{
+		{
+			name:        "import CSV",
+			contentType: "text/csv",
+			body:        "make,model,year\nToyota,Corolla,2015\nFord,Fiesta,2016\n",
+			wantCode:    http.StatusOK,
+			wantReport:  genv2.CarImportReport{Imported: 2, Errors: []genv2.CarImportError{}},
+		},
+		{
+			name:        "import exported CSV",
+			contentType: "text/csv; charset=utf-8",
+			body:        "id,make,model,year,renter_id\n1,Toyota,Corolla,2015,0\n",
+			wantCode:    http.StatusOK,
+			wantReport:  genv2.CarImportReport{Imported: 1, Errors: []genv2.CarImportError{}},
+		},
+		{
+			name:        "import NDJSON",
+			contentType: "application/x-ndjson",
+			body:        `{"make": "Toyota", "model": "Corolla", "year": 2015}` + "\n\n" + `{"id": 1, "make": "Ford", "model": "Fiesta", "year": 2016}`,
+			wantCode:    http.StatusOK,
+			wantReport:  genv2.CarImportReport{Imported: 2, Errors: []genv2.CarImportError{}},
+		},
+		{
+			name:        "import invalid rows atomically",
+			contentType: "text/csv",
+			body:        "make,model,year\nToyota,Corolla,2015\n,Fiesta,2016\nFord,Focus,new\n",
+			mode:        &atomic,
+			wantCode:    http.StatusUnprocessableEntity,
+			wantReport: genv2.CarImportReport{Errors: []genv2.CarImportError{
+				{Line: 3, Message: rental.ErrCarMakeEmpty.Error()},
+				{Line: 4, Message: "Car year must be an integer"},
+			}},
+		},
+		{
+			name:        "import invalid rows with best effort",
+			contentType: "application/x-ndjson",
+			body:        `{"make": "Toyota", "model": "Corolla", "year": 2015}` + "\n" + `{"make": "Ford", "model": "Fiesta", "year": -1}`,
+			mode:        &bestEffort,
+			wantCode:    http.StatusOK,
+			wantReport: genv2.CarImportReport{Imported: 1, Errors: []genv2.CarImportError{
+				{Line: 2, Message: rental.ErrInvalidCarYear.Error()},
+			}},
+		},
+		{
+			name:        "import CSV without year column",
+			contentType: "text/csv",
+			body:        "make,model\nToyota,Corolla\n",
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "import JSON",
+			contentType: "application/json",
+			body:        `[]`,
+			wantCode:    http.StatusUnsupportedMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			s := NewServerV2(&Server{CarCRUDService: mock.NewMockCarCRUDService(), Transactor: mock.NewMockTransactor()})
+			req := httptest.NewRequest(http.MethodPost, "/v2/car/import", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			resp := httptest.NewRecorder()
+			ctx := echo.New().NewContext(req, resp)
+
+			// Test
+
+			err := s.ImportCars(ctx, genv2.ImportCarsParams{Mode: tt.mode})
+			if he, ok := err.(*echo.HTTPError); ok {
+				if he.Code != tt.wantCode {
+					t.Errorf("got error %v, want %d status code", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("got %d status code, want %d", resp.Code, tt.wantCode)
+			}
+			var got genv2.CarImportReport
+			if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if !reflect.DeepEqual(got, tt.wantReport) {
+				t.Errorf("got %v, want %v", got, tt.wantReport)
+			}
+			imported := 0
+			s.CarCRUDService.ForEach(context.Background(), func(car rental.Car) error {
+				imported++
+				return nil
+			})
+			if imported != tt.wantReport.Imported {
+				t.Errorf("got %d cars, want %d", imported, tt.wantReport.Imported)
+			}
+		})
+	}
+}
+
+func TestServerV2_ImportCars_RecordsAuditEntriesAndEvents(t *testing.T) {
+	// Setup
+
+	s := NewServerV2(&Server{CarCRUDService: mock.NewMockCarCRUDService(), AuditLogService: mock.NewMockAuditLogService(), Transactor: mock.NewMockTransactor(), Events: events.NewBroker(10, 10)})
+	req := httptest.NewRequest(http.MethodPost, "/v2/car/import", strings.NewReader("make,model,year\nToyota,Corolla,2015\nFord,Fiesta,2016\n"))
+	req.Header.Set(echo.HeaderContentType, "text/csv")
+	ctx := echo.New().NewContext(req, httptest.NewRecorder())
+	subscription, _, err := s.Events.Subscribe(0)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	defer subscription.Close()
+
+	// Test
+
+	if err := s.ImportCars(ctx, genv2.ImportCarsParams{}); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	entries, err := s.AuditLogService.List(context.Background(), rental.AuditFilter{})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d audit entries, want 2", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Action != rental.AuditActionCreate || entry.EntityType != rental.AuditEntityCar || (entry.EntityID != 1 && entry.EntityID != 2) {
+			t.Errorf("got %v, want creation of car 1 or 2", entry)
+		}
+	}
+
+	// A single event summarizes the import
+	select {
+	case event := <-subscription.Events():
+		if event.Type != events.CarsImported || len(event.CarIDs) != 2 || event.CarIDs[0] != 1 || event.CarIDs[1] != 2 {
+			t.Errorf("got event %v, want import of cars 1 and 2", event)
+		}
+	default:
+		t.Fatalf("got no event, want import of cars 1 and 2")
+	}
+	select {
+	case event := <-subscription.Events():
+		t.Errorf("got event %v, want a single event", event)
+	default:
+	}
+}
+
+// failingAuditLog fails to record entries in batches.
+type failingAuditLog struct {
+	rental.AuditLogService
+}
+
+func (failingAuditLog) RecordMany(ctx context.Context, entries []rental.AuditEntry) error {
+	return errors.New("connection reset")
+}
+
+func TestServerV2_ImportCars_AuditFailure(t *testing.T) {
+	transactor := &rollbackTransactor{}
+	broker := events.NewBroker(10, 10)
+	s := NewServerV2(&Server{CarCRUDService: mock.NewMockCarCRUDService(), AuditLogService: failingAuditLog{}, Transactor: transactor, Events: broker})
+	req := httptest.NewRequest(http.MethodPost, "/v2/car/import", strings.NewReader("make,model,year\nToyota,Corolla,2015\n"))
+	req.Header.Set(echo.HeaderContentType, "text/csv")
+	ctx := echo.New().NewContext(req, httptest.NewRecorder())
+	subscription, _, err := broker.Subscribe(0)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	defer subscription.Close()
+
+	err = s.ImportCars(ctx, genv2.ImportCarsParams{})
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusInternalServerError {
+		t.Errorf("got error %v, want %d status code", err, http.StatusInternalServerError)
+	}
+	if !transactor.rolledBack {
+		t.Errorf("got import committed, want import rolled back")
+	}
+	select {
+	case event := <-subscription.Events():
+		t.Errorf("got event %v, want no event", event)
+	default:
+	}
+}
+
+func TestServerV2_ExportCars(t *testing.T) {
+	csv, ndjson := genv2.ExportCarsParamsFormat("csv"), genv2.ExportCarsParamsFormat("ndjson")
+	tests := []struct {
+		name            string
+		format          *genv2.ExportCarsParamsFormat
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "export CSV",
+			format:          &csv,
+			wantContentType: "text/csv",
+			wantBody:        "id,make,model,year,renter_id\n1,Toyota,Corolla,2015,0\n2,Ford,Fiesta,2016,1\n",
+		},
+		{
+			name:            "export NDJSON",
+			format:          &ndjson,
+			wantContentType: "application/x-ndjson",
+			wantBody: `{"id":1,"make":"Toyota","model":"Corolla","renter_id":0,"year":2015}` + "\n" +
+				`{"id":2,"make":"Ford","model":"Fiesta","renter_id":1,"year":2016}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			s := NewServerV2(&Server{CarCRUDService: mock.NewMockCarCRUDService()})
+			for _, car := range []rental.Car{
+				{ID: 2, Make: "Ford", Model: "Fiesta", Year: 2016},
+				{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015},
+			} {
+				if car.ID == 2 {
+					car.Rent(1)
+				}
+				if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
+					t.Fatalf("got error %v, want nil", err)
+				}
+			}
+			resp := httptest.NewRecorder()
+			ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v2/car/export", nil), resp)
+
+			// Test
+
+			if err := s.ExportCars(ctx, genv2.ExportCarsParams{Format: tt.format}); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if resp.Code != http.StatusOK {
+				t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+			}
+			if got := resp.Header().Get(echo.HeaderContentType); got != tt.wantContentType {
+				t.Errorf("got content type %s, want %s", got, tt.wantContentType)
+			}
+			if got := resp.Body.String(); got != tt.wantBody {
+				t.Errorf("got body %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
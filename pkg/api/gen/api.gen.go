@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -19,9 +20,103 @@ import (
 )
 
 const (
-	BasicAuthScopes = "BasicAuth.Scopes"
+	ApiKeyAuthScopes = "ApiKeyAuth.Scopes"
+	BasicAuthScopes  = "BasicAuth.Scopes"
+	BearerAuthScopes = "BearerAuth.Scopes"
 )
 
+// Defines values for AuditEntryAction.
+const (
+	Create AuditEntryAction = "create"
+	Delete AuditEntryAction = "delete"
+	Rent   AuditEntryAction = "rent"
+	Return AuditEntryAction = "return"
+	Revoke AuditEntryAction = "revoke"
+	Update AuditEntryAction = "update"
+)
+
+// Defines values for AuditEntryEntityType.
+const (
+	AuditEntryEntityTypeApiKey   AuditEntryEntityType = "api_key"
+	AuditEntryEntityTypeCar      AuditEntryEntityType = "car"
+	AuditEntryEntityTypeCustomer AuditEntryEntityType = "customer"
+	AuditEntryEntityTypeTenant   AuditEntryEntityType = "tenant"
+	AuditEntryEntityTypeUser     AuditEntryEntityType = "user"
+)
+
+// Defines values for CarEventType.
+const (
+	CarCreated  CarEventType = "car.created"
+	CarDeleted  CarEventType = "car.deleted"
+	CarRented   CarEventType = "car.rented"
+	CarReturned CarEventType = "car.returned"
+)
+
+// Defines values for Role.
+const (
+	RoleAdmin    Role = "admin"
+	RoleAgent    Role = "agent"
+	RoleCustomer Role = "customer"
+	RoleOperator Role = "operator"
+	RoleReadOnly Role = "read-only"
+)
+
+// Defines values for TokenErrorError.
+const (
+	InvalidGrant         TokenErrorError = "invalid_grant"
+	InvalidRequest       TokenErrorError = "invalid_request"
+	InvalidScope         TokenErrorError = "invalid_scope"
+	UnsupportedGrantType TokenErrorError = "unsupported_grant_type"
+)
+
+// Defines values for TokenRequestGrantType.
+const (
+	Password     TokenRequestGrantType = "password"
+	RefreshToken TokenRequestGrantType = "refresh_token"
+)
+
+// APIKey defines model for APIKey.
+type APIKey struct {
+	CreatedAt time.Time  `json:"created_at"`
+	CreatedBy string     `json:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Id        int64      `json:"id"`
+
+	// Time of the last use of the key, with a one minute precision
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Name       string     `json:"name"`
+
+	// Beginning of the key, identifying it without revealing it
+	Prefix    string     `json:"prefix"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	Scopes    []string   `json:"scopes"`
+}
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	Action AuditEntryAction `json:"action"`
+	Actor  string           `json:"actor"`
+
+	// State of the entity after the action, absent on deletion
+	After *map[string]interface{} `json:"after,omitempty"`
+
+	// State of the entity before the action, absent on creation
+	Before    *map[string]interface{} `json:"before,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+
+	// Fields that changed, mapped to their previous and new values
+	Diff       map[string]interface{} `json:"diff"`
+	EntityId   int64                  `json:"entity_id"`
+	EntityType AuditEntryEntityType   `json:"entity_type"`
+	Id         int64                  `json:"id"`
+}
+
+// AuditEntryAction defines model for AuditEntry.Action.
+type AuditEntryAction string
+
+// AuditEntryEntityType defines model for AuditEntry.EntityType.
+type AuditEntryEntityType string
+
 // Car defines model for Car.
 type Car struct {
 	Id       int64  `json:"id"`
@@ -31,6 +126,39 @@ type Car struct {
 	Year     int    `json:"year"`
 }
 
+// CarEvent defines model for CarEvent.
+type CarEvent struct {
+	Car  Car          `json:"car"`
+	Id   int64        `json:"id"`
+	Time time.Time    `json:"time"`
+	Type CarEventType `json:"type"`
+}
+
+// CarEventType defines model for CarEvent.Type.
+type CarEventType string
+
+// JSON Merge Patch (RFC 7396) of a car, only the fields present are
+// changed.
+type CarPatch struct {
+	Make  *string `json:"make,omitempty"`
+	Model *string `json:"model,omitempty"`
+	Year  *int    `json:"year,omitempty"`
+}
+
+// CreateAPIKeyRequest defines model for CreateAPIKeyRequest.
+type CreateAPIKeyRequest struct {
+	// The key never expires if absent
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+}
+
+// CreateTenantRequest defines model for CreateTenantRequest.
+type CreateTenantRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
 // CreateUpdateCarRequest defines model for CreateUpdateCarRequest.
 type CreateUpdateCarRequest struct {
 	Make  string `json:"make"`
@@ -43,38 +171,291 @@ type CreateUpdateCustomerRequest struct {
 	Name string `json:"name"`
 }
 
+// CreateUserRequest defines model for CreateUserRequest.
+type CreateUserRequest struct {
+	// ID of the customer the user acts as, required for the customer role only
+	CustomerId *int64 `json:"customer_id,omitempty"`
+	Password   string `json:"password"`
+
+	// Role of a user, determining the scopes granted to them:
+	//   * operator: all the admin scopes and tenants:admin, which allows
+	//     provisioning tenants and acting on behalf of any tenant
+	//   * admin: all scopes, except the self-service ones and tenants:admin
+	//   * agent: cars:read, cars:write, customers:read, customers:write, rentals:write
+	//   * read-only: cars:read, customers:read
+	//   * customer: cars:read, customers:self, rentals:self, restricted to the
+	//     customer the user is bound to
+	Role     Role   `json:"role"`
+	Username string `json:"username"`
+}
+
+// CreatedAPIKey defines model for CreatedAPIKey.
+type CreatedAPIKey struct {
+	ApiKey APIKey `json:"api_key"`
+
+	// The API key, it is only returned once and can't be retrieved later
+	Key string `json:"key"`
+}
+
 // Customer defines model for Customer.
 type Customer struct {
 	Id   int64  `json:"id"`
 	Name string `json:"name"`
 }
 
+// JSON Merge Patch (RFC 7396) of a customer, only the fields present are
+// changed.
+type CustomerPatch struct {
+	Name *string `json:"name,omitempty"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+// JSONWebKey defines model for JSONWebKey.
+type JSONWebKey struct {
+	Alg string `json:"alg"`
+	E   string `json:"e"`
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	Use string `json:"use"`
+}
+
+// JSONWebKeySet defines model for JSONWebKeySet.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// Role of a user, determining the scopes granted to them:
+//   - operator: all the admin scopes and tenants:admin, which allows
+//     provisioning tenants and acting on behalf of any tenant
+//   - admin: all scopes, except the self-service ones and tenants:admin
+//   - agent: cars:read, cars:write, customers:read, customers:write, rentals:write
+//   - read-only: cars:read, customers:read
+//   - customer: cars:read, customers:self, rentals:self, restricted to the
+//     customer the user is bound to
+type Role string
+
+// Tenant defines model for Tenant.
+type Tenant struct {
+	CreatedAt time.Time `json:"created_at"`
+	Id        int64     `json:"id"`
+	Name      string    `json:"name"`
+
+	// Identifies the tenant in the X-Tenant header and as subdomain,
+	// a lowercase DNS label
+	Slug string `json:"slug"`
+}
+
+// TokenError defines model for TokenError.
+type TokenError struct {
+	Error            TokenErrorError `json:"error"`
+	ErrorDescription *string         `json:"error_description,omitempty"`
+}
+
+// TokenErrorError defines model for TokenError.Error.
+type TokenErrorError string
+
+// TokenRequest defines model for TokenRequest.
+type TokenRequest struct {
+	GrantType TokenRequestGrantType `json:"grant_type"`
+
+	// Required by the password grant
+	Password *string `json:"password,omitempty"`
+
+	// Required by the refresh_token grant
+	RefreshToken *string `json:"refresh_token,omitempty"`
+
+	// Space-separated list of requested scopes, defaults to all the
+	// scopes of the user's role, or of the refresh token.
+	Scope *string `json:"scope,omitempty"`
+
+	// Required by the password grant
+	Username *string `json:"username,omitempty"`
+}
+
+// TokenRequestGrantType defines model for TokenRequest.GrantType.
+type TokenRequestGrantType string
+
+// TokenResponse defines model for TokenResponse.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+
+	// Lifetime of the access token in seconds
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+
+	// Space-separated list of granted scopes
+	Scope     string `json:"scope"`
+	TokenType string `json:"token_type"`
+}
+
+// UpdateUserRequest defines model for UpdateUserRequest.
+type UpdateUserRequest struct {
+	// ID of the customer the user acts as, required for the customer role only
+	CustomerId *int64 `json:"customer_id,omitempty"`
+
+	// New password of the user, unchanged if absent
+	Password *string `json:"password,omitempty"`
+
+	// Role of a user, determining the scopes granted to them:
+	//   * operator: all the admin scopes and tenants:admin, which allows
+	//     provisioning tenants and acting on behalf of any tenant
+	//   * admin: all scopes, except the self-service ones and tenants:admin
+	//   * agent: cars:read, cars:write, customers:read, customers:write, rentals:write
+	//   * read-only: cars:read, customers:read
+	//   * customer: cars:read, customers:self, rentals:self, restricted to the
+	//     customer the user is bound to
+	Role Role `json:"role"`
+}
+
+// User defines model for User.
+type User struct {
+	// ID of the customer the user acts as, only set for the customer role
+	CustomerId *int64 `json:"customer_id,omitempty"`
+	Id         int64  `json:"id"`
+
+	// Role of a user, determining the scopes granted to them:
+	//   * operator: all the admin scopes and tenants:admin, which allows
+	//     provisioning tenants and acting on behalf of any tenant
+	//   * admin: all scopes, except the self-service ones and tenants:admin
+	//   * agent: cars:read, cars:write, customers:read, customers:write, rentals:write
+	//   * read-only: cars:read, customers:read
+	//   * customer: cars:read, customers:self, rentals:self, restricted to the
+	//     customer the user is bound to
+	Role     Role   `json:"role"`
+	Username string `json:"username"`
+}
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
 // BadRequest defines model for BadRequest.
 type BadRequest = ErrorResponse
 
+// Forbidden defines model for Forbidden.
+type Forbidden = ErrorResponse
+
 // InternalServerError defines model for InternalServerError.
 type InternalServerError = ErrorResponse
 
+// PreconditionFailed defines model for PreconditionFailed.
+type PreconditionFailed = ErrorResponse
+
+// PreconditionRequired defines model for PreconditionRequired.
+type PreconditionRequired = ErrorResponse
+
 // Unauthorized defines model for Unauthorized.
 type Unauthorized = ErrorResponse
 
+// CreateAPIKeyJSONBody defines parameters for CreateAPIKey.
+type CreateAPIKeyJSONBody = CreateAPIKeyRequest
+
+// ListAuditEntriesParams defines parameters for ListAuditEntries.
+type ListAuditEntriesParams struct {
+	// Only return actions performed by this user
+	Actor *string `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Only return actions of this type
+	Action *string `form:"action,omitempty" json:"action,omitempty"`
+
+	// Only return actions performed on entities of this type
+	EntityType *string `form:"entityType,omitempty" json:"entityType,omitempty"`
+
+	// Only return actions performed on the entity with this ID
+	EntityId *int64 `form:"entityId,omitempty" json:"entityId,omitempty"`
+
+	// Only return actions performed at or after this time
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Only return actions performed before this time
+	Until *time.Time `form:"until,omitempty" json:"until,omitempty"`
+
+	// Maximum number of entries to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// DeleteCarParams defines parameters for DeleteCar.
+type DeleteCarParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchCarParams defines parameters for PatchCar.
+type PatchCarParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
 // UpdateCarJSONBody defines parameters for UpdateCar.
 type UpdateCarJSONBody = Car
 
+// UpdateCarParams defines parameters for UpdateCar.
+type UpdateCarParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
 // RentCarParams defines parameters for RentCar.
 type RentCarParams struct {
-	// ID of the customer to rent the car to
-	CustomerId int64 `form:"customerId" json:"customerId"`
+	// ID of the customer to rent the car to, required unless the caller is a customer
+	CustomerId *int64 `form:"customerId,omitempty" json:"customerId,omitempty"`
+}
+
+// DeleteCustomerParams defines parameters for DeleteCustomer.
+type DeleteCustomerParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchCustomerParams defines parameters for PatchCustomer.
+type PatchCustomerParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
 }
 
 // UpdateCustomerJSONBody defines parameters for UpdateCustomer.
 type UpdateCustomerJSONBody = CreateUpdateCustomerRequest
 
+// UpdateCustomerParams defines parameters for UpdateCustomer.
+type UpdateCustomerParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// StreamEventsParams defines parameters for StreamEvents.
+type StreamEventsParams struct {
+	// ID of the last event received by the client
+	LastEventID *int64 `json:"Last-Event-ID,omitempty"`
+}
+
+// CreateTenantJSONBody defines parameters for CreateTenant.
+type CreateTenantJSONBody = CreateTenantRequest
+
+// CreateUserJSONBody defines parameters for CreateUser.
+type CreateUserJSONBody = CreateUserRequest
+
+// UpdateUserJSONBody defines parameters for UpdateUser.
+type UpdateUserJSONBody = UpdateUserRequest
+
+// CreateAPIKeyJSONRequestBody defines body for CreateAPIKey for application/json ContentType.
+type CreateAPIKeyJSONRequestBody = CreateAPIKeyJSONBody
+
 // CreateCarJSONRequestBody defines body for CreateCar for application/json ContentType.
 type CreateCarJSONRequestBody = CreateUpdateCarRequest
 
@@ -87,20 +468,50 @@ type CreateCustomerJSONRequestBody = CreateUpdateCustomerRequest
 // UpdateCustomerJSONRequestBody defines body for UpdateCustomer for application/json ContentType.
 type UpdateCustomerJSONRequestBody = UpdateCustomerJSONBody
 
+// CreateTenantJSONRequestBody defines body for CreateTenant for application/json ContentType.
+type CreateTenantJSONRequestBody = CreateTenantJSONBody
+
+// CreateUserJSONRequestBody defines body for CreateUser for application/json ContentType.
+type CreateUserJSONRequestBody = CreateUserJSONBody
+
+// UpdateUserJSONRequestBody defines body for UpdateUser for application/json ContentType.
+type UpdateUserJSONRequestBody = UpdateUserJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Get the keys verifying access tokens
+	// (GET /.well-known/jwks.json)
+	GetJWKS(ctx echo.Context) error
+	// List API keys
+	// (GET /apikey)
+	ListAPIKeys(ctx echo.Context) error
+	// Create a new API key
+	// (POST /apikey)
+	CreateAPIKey(ctx echo.Context) error
+	// Revokes an API key
+	// (DELETE /apikey/{apiKeyId})
+	RevokeAPIKey(ctx echo.Context, apiKeyId int64) error
+	// Find API key by ID
+	// (GET /apikey/{apiKeyId})
+	GetAPIKeyById(ctx echo.Context, apiKeyId int64) error
+	// List audit entries
+	// (GET /audit)
+	ListAuditEntries(ctx echo.Context, params ListAuditEntriesParams) error
 	// Create a new car
 	// (POST /car)
 	CreateCar(ctx echo.Context) error
 	// Deletes a car
 	// (DELETE /car/{carId})
-	DeleteCar(ctx echo.Context, carId int64) error
+	DeleteCar(ctx echo.Context, carId int64, params DeleteCarParams) error
 	// Find car by ID
 	// (GET /car/{carId})
 	GetCarById(ctx echo.Context, carId int64) error
+	// Partially updates a car
+	// (PATCH /car/{carId})
+	PatchCar(ctx echo.Context, carId int64, params PatchCarParams) error
 	// Updates a car
 	// (PUT /car/{carId})
-	UpdateCar(ctx echo.Context, carId int64) error
+	UpdateCar(ctx echo.Context, carId int64, params UpdateCarParams) error
 	// Rent a car
 	// (GET /car/{carId}/rent)
 	RentCar(ctx echo.Context, carId int64, params RentCarParams) error
@@ -112,13 +523,49 @@ type ServerInterface interface {
 	CreateCustomer(ctx echo.Context) error
 	// Deletes a customer
 	// (DELETE /customer/{customerId})
-	DeleteCustomer(ctx echo.Context, customerId int64) error
+	DeleteCustomer(ctx echo.Context, customerId int64, params DeleteCustomerParams) error
 	// Find customer by ID
 	// (GET /customer/{customerId})
 	GetCustomerById(ctx echo.Context, customerId int64) error
+	// Partially updates a customer
+	// (PATCH /customer/{customerId})
+	PatchCustomer(ctx echo.Context, customerId int64, params PatchCustomerParams) error
 	// Updates a customer
 	// (PUT /customer/{customerId})
-	UpdateCustomer(ctx echo.Context, customerId int64) error
+	UpdateCustomer(ctx echo.Context, customerId int64, params UpdateCustomerParams) error
+	// List the cars rented by a customer
+	// (GET /customer/{customerId}/rentals)
+	ListCustomerRentals(ctx echo.Context, customerId int64) error
+	// Stream car status changes
+	// (GET /events)
+	StreamEvents(ctx echo.Context, params StreamEventsParams) error
+	// List tenants
+	// (GET /tenant)
+	ListTenants(ctx echo.Context) error
+	// Provision a new tenant
+	// (POST /tenant)
+	CreateTenant(ctx echo.Context) error
+	// Find tenant by ID
+	// (GET /tenant/{tenantId})
+	GetTenantById(ctx echo.Context, tenantId int64) error
+	// Issue an access token
+	// (POST /token)
+	IssueToken(ctx echo.Context) error
+	// List users
+	// (GET /user)
+	ListUsers(ctx echo.Context) error
+	// Create a new user
+	// (POST /user)
+	CreateUser(ctx echo.Context) error
+	// Deletes a user
+	// (DELETE /user/{userId})
+	DeleteUser(ctx echo.Context, userId int64) error
+	// Find user by ID
+	// (GET /user/{userId})
+	GetUserById(ctx echo.Context, userId int64) error
+	// Updates a user
+	// (PUT /user/{userId})
+	UpdateUser(ctx echo.Context, userId int64) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -126,11 +573,164 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
+// GetJWKS converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJWKS(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetJWKS(ctx)
+	return err
+}
+
+// ListAPIKeys converts echo context to params.
+func (w *ServerInterfaceWrapper) ListAPIKeys(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListAPIKeys(ctx)
+	return err
+}
+
+// CreateAPIKey converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateAPIKey(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateAPIKey(ctx)
+	return err
+}
+
+// RevokeAPIKey converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeAPIKey(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "apiKeyId" -------------
+	var apiKeyId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "apiKeyId", runtime.ParamLocationPath, ctx.Param("apiKeyId"), &apiKeyId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter apiKeyId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RevokeAPIKey(ctx, apiKeyId)
+	return err
+}
+
+// GetAPIKeyById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAPIKeyById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "apiKeyId" -------------
+	var apiKeyId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "apiKeyId", runtime.ParamLocationPath, ctx.Param("apiKeyId"), &apiKeyId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter apiKeyId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetAPIKeyById(ctx, apiKeyId)
+	return err
+}
+
+// ListAuditEntries converts echo context to params.
+func (w *ServerInterfaceWrapper) ListAuditEntries(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"audit:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"audit:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"audit:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuditEntriesParams
+	// ------------- Optional query parameter "actor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor", ctx.QueryParams(), &params.Actor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter actor: %s", err))
+	}
+
+	// ------------- Optional query parameter "action" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "action", ctx.QueryParams(), &params.Action)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter action: %s", err))
+	}
+
+	// ------------- Optional query parameter "entityType" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "entityType", ctx.QueryParams(), &params.EntityType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter entityType: %s", err))
+	}
+
+	// ------------- Optional query parameter "entityId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "entityId", ctx.QueryParams(), &params.EntityId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter entityId: %s", err))
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", ctx.QueryParams(), &params.Since)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter since: %s", err))
+	}
+
+	// ------------- Optional query parameter "until" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "until", ctx.QueryParams(), &params.Until)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter until: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListAuditEntries(ctx, params)
+	return err
+}
+
 // CreateCar converts echo context to params.
 func (w *ServerInterfaceWrapper) CreateCar(ctx echo.Context) error {
 	var err error
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
 
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.CreateCar(ctx)
@@ -148,10 +748,34 @@ func (w *ServerInterfaceWrapper) DeleteCar(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
 	}
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteCarParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.DeleteCar(ctx, carId)
+	err = w.Handler.DeleteCar(ctx, carId, params)
 	return err
 }
 
@@ -166,13 +790,59 @@ func (w *ServerInterfaceWrapper) GetCarById(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
 	}
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"cars:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:read"})
 
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.GetCarById(ctx, carId)
 	return err
 }
 
+// PatchCar converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchCar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "carId" -------------
+	var carId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "carId", runtime.ParamLocationPath, ctx.Param("carId"), &carId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchCarParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PatchCar(ctx, carId, params)
+	return err
+}
+
 // UpdateCar converts echo context to params.
 func (w *ServerInterfaceWrapper) UpdateCar(ctx echo.Context) error {
 	var err error
@@ -184,10 +854,34 @@ func (w *ServerInterfaceWrapper) UpdateCar(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
 	}
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateCarParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.UpdateCar(ctx, carId)
+	err = w.Handler.UpdateCar(ctx, carId, params)
 	return err
 }
 
@@ -202,13 +896,21 @@ func (w *ServerInterfaceWrapper) RentCar(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
 	}
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(BasicAuthScopes, []string{"rentals:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"rentals:self"})
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params RentCarParams
-	// ------------- Required query parameter "customerId" -------------
+	// ------------- Optional query parameter "customerId" -------------
 
-	err = runtime.BindQueryParameter("form", true, true, "customerId", ctx.QueryParams(), &params.CustomerId)
+	err = runtime.BindQueryParameter("form", true, false, "customerId", ctx.QueryParams(), &params.CustomerId)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
 	}
@@ -229,7 +931,15 @@ func (w *ServerInterfaceWrapper) ReturnCar(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
 	}
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(BasicAuthScopes, []string{"rentals:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"rentals:self"})
 
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.ReturnCar(ctx, carId)
@@ -240,7 +950,11 @@ func (w *ServerInterfaceWrapper) ReturnCar(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) CreateCustomer(ctx echo.Context) error {
 	var err error
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"customers:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:write"})
 
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.CreateCustomer(ctx)
@@ -258,10 +972,34 @@ func (w *ServerInterfaceWrapper) DeleteCustomer(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
 	}
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"customers:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteCustomerParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.DeleteCustomer(ctx, customerId)
+	err = w.Handler.DeleteCustomer(ctx, customerId, params)
 	return err
 }
 
@@ -276,13 +1014,63 @@ func (w *ServerInterfaceWrapper) GetCustomerById(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
 	}
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BasicAuthScopes, []string{"customers:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:self"})
 
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.GetCustomerById(ctx, customerId)
 	return err
 }
 
+// PatchCustomer converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchCustomer(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "customerId" -------------
+	var customerId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "customerId", runtime.ParamLocationPath, ctx.Param("customerId"), &customerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"customers:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchCustomerParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PatchCustomer(ctx, customerId, params)
+	return err
+}
+
 // UpdateCustomer converts echo context to params.
 func (w *ServerInterfaceWrapper) UpdateCustomer(ctx echo.Context) error {
 	var err error
@@ -294,10 +1082,252 @@ func (w *ServerInterfaceWrapper) UpdateCustomer(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
 	}
 
-	ctx.Set(BasicAuthScopes, []string{""})
+	ctx.Set(BasicAuthScopes, []string{"customers:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateCustomerParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateCustomer(ctx, customerId, params)
+	return err
+}
+
+// ListCustomerRentals converts echo context to params.
+func (w *ServerInterfaceWrapper) ListCustomerRentals(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "customerId" -------------
+	var customerId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "customerId", runtime.ParamLocationPath, ctx.Param("customerId"), &customerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BasicAuthScopes, []string{"customers:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:self"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListCustomerRentals(ctx, customerId)
+	return err
+}
+
+// StreamEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) StreamEvents(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"cars:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamEventsParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID int64
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Last-Event-ID, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, valueList[0], &LastEventID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Last-Event-ID: %s", err))
+		}
+
+		params.LastEventID = &LastEventID
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.StreamEvents(ctx, params)
+	return err
+}
+
+// ListTenants converts echo context to params.
+func (w *ServerInterfaceWrapper) ListTenants(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"tenants:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListTenants(ctx)
+	return err
+}
+
+// CreateTenant converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateTenant(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"tenants:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateTenant(ctx)
+	return err
+}
+
+// GetTenantById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTenantById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tenantId" -------------
+	var tenantId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "tenantId", runtime.ParamLocationPath, ctx.Param("tenantId"), &tenantId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tenantId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"tenants:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetTenantById(ctx, tenantId)
+	return err
+}
+
+// IssueToken converts echo context to params.
+func (w *ServerInterfaceWrapper) IssueToken(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.IssueToken(ctx)
+	return err
+}
+
+// ListUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) ListUsers(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListUsers(ctx)
+	return err
+}
+
+// CreateUser converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateUser(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateUser(ctx)
+	return err
+}
+
+// DeleteUser converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteUser(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "userId", runtime.ParamLocationPath, ctx.Param("userId"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteUser(ctx, userId)
+	return err
+}
+
+// GetUserById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUserById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "userId", runtime.ParamLocationPath, ctx.Param("userId"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetUserById(ctx, userId)
+	return err
+}
+
+// UpdateUser converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateUser(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "userId", runtime.ParamLocationPath, ctx.Param("userId"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
 
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.UpdateCustomer(ctx, customerId)
+	err = w.Handler.UpdateUser(ctx, userId)
 	return err
 }
 
@@ -329,45 +1359,132 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/.well-known/jwks.json", wrapper.GetJWKS)
+	router.GET(baseURL+"/apikey", wrapper.ListAPIKeys)
+	router.POST(baseURL+"/apikey", wrapper.CreateAPIKey)
+	router.DELETE(baseURL+"/apikey/:apiKeyId", wrapper.RevokeAPIKey)
+	router.GET(baseURL+"/apikey/:apiKeyId", wrapper.GetAPIKeyById)
+	router.GET(baseURL+"/audit", wrapper.ListAuditEntries)
 	router.POST(baseURL+"/car", wrapper.CreateCar)
 	router.DELETE(baseURL+"/car/:carId", wrapper.DeleteCar)
 	router.GET(baseURL+"/car/:carId", wrapper.GetCarById)
+	router.PATCH(baseURL+"/car/:carId", wrapper.PatchCar)
 	router.PUT(baseURL+"/car/:carId", wrapper.UpdateCar)
 	router.GET(baseURL+"/car/:carId/rent", wrapper.RentCar)
 	router.GET(baseURL+"/car/:carId/return", wrapper.ReturnCar)
 	router.POST(baseURL+"/customer", wrapper.CreateCustomer)
 	router.DELETE(baseURL+"/customer/:customerId", wrapper.DeleteCustomer)
 	router.GET(baseURL+"/customer/:customerId", wrapper.GetCustomerById)
+	router.PATCH(baseURL+"/customer/:customerId", wrapper.PatchCustomer)
 	router.PUT(baseURL+"/customer/:customerId", wrapper.UpdateCustomer)
+	router.GET(baseURL+"/customer/:customerId/rentals", wrapper.ListCustomerRentals)
+	router.GET(baseURL+"/events", wrapper.StreamEvents)
+	router.GET(baseURL+"/tenant", wrapper.ListTenants)
+	router.POST(baseURL+"/tenant", wrapper.CreateTenant)
+	router.GET(baseURL+"/tenant/:tenantId", wrapper.GetTenantById)
+	router.POST(baseURL+"/token", wrapper.IssueToken)
+	router.GET(baseURL+"/user", wrapper.ListUsers)
+	router.POST(baseURL+"/user", wrapper.CreateUser)
+	router.DELETE(baseURL+"/user/:userId", wrapper.DeleteUser)
+	router.GET(baseURL+"/user/:userId", wrapper.GetUserById)
+	router.PUT(baseURL+"/user/:userId", wrapper.UpdateUser)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xZYW/bNhP+K8K970dVUtJsQAUMWJN0g4FhK5IVxZAaw0U62+woUiWppG6g/z6QlGzL",
-	"cpw4sZwNGPollci7h/c8d8eT7yCTRSkFCaMhvQNFXyrS5lTmjNyDM0Vo6EOZo6EzVBf+vX2TSWFIuD+x",
-	"LDnL0DAp4s9aCvtMZzMq0P71f0UTSOF/8dJV7N/q+B7zdV3XYdd3pY0saFgAaz5qD0ORLqXQPh6nmO8b",
-	"wjulpLponIBzmZPOFCutMUhhJG6QszxgoqxMBHUII2FICeSXpG5IOQOHhOOdB9p5D8i5r0P4ILAyM6nY",
-	"N8oPH51MUU7CMOQaQpgR5qQcZR8/fnz1tjIz+zJDQ13fJKoC0is4Rc2yQBHy4odPcEHaKJYZyj8BjEMw",
-	"85IgBftQTFthNBBdmqAjoFSyJGWa3GEuCvQVi5ITpEchTKQq0EAKTJjvT2BhlwlDU3JBLPAv6myD3+Vc",
-	"GoQeiBAKmRPvLv4DFdOb1iqytP3Zx9SHMCd/msWq4+ToTX+hM/qlYsqyfWVP26BfddaCbKwuQymvP1Nm",
-	"YD3NOyWmG86hIvO04zYn3e10/SLWPaLAYu2I79m3bxicS+ojXwPk9m5E0Hjdk0Cfg9EJ4l6g3VTv809a",
-	"43TNtys+QU4GGdcP+m9N9L3bdKasUszML21at9Ves8zWjkXNsHuu7dOlr5kxpS9MTExkW/gwc/xSgYzb",
-	"RfiZFSRk4f79OLWPo0wW0KtnFyQM8uDt+xGEwFlGTTB83OFtidmMguMogRAqxRsAaRzf3t5G6N5GUk3j",
-	"ZquOfxmdvfv18t2r4yiJZqbg1qVhxoXvktk4Bh2fN6S0h3IUJVFil8uSBJYMUngdJdExhFCimbkQxVlT",
-	"+qQXtGXM1flRDmmjflsdw5W7xfy+ot+5ftx7OVhrycfJ0f6uA6g29ZgzVLa/oKHcuj9Jkhfq/t770f3x",
-	"a+ISd3pxHcJ3HvL2TZuuFS4zqqJANV/wGWAg6DbIHK0Gp9rmlv3f2K62iojvMlSjvLY+c+Lku25XG+fu",
-	"uddGiQoLMq5jX91tiD7LAyODxpTNNEidBtt6koJzCKv5blRF4QoNDxa3etyT1ok/wTocDyR/Mh0n9xkW",
-	"0gQTWYl9kuYDrQNcYwzzggkN4zqEKZk+ngsylRJ2n2ZiyqnZ3mXxZzJnqE7nLvZbaRydB3JibVgmJ0zk",
-	"h+QxOUSJWBD3z9fET0zkjovreTA63ywKW296WbsoxzvQPUMTCKJcW+avKaicjaH573SbfVJfv4y6mqgF",
-	"usoy0npScT5/rtgO08KGErHX4pbCttaNYtWctSl3XWXbS9CjdW1m1JYyZ3QwKYdb/DcX+xbECqgWz5eK",
-	"1HwFULPjcI3STYKrl6Ytl5tAquWZcknaiYa+Mu2/ejxR6K83I0OuCPN5B+EBC7BV2y7Cta14i3Tt66eJ",
-	"1xl++RuVB9KRyvYQr3yJ27s2LOEvpgsbhweUsTrRb5u72nXPHb7WP4wOOYG1mDf1kbY4/DeL9WaxJdWL",
-	"gax91BVNfLfsAo+Zz5aGtw9pLTePmNQO24UWHWWQma21PvDg1qd39+ltaaM/wjXvdpjjOpePLU1kOLKT",
-	"wxadIaa7IdXjR7zWwxPnvEdm/7omdpv49iqRAca+B34jfGlh/osHwyH1vzIdbqmeq1/+nbBXvvlfja2k",
-	"/M+cXvWdD+5cZshnUpv0TfImiW+OwE5ryyU6jd3EiTwqCtI6yunGrRovcKzn0W9tDuoAb5BxvObUjnfI",
-	"A486WqZOc4x6XP8dAAD//0KmwIHKHwAA",
+	"H4sIAAAAAAAC/+w9C28bN5N/hdj7gO/r3ephN83XCDjc5fnBbdoadnrpIfIF1O5IYr3LVUmuFdXQfz8M",
+	"H/vkSrJjqXZiFGisXT6Gw3lyhrPXQZSli4wDVzIYXQdzoDEI/efrd3SG/8YgI8EWimU8GAX/A0KyjJNs",
+	"StQcCHDF1CokKiMSeEwY149Ppr2fqIrmxIyHraM55TOQQRjIaA4pxaHVagHBKJBKMD4L1ut1GCyooCko",
+	"C8PJVA/TBgOBczBc+UDSf5o5CZNkQiXEJOMhoZIIULngEI/5ZEWYkiShUhEBNO6TM/gjZwLikAj4Iwep",
+	"JFkyNc9yRZgiVAAR8DtECmL9nNAxf3L8PTkVEGU8ZgheMUZ/zIMwYAivwUMQBpymuGSHoG3osEC8yGIG",
+	"GiMvBVAFvy5iquAlFWfmPb6JMq6A6z/pYpGwiCIwg98lIuy6Ms3fBEyDUfBvg3LrB+atHHQMv9bA1F7m",
+	"UmUp7BeAxhzrtcWJXGRcGny8oPFdg/BaiEyc2UnMNtSJ74Rf0YQhsS9y1Q/WYfAmExMWx8APCYTMp1MW",
+	"MeCKLECkTCIXSATnhCsQnCbnIK5A6KEOCZiZnEg9OwE9/ToMqjzyhrIE4sMB9a4UDEsqSZrFbMogJpLx",
+	"CJCzl1ou0LgJqOPlw4LalJ9MEr2/KBbC4FdOczXPBPsT4sNTfSQgRlTSRAZhVWG8f/++9zxXc3wZUQX1",
+	"uYHnaTD6ELygkkWI6iT9z3FwBijtUJyOgyAMXgAVIIq3AriiyTgILsK2cEQILfA4/vPTkx9hhX8tRLYA",
+	"oazAjLRAiT9SjZ9pJlL8K0D50lMshaA1dFj0mejx4BNNFwm2MPD4esCnBRMgbzQLi2ujH4VlP8bV0ydl",
+	"H8YVzEAzEaqqj7ksFtSgHZaC04PYkuSy+H0Jq9DqLJJxICnjuQKyEBAxlBxBuCPcRoVV8TJhSYIvPY0X",
+	"AqbsUxvSFzBjnDM+q4HHNGVNV/icqULzCrgCmpiHQVjdkMuP3/5x/NvVM/rCN7mAq+zyhnsvo2xhKKeY",
+	"5kMQUSFHWjyElgjkaCmYAqRMpiCVHv1dDE6FoKvAqXMjTT7g9ltcFlgqZq+RYFil4ZITsgnaITjN8zxm",
+	"6jVXwkP+NDIILxnQDBaEQa6VbIB8noACuzT9D9pHgcMfzlmivOjVWi2NlNEzuzAMnSoQbbI4V1RBw5LT",
+	"TfUDs5iQ0IkErkjGiQbdEG8LLROYZgJ2m8O07ZhEY6xjktuIl5hNp22w3jBIYknUnCpruMYhSeliATGa",
+	"12oOTCC3XrEsl4TymHBYkiua5JpgCqRfByugGrVTkaXB6Hh49F0YqEz/9XS99izCIOHjrQSS7WteVKiM",
+	"CiRca8EhtUn9D12wj5eARK2AU67qxGV63YWk9DGbIdDQ8UQd9ioW7BZtZbyXVLQ57lZYTOllQ6a+y1aZ",
+	"oj5spFkMSb3x/1LBpF8CcgXCs7NtEBzVFK2Oh0fPdkOshr46mQPSjtqBu9dX1mppaGwqthkriPg2XTw5",
+	"3gnXmi13ZlYPYfctWSCBUNE34tP90kgofxhPs0XlZbOmXeNBr6VPyxwIZwdCT52zTGNjvdLktILaKU0k",
+	"NM26H85/+Zn8BGIGRHcn/zh785L889tnT79BGUlJREVIMp4Yl3pqpNRCgJaOVMCYW2ll3N36Xu6Lrncn",
+	"1jae9OYZc7HiOtbhrttzbQP9ElaEg3ZvTEvCplZj7MeO2mKW3N4MsRaIHf+iE2HvtLjuRFh7MWda95O3",
+	"q6rirCwoyWf1DolpuaBKgUBE/98H2vtz2Ht28Q/7R+/iehg+PVq759/819+2cpCexyK7e3W+A5X7RsnV",
+	"VVmJu13Kbj6y2baHp+zPPyl5lcFWNG/Dr9wwr7MSrJpquJ2vnKnmmukfuQSBppokVIbEQUKmmai3FVkC",
+	"WnoFO+mGBZVymYm4ph+Kh2GQMv4W+EzNg9H3PnWbJbBNd51hm7UxiNoY/53y7cguuoZV2PTk3VsQd3nI",
+	"ziTbArftvg4D27gtFZ+fnlg/TuGRhVYaTgWSjEegzdaI8r8rMgF8JRhcQUwSqkBsXbaxGx243pXabb8j",
+	"q+xzGKJ07zYBuh+NbQe/vdq+6cJb66ufJrWFKUhJZ40p9EEhiUFRlsit+HVD+LCLGHoPEz+1JzOvkgTv",
+	"00sW+5+rlfc59z7NpW/0JnWrVWAmNB1CDSoOGQbblnkOHrF6CSv9b2EYbOLucqytFoMe1wfPmZV+dXI9",
+	"0xIY6RLFVogbDCJl+uwHSdNYHmQmKFeFq5uOxpyQfye4HqoyMSI0SXRzGqeMu04oTowfKUf6RUiWcxbN",
+	"sXW2lDgGIQuRXekTLj2jaa17oh+IB1CcTGBOk6mGkq9sGwOAHtXMbuYMCXyKYKEM7JBMe3jOzSLUMj6I",
+	"7DAz4GpECoMtNH/qM6SwYNjiXfHbNqidOZkRsWUP+bs+am0k09I962iIayhncL/csazdD4PJtgbG0FqW",
+	"45qzsfGojZfk9g2pGLGA/87c6ZIFvHo+UPOPXMsWHxkb9G7OeBsK4fiWCmFHK7dh05hjTgZSY9LQi4ud",
+	"/tYzy3Rn/5pSJZH5JM5Syng45pQk2RJERCWQVz+fk4ROIDH4b1rTOyipqoW89cjjXXYJvIgo1bcB3GNH",
+	"BcyEDD7aKGYQFk80t1d+a95CwcdlvlhkQoFtYw5nLjyo1bN9rCF2m4g1AHYuq9NArUBSWV3V9IKpADn/",
+	"qHAYL7RVw7IhH53tOjGq2rUkDkeqfahTnW3rgLXm3aOaLWifli5oBD0JGJhHeZAwqVBQ2j2FuJCLMUxp",
+	"niiJIsNK6zE3L50JjzLj71Ib5SHJhHtsISQaQmOP+HRoYS5/HgIbROElsxZldFkyNIpAynIrOiNDzLNT",
+	"b9kUVCVkYwYzaEBxIHUcUnpFUYsIPndDnQIuQhCtAfVUJRsUosZE7bYiuoaq2mg1LDWX5hbi2xzj2D5E",
+	"57IOx8+wLMm2wishyZ2Z7j9g2odr2ti2TrcS0b4PfGunRYLy4zsIb+rF3cr1O5An76x95853IBu5GaJc",
+	"MLU6x8kNqp8v2I+wwpB7G9PWFTeGd0qjOeNAooQBVyGxOp6oucjy2ZwM6IJdwqpPfoSV1ElOuAdjXtjk",
+	"pZmu5rAiSxBQDIJB2g2ZTr/1np+e9H40IR/rVmi4EXM6GcAtQKNVH4Pi07L5XKmFbqzFjGs90b/euJ38",
+	"4f27oOkon50ff/eUSDbjEDdkq5S5URinv5y/IwOjeIiW9QYBVyB0hsiY4/pchFqSRT5JmJzjgIoM+ktI",
+	"kt4lz5Z88PvyUvYx56IIY7NCJ435JYsryXA4XEXZlStvSFKzdNx+xqeZy/WgkZZzkFKWYCP6O0uBZ6n+",
+	"779n+LgfZWnQSuGwxurz05MgDBIWgVVpdqueL2g0B3LcHyJRisQCMBoMlstln+q3/UzMBrarHLw9efn6",
+	"5/PXveP+sD9XaWLCK0rT/zlDRiC1OW2qXjAKjvrD/hCbZwvgdMGCUfBtf9g/NgfAc03ffvTim5nxd42X",
+	"wTJ+Egej4F+gfnj/43nQyBA7Hg7vLEmm7nF7kmSwAXkPE+Qkcg7Kns18d/TPb3Cx3w2/PWAiUUHqlEdA",
+	"ck6vKEvoJIGaQAlGHy7CQOZpSsXKoLEkd80GOh2jyj/aOqAzqbU68uMFDmilSOf+vGVSmUNE+bl7tNOR",
+	"Rnlg2TjOWId+YSnJFH1Z7PFkeNQ1fAH4oJaHpTt9u71TmSqo6WG4vYcvm6+xgddVSWrOh+3xQ3CxDq9r",
+	"otPzuqpImq9rtIFb6E55a0SAjSUOFiwy6YmZmSNoSSh33bV2b6qmPnHBteKQ2OqgwpPQ+RHaKqBJAgIT",
+	"97ClbWbkaZ3uqgG/oJpSu7rjrNV6TNGTrHo8PLrjKd2hfjdROz1tCHQHcqtk1D4yQhcjGOwTqnNwLKZ9",
+	"/FCKxcG1MXtO4rVhD51z1RKSZzrlqiDWajr8hy4bj+lTOpusZc0wVKKlEeamDqrWpxI5VNPPt+fUXLTo",
+	"+Um35WngOaQ0fbIJHJ6pUrw/NHIzVFEVn37h22UXGXp6YShgI00ZD80hTWU2fndgsro7i227fDy0zv9y",
+	"qfQN43FBOpMVOXnVKRIxY7ViKDbdFCQ5czauWxLgSjCQJMV4pwscTVmiQMiQpJm+vhMBV2TKhD5o9pie",
+	"LkuWgdzGBb+UkWubDyrJAgSSsvPpmCQ2pVEzxh85iFWFM2yqYfflnnCXObWryCSxp2QdM5lkxs+cqlxe",
+	"xk1WLIOdANBtV+9Mg7sEopKea31wJg1NdYNxEteA2CFN9GZgUYXH1i4jGRFjwks+iPTdEj84GyJTN4Wo",
+	"yFzeDEzOFUvuAJif6CeW5inheToxZxmON1u6ogFBwlKmahDYgEEwOhoOwyA1I+tfQ32caX/uQWPs5j+W",
+	"afW7+JA1QVVRKl+Zya0Ftk1KbCuX5tu6bqm9bTueNV3QpVps9rBzQ32u4EsqrMVS8QP9eKjcvuy8G7lP",
+	"J48KH629pMLj1v0Flx8fPrmWaRg+cm2+rZNr7W23e2jzti2x4q+CUgfXERVbPMJX+rmh2Y1GC1KFcQWL",
+	"Cz0em11P+JkGe+jfhxK6gbu4vZvLiJC7LPq/2BBHUGpG+JPhs8Pe/YyoIHN9G1Vn5BQZTQIPxhY6TYQp",
+	"SeZMqsyopidHx9uX7rl9i12Pv79Z1+I+7ENjScNG0lxo2Og9+50RSvD6bQK2e8vFfknF7v417rHKyJTx",
+	"eH9cuk+3eoNiMpwT+kpJ+Ea0zQa6zXp939h/nzTeZSQ1Xnoo3Gciae8bCavT89ahNV81jZemNkc1U9iF",
+	"6akokoZtftqG9OMxn2TxKiQJ0CvnpWdqDkKWeQy+E3o9zg4arsI8GAXgALH2OyZAzLXU+P7ovF2CDCli",
+	"sac35T9uzH+nZrL1urmu9V/D93YHiMx1pHCaJzov5jPEwH31nKzkOIxJ0DZIPkPbH313WFsGxQFhOkBH",
+	"W4Lj67I/TqnAmhnJyjLKRksEXauWI1B4nl+bmLyViPpipOCjSHt0YFYF+28QG40zhYGwO1m4NQsBEVUl",
+	"X7eTw+zoOoO7SLvsE3dbzTil2EK6ezoSkiuQoUlycz0Ik0RRzD7CEhS2dEWlblB/zMf8VQGPuX1iDEaT",
+	"kEjJv16/cynmJKJ4Q4cowWYzsIneEY3mIMdcXygUdJmAkCNd7sZk9F0dD6wDTRiXCqjX8MQ17yxQnT2s",
+	"D7q52qsM3Z42a4CoAFVJVM55onO1yiwZJisb2nFA717fOIay8xmPLXpQGncbjhYxzlKsNs5AapEBn5g0",
+	"Jdc+RzC2IaMJulQrC6G+m8A2FFi7x0dHtfJmUcajXOCiklVor1rUeMpcwd2nMGyUS2rLQ0+Dukj0jeCb",
+	"Aa+ubZrAvm8kVnDVKVHD4FNPYHBMh656UUKlDEaBgQNfWiWfiWAUVCRO4BHFOjB2E2HsTp0MRSKEVTmM",
+	"21e5Xu3EgM3PNn08Uhbb7kvODq7NH+V6N0tebHE72dudkLKfY7NOaWaLuxzeWfULnkfh9bUJL5MTsB/x",
+	"VS2lsCmYWhoVnxdRbRZ73WdY1cHsc07su8cA6x05Q/W77F6PyNek4Ra1mmyIt5YUWQRd3aOLGm0Prkub",
+	"d5cYbDnw5kBs4QZtj8ZWbe57FZItTO97Epd18PzFSsqB8XVHaA/B05WAbZuhbx619RxmVIxoGttTimzJ",
+	"yUJkU5aAz2zFQK/tf4Nob81p32C93pUw2Gvkdxfd+bBjwF5Rs3dW6owGe1p0MVJlDP8kXWZmq4UntOzQ",
+	"cqfxZTfooYLMO6rwJuPeLI7yVyn1PcecazXLDh143kXsPIag9xOv8dtej3HoL8DK8oalN9pbm2LTX710",
+	"vduP3Xx58vRROD46fDsEuDcIoM7zm+L4cpdrhTpWUpwuuyDLZEXoRk9xzHW1rNJVtHP67E28slIytQFt",
+	"91iHo6rlPJPgpnkwDuRO96tsEf9tF6teUiErG1TFTvDoKd4zT/GtZQ8S1XdtO0/DlfsGoZd5DZ5658AV",
+	"0d+NkEQqATR1qW5SUZVL95nBPnlNoznRg+qzOqbkmCOpESrtY2QaU12TuI9R4EttHsZU0Wql1zHHBfVZ",
+	"ampTmhEkMQtnfzo/1IgVWxhLL3uSJ5fEdAvH3HAzDm4yMpC2T+yYGoA+eakLodg6J1rKc4hUpQzVWypV",
+	"TzfunbxyNaUERMCuzAdsLGw6EpwyKTEkSCVJMj4bc6pfuG+zScWShMwz8+EZSBtHl6FxrJdMghluBkp/",
+	"8fBoqBGX5vri9RTQQy/Wb2+kmu1BzLjPONUg94nMc93FbO/uslJ/6MpsqUVDKSg0MrtKk9XgCe5aQir4",
+	"pAxV9wwqbpRfqMHyicPzGtVbrrnfvurRAaNnTjbo7/rVWYVnmgdAkDkk+lOllRP6g1bmMrLGyq8Ndbn2",
+	"f2nEUlNbgHaJaVWUYO6s8WXKFx+mxpeZaxczwkL1pZT4qtUY99GDp0GdJpoNPHq82Mjb1PmCdKFcLfU+",
+	"OVHS1OawBdxZXU/yotL7mPvKspdlskNSaMJGpezu2l+m2V5rf9U/j7Pn2l+O6ruo/GHU/LLBW+8CsCZ5",
+	"kZTJOMklPFxmOnUfILAJCsqR4wYJO7g2/9rEhK7CTgZdu4cizaDb/Eg39T0OQ25lgXtR1snCcgj3cM80",
+	"rEOAlno2lnYq6pH7FcQvOBuWmLWVeIHHi4xxWyj16T+fPPuG2E8AOKeqKIxNeTzmnmr2Ej/bXqkgbyr4",
+	"2sB/LmFEAJ1B23PMDelLy4214vM4h61cZxyl4oObGQecJsdh0QcyjeJGd9fVfa1E1OHKptq9yqVfW51I",
+	"mcM7W/V8N131qbdcLnvIi71cJMCjLDYfhd6RjarfPThwSK9eWX9jAdu7T8OrfMdiQw6e29RCNd6eeR9G",
+	"8V9NgWiOVcv9+qv95rb8e6cfgPXhD+MF4Ey7+AAaosciv7sU+c3t5m2w/H2W9q/Slwl8l8EyuSVEdndW",
+	"tqEqPxU9KAv7MBLnV/sVg8Js1zcAH3xxX1vk0Wvr4LvBNf5/p/xhyxwbzXRNXNvzhs2ch7jpogG6J0nA",
+	"GpYHXSK1TGrN5ZaE1pavh6vf3dPD8bf5eXuiouFhRPC98PAePklqz05Ty6bEzu6kn12kWpUkb5Tsc3cU",
+	"evcGSfvjU3vO2dnIDd5cncccxi+HT8v0mE6TZBNgbVja05sZ9VINF9c+ApRkEU3mmVSjZ8Nnw8HVUbAO",
+	"q03kaGBzcPppClL2Y7jSrS4KWFunQU6eSFL4p660AU1MqED2S3FgHgTri/X/DwCxBEE6uZAAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
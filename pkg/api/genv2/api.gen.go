@@ -0,0 +1,1879 @@
+// Package genv2 provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.11.0 DO NOT EDIT.
+package genv2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	ApiKeyAuthScopes = "ApiKeyAuth.Scopes"
+	BasicAuthScopes  = "BasicAuth.Scopes"
+	BearerAuthScopes = "BearerAuth.Scopes"
+)
+
+// Defines values for AuditEntryAction.
+const (
+	AuditEntryActionCreate AuditEntryAction = "create"
+	AuditEntryActionDelete AuditEntryAction = "delete"
+	AuditEntryActionRent   AuditEntryAction = "rent"
+	AuditEntryActionReturn AuditEntryAction = "return"
+	AuditEntryActionRevoke AuditEntryAction = "revoke"
+	AuditEntryActionUpdate AuditEntryAction = "update"
+)
+
+// Defines values for AuditEntryEntityType.
+const (
+	AuditEntryEntityTypeApiKey   AuditEntryEntityType = "api_key"
+	AuditEntryEntityTypeCar      AuditEntryEntityType = "car"
+	AuditEntryEntityTypeCustomer AuditEntryEntityType = "customer"
+	AuditEntryEntityTypeTenant   AuditEntryEntityType = "tenant"
+	AuditEntryEntityTypeUser     AuditEntryEntityType = "user"
+)
+
+// Defines values for BatchOperationAction.
+const (
+	BatchOperationActionCreate BatchOperationAction = "create"
+	BatchOperationActionDelete BatchOperationAction = "delete"
+	BatchOperationActionUpdate BatchOperationAction = "update"
+)
+
+// Defines values for BatchOperationEntity.
+const (
+	BatchOperationEntityCar      BatchOperationEntity = "car"
+	BatchOperationEntityCustomer BatchOperationEntity = "customer"
+)
+
+// Defines values for CarEventType.
+const (
+	CarCreated  CarEventType = "car.created"
+	CarDeleted  CarEventType = "car.deleted"
+	CarRented   CarEventType = "car.rented"
+	CarReturned CarEventType = "car.returned"
+)
+
+// Defines values for Role.
+const (
+	RoleAdmin    Role = "admin"
+	RoleAgent    Role = "agent"
+	RoleCustomer Role = "customer"
+	RoleOperator Role = "operator"
+	RoleReadOnly Role = "read-only"
+)
+
+// Defines values for TokenErrorError.
+const (
+	InvalidGrant         TokenErrorError = "invalid_grant"
+	InvalidRequest       TokenErrorError = "invalid_request"
+	InvalidScope         TokenErrorError = "invalid_scope"
+	UnsupportedGrantType TokenErrorError = "unsupported_grant_type"
+)
+
+// Defines values for TokenRequestGrantType.
+const (
+	Password     TokenRequestGrantType = "password"
+	RefreshToken TokenRequestGrantType = "refresh_token"
+)
+
+// APIKey defines model for APIKey.
+type APIKey struct {
+	CreatedAt time.Time  `json:"created_at"`
+	CreatedBy string     `json:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Id        int64      `json:"id"`
+
+	// Time of the last use of the key, with a one minute precision
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Name       string     `json:"name"`
+
+	// Beginning of the key, identifying it without revealing it
+	Prefix    string     `json:"prefix"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	Scopes    []string   `json:"scopes"`
+}
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	Action AuditEntryAction `json:"action"`
+	Actor  string           `json:"actor"`
+
+	// State of the entity after the action, absent on deletion
+	After *map[string]interface{} `json:"after,omitempty"`
+
+	// State of the entity before the action, absent on creation
+	Before    *map[string]interface{} `json:"before,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+
+	// Fields that changed, mapped to their previous and new values
+	Diff       map[string]interface{} `json:"diff"`
+	EntityId   int64                  `json:"entity_id"`
+	EntityType AuditEntryEntityType   `json:"entity_type"`
+	Id         int64                  `json:"id"`
+}
+
+// AuditEntryAction defines model for AuditEntry.Action.
+type AuditEntryAction string
+
+// AuditEntryEntityType defines model for AuditEntry.EntityType.
+type AuditEntryEntityType string
+
+// Creation, update or deletion of a car or a customer. The new details
+// of the entity are given in car or customer, and updates and deletions
+// take the ETag of the version of the entity they are based on.
+type BatchOperation struct {
+	Action   BatchOperationAction         `json:"action"`
+	Car      *CreateUpdateCarRequest      `json:"car,omitempty"`
+	Customer *CreateUpdateCustomerRequest `json:"customer,omitempty"`
+	Entity   BatchOperationEntity         `json:"entity"`
+
+	// ID of the entity to update or delete
+	Id *int64 `json:"id,omitempty"`
+
+	// ETag of the version of the entity to update or delete
+	IfMatch *string `json:"if_match,omitempty"`
+}
+
+// BatchOperationAction defines model for BatchOperation.Action.
+type BatchOperationAction string
+
+// BatchOperationEntity defines model for BatchOperation.Entity.
+type BatchOperationEntity string
+
+// BatchRequest defines model for BatchRequest.
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResponse defines model for BatchResponse.
+type BatchResponse struct {
+	// Results of the operations, in the order of the request
+	Results []BatchResult `json:"results"`
+}
+
+// BatchResult defines model for BatchResult.
+type BatchResult struct {
+	Car      *Car      `json:"car,omitempty"`
+	Customer *Customer `json:"customer,omitempty"`
+
+	// Reason of the failure of the operation
+	Error *string `json:"error,omitempty"`
+
+	// ETag of the version of the entity updated
+	Etag *string `json:"etag,omitempty"`
+
+	// HTTP status code of the operation, as if it was performed alone.
+	// Operations rolled back or not performed because another operation
+	// failed have a 424 status.
+	Status int `json:"status"`
+}
+
+// Car defines model for Car.
+type Car struct {
+	Id       int64  `json:"id"`
+	Make     string `json:"make"`
+	Model    string `json:"model"`
+	RenterId int    `json:"renter_id"`
+	Year     int    `json:"year"`
+}
+
+// CarEvent defines model for CarEvent.
+type CarEvent struct {
+	Car  Car          `json:"car"`
+	Id   int64        `json:"id"`
+	Time time.Time    `json:"time"`
+	Type CarEventType `json:"type"`
+}
+
+// CarEventType defines model for CarEvent.Type.
+type CarEventType string
+
+// CarImportError defines model for CarImportError.
+type CarImportError struct {
+	// Line of the row in the imported file
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// CarImportReport defines model for CarImportReport.
+type CarImportReport struct {
+	// Rows that couldn't be imported
+	Errors []CarImportError `json:"errors"`
+
+	// Number of cars imported
+	Imported int `json:"imported"`
+}
+
+// JSON Merge Patch (RFC 7396) of a car, only the fields present are
+// changed.
+type CarPatch struct {
+	Make  *string `json:"make,omitempty"`
+	Model *string `json:"model,omitempty"`
+	Year  *int    `json:"year,omitempty"`
+}
+
+// CreateAPIKeyRequest defines model for CreateAPIKeyRequest.
+type CreateAPIKeyRequest struct {
+	// The key never expires if absent
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+}
+
+// CreateRentalRequest defines model for CreateRentalRequest.
+type CreateRentalRequest struct {
+	CarId int64 `json:"car_id"`
+
+	// ID of the customer renting the car, required unless the caller is a customer
+	CustomerId *int64 `json:"customer_id,omitempty"`
+}
+
+// CreateTenantRequest defines model for CreateTenantRequest.
+type CreateTenantRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// CreateUpdateCarRequest defines model for CreateUpdateCarRequest.
+type CreateUpdateCarRequest struct {
+	Make  string `json:"make"`
+	Model string `json:"model"`
+	Year  int    `json:"year"`
+}
+
+// CreateUpdateCustomerRequest defines model for CreateUpdateCustomerRequest.
+type CreateUpdateCustomerRequest struct {
+	Name string `json:"name"`
+}
+
+// CreateUserRequest defines model for CreateUserRequest.
+type CreateUserRequest struct {
+	// ID of the customer the user acts as, required for the customer role only
+	CustomerId *int64 `json:"customer_id,omitempty"`
+	Password   string `json:"password"`
+
+	// Role of a user, determining the scopes granted to them:
+	//   * operator: all the admin scopes and tenants:admin, which allows
+	//     provisioning tenants and acting on behalf of any tenant
+	//   * admin: all scopes, except the self-service ones and tenants:admin
+	//   * agent: cars:read, cars:write, customers:read, customers:write, rentals:write
+	//   * read-only: cars:read, customers:read
+	//   * customer: cars:read, customers:self, rentals:self, restricted to the
+	//     customer the user is bound to
+	Role     Role   `json:"role"`
+	Username string `json:"username"`
+}
+
+// CreatedAPIKey defines model for CreatedAPIKey.
+type CreatedAPIKey struct {
+	ApiKey APIKey `json:"api_key"`
+
+	// The API key, it is only returned once and can't be retrieved later
+	Key string `json:"key"`
+}
+
+// Customer defines model for Customer.
+type Customer struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// JSON Merge Patch (RFC 7396) of a customer, only the fields present are
+// changed.
+type CustomerPatch struct {
+	Name *string `json:"name,omitempty"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+// JSONWebKey defines model for JSONWebKey.
+type JSONWebKey struct {
+	Alg string `json:"alg"`
+	E   string `json:"e"`
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	Use string `json:"use"`
+}
+
+// JSONWebKeySet defines model for JSONWebKeySet.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// Rental defines model for Rental.
+type Rental struct {
+	CarId      int64 `json:"car_id"`
+	CustomerId int64 `json:"customer_id"`
+	Id         int64 `json:"id"`
+
+	// Time the car was returned, absent while the rental is active
+	ReturnedAt *time.Time `json:"returned_at,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+}
+
+// Role of a user, determining the scopes granted to them:
+//   - operator: all the admin scopes and tenants:admin, which allows
+//     provisioning tenants and acting on behalf of any tenant
+//   - admin: all scopes, except the self-service ones and tenants:admin
+//   - agent: cars:read, cars:write, customers:read, customers:write, rentals:write
+//   - read-only: cars:read, customers:read
+//   - customer: cars:read, customers:self, rentals:self, restricted to the
+//     customer the user is bound to
+type Role string
+
+// Tenant defines model for Tenant.
+type Tenant struct {
+	CreatedAt time.Time `json:"created_at"`
+	Id        int64     `json:"id"`
+	Name      string    `json:"name"`
+
+	// Identifies the tenant in the X-Tenant header and as subdomain,
+	// a lowercase DNS label
+	Slug string `json:"slug"`
+}
+
+// TokenError defines model for TokenError.
+type TokenError struct {
+	Error            TokenErrorError `json:"error"`
+	ErrorDescription *string         `json:"error_description,omitempty"`
+}
+
+// TokenErrorError defines model for TokenError.Error.
+type TokenErrorError string
+
+// TokenRequest defines model for TokenRequest.
+type TokenRequest struct {
+	GrantType TokenRequestGrantType `json:"grant_type"`
+
+	// Required by the password grant
+	Password *string `json:"password,omitempty"`
+
+	// Required by the refresh_token grant
+	RefreshToken *string `json:"refresh_token,omitempty"`
+
+	// Space-separated list of requested scopes, defaults to all the
+	// scopes of the user's role, or of the refresh token.
+	Scope *string `json:"scope,omitempty"`
+
+	// Required by the password grant
+	Username *string `json:"username,omitempty"`
+}
+
+// TokenRequestGrantType defines model for TokenRequest.GrantType.
+type TokenRequestGrantType string
+
+// TokenResponse defines model for TokenResponse.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+
+	// Lifetime of the access token in seconds
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+
+	// Space-separated list of granted scopes
+	Scope     string `json:"scope"`
+	TokenType string `json:"token_type"`
+}
+
+// UpdateUserRequest defines model for UpdateUserRequest.
+type UpdateUserRequest struct {
+	// ID of the customer the user acts as, required for the customer role only
+	CustomerId *int64 `json:"customer_id,omitempty"`
+
+	// New password of the user, unchanged if absent
+	Password *string `json:"password,omitempty"`
+
+	// Role of a user, determining the scopes granted to them:
+	//   * operator: all the admin scopes and tenants:admin, which allows
+	//     provisioning tenants and acting on behalf of any tenant
+	//   * admin: all scopes, except the self-service ones and tenants:admin
+	//   * agent: cars:read, cars:write, customers:read, customers:write, rentals:write
+	//   * read-only: cars:read, customers:read
+	//   * customer: cars:read, customers:self, rentals:self, restricted to the
+	//     customer the user is bound to
+	Role Role `json:"role"`
+}
+
+// User defines model for User.
+type User struct {
+	// ID of the customer the user acts as, only set for the customer role
+	CustomerId *int64 `json:"customer_id,omitempty"`
+	Id         int64  `json:"id"`
+
+	// Role of a user, determining the scopes granted to them:
+	//   * operator: all the admin scopes and tenants:admin, which allows
+	//     provisioning tenants and acting on behalf of any tenant
+	//   * admin: all scopes, except the self-service ones and tenants:admin
+	//   * agent: cars:read, cars:write, customers:read, customers:write, rentals:write
+	//   * read-only: cars:read, customers:read
+	//   * customer: cars:read, customers:self, rentals:self, restricted to the
+	//     customer the user is bound to
+	Role     Role   `json:"role"`
+	Username string `json:"username"`
+}
+
+// Ids defines model for Ids.
+type Ids = []int64
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// BadRequest defines model for BadRequest.
+type BadRequest = ErrorResponse
+
+// Forbidden defines model for Forbidden.
+type Forbidden = ErrorResponse
+
+// InternalServerError defines model for InternalServerError.
+type InternalServerError = ErrorResponse
+
+// PreconditionFailed defines model for PreconditionFailed.
+type PreconditionFailed = ErrorResponse
+
+// PreconditionRequired defines model for PreconditionRequired.
+type PreconditionRequired = ErrorResponse
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = ErrorResponse
+
+// CreateAPIKeyJSONBody defines parameters for CreateAPIKey.
+type CreateAPIKeyJSONBody = CreateAPIKeyRequest
+
+// ListAuditEntriesParams defines parameters for ListAuditEntries.
+type ListAuditEntriesParams struct {
+	// Only return actions performed by this user
+	Actor *string `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Only return actions of this type
+	Action *string `form:"action,omitempty" json:"action,omitempty"`
+
+	// Only return actions performed on entities of this type
+	EntityType *string `form:"entityType,omitempty" json:"entityType,omitempty"`
+
+	// Only return actions performed on the entity with this ID
+	EntityId *int64 `form:"entityId,omitempty" json:"entityId,omitempty"`
+
+	// Only return actions performed at or after this time
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Only return actions performed before this time
+	Until *time.Time `form:"until,omitempty" json:"until,omitempty"`
+
+	// Maximum number of entries to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// BatchJSONBody defines parameters for Batch.
+type BatchJSONBody = BatchRequest
+
+// GetCarsByIdsParams defines parameters for GetCarsByIds.
+type GetCarsByIdsParams struct {
+	// Comma separated IDs of the entities to fetch, at most 100. Entities
+	// that don't exist are left out.
+	Ids Ids `form:"ids" json:"ids"`
+}
+
+// ExportCarsParams defines parameters for ExportCars.
+type ExportCarsParams struct {
+	// Format of the exported file
+	Format *ExportCarsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// ExportCarsParamsFormat defines parameters for ExportCars.
+type ExportCarsParamsFormat string
+
+// ImportCarsParams defines parameters for ImportCars.
+type ImportCarsParams struct {
+	// Whether to import nothing or the valid rows when some rows are invalid
+	Mode *ImportCarsParamsMode `form:"mode,omitempty" json:"mode,omitempty"`
+}
+
+// ImportCarsParamsMode defines parameters for ImportCars.
+type ImportCarsParamsMode string
+
+// DeleteCarParams defines parameters for DeleteCar.
+type DeleteCarParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchCarParams defines parameters for PatchCar.
+type PatchCarParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// UpdateCarJSONBody defines parameters for UpdateCar.
+type UpdateCarJSONBody = Car
+
+// UpdateCarParams defines parameters for UpdateCar.
+type UpdateCarParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// GetCustomersByIdsParams defines parameters for GetCustomersByIds.
+type GetCustomersByIdsParams struct {
+	// Comma separated IDs of the entities to fetch, at most 100. Entities
+	// that don't exist are left out.
+	Ids Ids `form:"ids" json:"ids"`
+}
+
+// DeleteCustomerParams defines parameters for DeleteCustomer.
+type DeleteCustomerParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// PatchCustomerParams defines parameters for PatchCustomer.
+type PatchCustomerParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// UpdateCustomerJSONBody defines parameters for UpdateCustomer.
+type UpdateCustomerJSONBody = CreateUpdateCustomerRequest
+
+// UpdateCustomerParams defines parameters for UpdateCustomer.
+type UpdateCustomerParams struct {
+	// ETag of the version of the entity the change is based on, as returned
+	// by its last read. Required, requests without it are rejected with a
+	// 428 Precondition Required.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// StreamEventsParams defines parameters for StreamEvents.
+type StreamEventsParams struct {
+	// ID of the last event received by the client
+	LastEventID *int64 `json:"Last-Event-ID,omitempty"`
+}
+
+// CreateRentalJSONBody defines parameters for CreateRental.
+type CreateRentalJSONBody = CreateRentalRequest
+
+// CreateTenantJSONBody defines parameters for CreateTenant.
+type CreateTenantJSONBody = CreateTenantRequest
+
+// CreateUserJSONBody defines parameters for CreateUser.
+type CreateUserJSONBody = CreateUserRequest
+
+// UpdateUserJSONBody defines parameters for UpdateUser.
+type UpdateUserJSONBody = UpdateUserRequest
+
+// CreateAPIKeyJSONRequestBody defines body for CreateAPIKey for application/json ContentType.
+type CreateAPIKeyJSONRequestBody = CreateAPIKeyJSONBody
+
+// BatchJSONRequestBody defines body for Batch for application/json ContentType.
+type BatchJSONRequestBody = BatchJSONBody
+
+// CreateCarJSONRequestBody defines body for CreateCar for application/json ContentType.
+type CreateCarJSONRequestBody = CreateUpdateCarRequest
+
+// UpdateCarJSONRequestBody defines body for UpdateCar for application/json ContentType.
+type UpdateCarJSONRequestBody = UpdateCarJSONBody
+
+// CreateCustomerJSONRequestBody defines body for CreateCustomer for application/json ContentType.
+type CreateCustomerJSONRequestBody = CreateUpdateCustomerRequest
+
+// UpdateCustomerJSONRequestBody defines body for UpdateCustomer for application/json ContentType.
+type UpdateCustomerJSONRequestBody = UpdateCustomerJSONBody
+
+// CreateRentalJSONRequestBody defines body for CreateRental for application/json ContentType.
+type CreateRentalJSONRequestBody = CreateRentalJSONBody
+
+// CreateTenantJSONRequestBody defines body for CreateTenant for application/json ContentType.
+type CreateTenantJSONRequestBody = CreateTenantJSONBody
+
+// CreateUserJSONRequestBody defines body for CreateUser for application/json ContentType.
+type CreateUserJSONRequestBody = CreateUserJSONBody
+
+// UpdateUserJSONRequestBody defines body for UpdateUser for application/json ContentType.
+type UpdateUserJSONRequestBody = UpdateUserJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Get the keys verifying access tokens
+	// (GET /.well-known/jwks.json)
+	GetJWKS(ctx echo.Context) error
+	// List API keys
+	// (GET /apikey)
+	ListAPIKeys(ctx echo.Context) error
+	// Create a new API key
+	// (POST /apikey)
+	CreateAPIKey(ctx echo.Context) error
+	// Revokes an API key
+	// (DELETE /apikey/{apiKeyId})
+	RevokeAPIKey(ctx echo.Context, apiKeyId int64) error
+	// Find API key by ID
+	// (GET /apikey/{apiKeyId})
+	GetAPIKeyById(ctx echo.Context, apiKeyId int64) error
+	// List audit entries
+	// (GET /audit)
+	ListAuditEntries(ctx echo.Context, params ListAuditEntriesParams) error
+	// Perform operations in a batch
+	// (POST /batch)
+	Batch(ctx echo.Context) error
+	// Find cars by IDs
+	// (GET /car)
+	GetCarsByIds(ctx echo.Context, params GetCarsByIdsParams) error
+	// Create a new car
+	// (POST /car)
+	CreateCar(ctx echo.Context) error
+	// Export cars
+	// (GET /car/export)
+	ExportCars(ctx echo.Context, params ExportCarsParams) error
+	// Import cars
+	// (POST /car/import)
+	ImportCars(ctx echo.Context, params ImportCarsParams) error
+	// Deletes a car
+	// (DELETE /car/{carId})
+	DeleteCar(ctx echo.Context, carId int64, params DeleteCarParams) error
+	// Find car by ID
+	// (GET /car/{carId})
+	GetCarById(ctx echo.Context, carId int64) error
+	// Partially updates a car
+	// (PATCH /car/{carId})
+	PatchCar(ctx echo.Context, carId int64, params PatchCarParams) error
+	// Updates a car
+	// (PUT /car/{carId})
+	UpdateCar(ctx echo.Context, carId int64, params UpdateCarParams) error
+	// Find customers by IDs
+	// (GET /customer)
+	GetCustomersByIds(ctx echo.Context, params GetCustomersByIdsParams) error
+	// Create a new customer
+	// (POST /customer)
+	CreateCustomer(ctx echo.Context) error
+	// Deletes a customer
+	// (DELETE /customer/{customerId})
+	DeleteCustomer(ctx echo.Context, customerId int64, params DeleteCustomerParams) error
+	// Find customer by ID
+	// (GET /customer/{customerId})
+	GetCustomerById(ctx echo.Context, customerId int64) error
+	// Partially updates a customer
+	// (PATCH /customer/{customerId})
+	PatchCustomer(ctx echo.Context, customerId int64, params PatchCustomerParams) error
+	// Updates a customer
+	// (PUT /customer/{customerId})
+	UpdateCustomer(ctx echo.Context, customerId int64, params UpdateCustomerParams) error
+	// List the cars rented by a customer
+	// (GET /customer/{customerId}/rentals)
+	ListCustomerRentals(ctx echo.Context, customerId int64) error
+	// Stream car status changes
+	// (GET /events)
+	StreamEvents(ctx echo.Context, params StreamEventsParams) error
+	// Rent a car
+	// (POST /rentals)
+	CreateRental(ctx echo.Context) error
+	// Find rental by ID
+	// (GET /rentals/{rentalId})
+	GetRentalById(ctx echo.Context, rentalId int64) error
+	// Return a rented car
+	// (POST /rentals/{rentalId}/return)
+	ReturnRental(ctx echo.Context, rentalId int64) error
+	// List tenants
+	// (GET /tenant)
+	ListTenants(ctx echo.Context) error
+	// Provision a new tenant
+	// (POST /tenant)
+	CreateTenant(ctx echo.Context) error
+	// Find tenant by ID
+	// (GET /tenant/{tenantId})
+	GetTenantById(ctx echo.Context, tenantId int64) error
+	// Issue an access token
+	// (POST /token)
+	IssueToken(ctx echo.Context) error
+	// List users
+	// (GET /user)
+	ListUsers(ctx echo.Context) error
+	// Create a new user
+	// (POST /user)
+	CreateUser(ctx echo.Context) error
+	// Deletes a user
+	// (DELETE /user/{userId})
+	DeleteUser(ctx echo.Context, userId int64) error
+	// Find user by ID
+	// (GET /user/{userId})
+	GetUserById(ctx echo.Context, userId int64) error
+	// Updates a user
+	// (PUT /user/{userId})
+	UpdateUser(ctx echo.Context, userId int64) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// GetJWKS converts echo context to params.
+func (w *ServerInterfaceWrapper) GetJWKS(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetJWKS(ctx)
+	return err
+}
+
+// ListAPIKeys converts echo context to params.
+func (w *ServerInterfaceWrapper) ListAPIKeys(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListAPIKeys(ctx)
+	return err
+}
+
+// CreateAPIKey converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateAPIKey(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateAPIKey(ctx)
+	return err
+}
+
+// RevokeAPIKey converts echo context to params.
+func (w *ServerInterfaceWrapper) RevokeAPIKey(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "apiKeyId" -------------
+	var apiKeyId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "apiKeyId", runtime.ParamLocationPath, ctx.Param("apiKeyId"), &apiKeyId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter apiKeyId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.RevokeAPIKey(ctx, apiKeyId)
+	return err
+}
+
+// GetAPIKeyById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetAPIKeyById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "apiKeyId" -------------
+	var apiKeyId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "apiKeyId", runtime.ParamLocationPath, ctx.Param("apiKeyId"), &apiKeyId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter apiKeyId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetAPIKeyById(ctx, apiKeyId)
+	return err
+}
+
+// ListAuditEntries converts echo context to params.
+func (w *ServerInterfaceWrapper) ListAuditEntries(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"audit:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"audit:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"audit:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuditEntriesParams
+	// ------------- Optional query parameter "actor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor", ctx.QueryParams(), &params.Actor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter actor: %s", err))
+	}
+
+	// ------------- Optional query parameter "action" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "action", ctx.QueryParams(), &params.Action)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter action: %s", err))
+	}
+
+	// ------------- Optional query parameter "entityType" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "entityType", ctx.QueryParams(), &params.EntityType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter entityType: %s", err))
+	}
+
+	// ------------- Optional query parameter "entityId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "entityId", ctx.QueryParams(), &params.EntityId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter entityId: %s", err))
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", ctx.QueryParams(), &params.Since)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter since: %s", err))
+	}
+
+	// ------------- Optional query parameter "until" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "until", ctx.QueryParams(), &params.Until)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter until: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListAuditEntries(ctx, params)
+	return err
+}
+
+// Batch converts echo context to params.
+func (w *ServerInterfaceWrapper) Batch(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BasicAuthScopes, []string{"customers:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:write"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.Batch(ctx)
+	return err
+}
+
+// GetCarsByIds converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCarsByIds(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"cars:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCarsByIdsParams
+	// ------------- Required query parameter "ids" -------------
+
+	err = runtime.BindQueryParameter("form", false, true, "ids", ctx.QueryParams(), &params.Ids)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter ids: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetCarsByIds(ctx, params)
+	return err
+}
+
+// CreateCar converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateCar(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateCar(ctx)
+	return err
+}
+
+// ExportCars converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportCars(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"cars:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportCarsParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ExportCars(ctx, params)
+	return err
+}
+
+// ImportCars converts echo context to params.
+func (w *ServerInterfaceWrapper) ImportCars(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportCarsParams
+	// ------------- Optional query parameter "mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", ctx.QueryParams(), &params.Mode)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter mode: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ImportCars(ctx, params)
+	return err
+}
+
+// DeleteCar converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteCar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "carId" -------------
+	var carId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "carId", runtime.ParamLocationPath, ctx.Param("carId"), &carId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteCarParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteCar(ctx, carId, params)
+	return err
+}
+
+// GetCarById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCarById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "carId" -------------
+	var carId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "carId", runtime.ParamLocationPath, ctx.Param("carId"), &carId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"cars:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:read"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetCarById(ctx, carId)
+	return err
+}
+
+// PatchCar converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchCar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "carId" -------------
+	var carId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "carId", runtime.ParamLocationPath, ctx.Param("carId"), &carId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchCarParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PatchCar(ctx, carId, params)
+	return err
+}
+
+// UpdateCar converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateCar(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "carId" -------------
+	var carId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "carId", runtime.ParamLocationPath, ctx.Param("carId"), &carId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter carId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"cars:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateCarParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateCar(ctx, carId, params)
+	return err
+}
+
+// GetCustomersByIds converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCustomersByIds(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BasicAuthScopes, []string{"customers:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:self"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCustomersByIdsParams
+	// ------------- Required query parameter "ids" -------------
+
+	err = runtime.BindQueryParameter("form", false, true, "ids", ctx.QueryParams(), &params.Ids)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter ids: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetCustomersByIds(ctx, params)
+	return err
+}
+
+// CreateCustomer converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateCustomer(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"customers:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:write"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateCustomer(ctx)
+	return err
+}
+
+// DeleteCustomer converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteCustomer(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "customerId" -------------
+	var customerId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "customerId", runtime.ParamLocationPath, ctx.Param("customerId"), &customerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"customers:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteCustomerParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteCustomer(ctx, customerId, params)
+	return err
+}
+
+// GetCustomerById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetCustomerById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "customerId" -------------
+	var customerId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "customerId", runtime.ParamLocationPath, ctx.Param("customerId"), &customerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BasicAuthScopes, []string{"customers:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:self"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetCustomerById(ctx, customerId)
+	return err
+}
+
+// PatchCustomer converts echo context to params.
+func (w *ServerInterfaceWrapper) PatchCustomer(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "customerId" -------------
+	var customerId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "customerId", runtime.ParamLocationPath, ctx.Param("customerId"), &customerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"customers:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchCustomerParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PatchCustomer(ctx, customerId, params)
+	return err
+}
+
+// UpdateCustomer converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateCustomer(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "customerId" -------------
+	var customerId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "customerId", runtime.ParamLocationPath, ctx.Param("customerId"), &customerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"customers:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateCustomerParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = &IfMatch
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateCustomer(ctx, customerId, params)
+	return err
+}
+
+// ListCustomerRentals converts echo context to params.
+func (w *ServerInterfaceWrapper) ListCustomerRentals(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "customerId" -------------
+	var customerId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "customerId", runtime.ParamLocationPath, ctx.Param("customerId"), &customerId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter customerId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BasicAuthScopes, []string{"customers:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:self"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListCustomerRentals(ctx, customerId)
+	return err
+}
+
+// StreamEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) StreamEvents(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"cars:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"cars:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"cars:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamEventsParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID int64
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Last-Event-ID, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Last-Event-ID", runtime.ParamLocationHeader, valueList[0], &LastEventID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Last-Event-ID: %s", err))
+		}
+
+		params.LastEventID = &LastEventID
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.StreamEvents(ctx, params)
+	return err
+}
+
+// CreateRental converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateRental(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(BasicAuthScopes, []string{"rentals:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"rentals:self"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateRental(ctx)
+	return err
+}
+
+// GetRentalById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRentalById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "rentalId" -------------
+	var rentalId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "rentalId", runtime.ParamLocationPath, ctx.Param("rentalId"), &rentalId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter rentalId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:read"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"customers:read"})
+
+	ctx.Set(BasicAuthScopes, []string{"customers:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"customers:self"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRentalById(ctx, rentalId)
+	return err
+}
+
+// ReturnRental converts echo context to params.
+func (w *ServerInterfaceWrapper) ReturnRental(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "rentalId" -------------
+	var rentalId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "rentalId", runtime.ParamLocationPath, ctx.Param("rentalId"), &rentalId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter rentalId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(BearerAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"rentals:write"})
+
+	ctx.Set(BasicAuthScopes, []string{"rentals:self"})
+
+	ctx.Set(BearerAuthScopes, []string{"rentals:self"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ReturnRental(ctx, rentalId)
+	return err
+}
+
+// ListTenants converts echo context to params.
+func (w *ServerInterfaceWrapper) ListTenants(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"tenants:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListTenants(ctx)
+	return err
+}
+
+// CreateTenant converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateTenant(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"tenants:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateTenant(ctx)
+	return err
+}
+
+// GetTenantById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTenantById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "tenantId" -------------
+	var tenantId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "tenantId", runtime.ParamLocationPath, ctx.Param("tenantId"), &tenantId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tenantId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"tenants:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"tenants:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetTenantById(ctx, tenantId)
+	return err
+}
+
+// IssueToken converts echo context to params.
+func (w *ServerInterfaceWrapper) IssueToken(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.IssueToken(ctx)
+	return err
+}
+
+// ListUsers converts echo context to params.
+func (w *ServerInterfaceWrapper) ListUsers(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListUsers(ctx)
+	return err
+}
+
+// CreateUser converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateUser(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateUser(ctx)
+	return err
+}
+
+// DeleteUser converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteUser(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "userId", runtime.ParamLocationPath, ctx.Param("userId"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteUser(ctx, userId)
+	return err
+}
+
+// GetUserById converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUserById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "userId", runtime.ParamLocationPath, ctx.Param("userId"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetUserById(ctx, userId)
+	return err
+}
+
+// UpdateUser converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateUser(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "userId" -------------
+	var userId int64
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "userId", runtime.ParamLocationPath, ctx.Param("userId"), &userId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter userId: %s", err))
+	}
+
+	ctx.Set(BasicAuthScopes, []string{"users:admin"})
+
+	ctx.Set(BearerAuthScopes, []string{"users:admin"})
+
+	ctx.Set(ApiKeyAuthScopes, []string{"users:admin"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.UpdateUser(ctx, userId)
+	return err
+}
+
+// This is a simple interface which specifies echo.Route addition functions which
+// are present on both echo.Echo and echo.Group, since we want to allow using
+// either of them for path registration
+type EchoRouter interface {
+	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterHandlers adds each server route to the EchoRouter.
+func RegisterHandlers(router EchoRouter, si ServerInterface) {
+	RegisterHandlersWithBaseURL(router, si, "")
+}
+
+// Registers handlers, and prepends BaseURL to the paths, so that the paths
+// can be served under a prefix.
+func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+
+	wrapper := ServerInterfaceWrapper{
+		Handler: si,
+	}
+
+	router.GET(baseURL+"/.well-known/jwks.json", wrapper.GetJWKS)
+	router.GET(baseURL+"/apikey", wrapper.ListAPIKeys)
+	router.POST(baseURL+"/apikey", wrapper.CreateAPIKey)
+	router.DELETE(baseURL+"/apikey/:apiKeyId", wrapper.RevokeAPIKey)
+	router.GET(baseURL+"/apikey/:apiKeyId", wrapper.GetAPIKeyById)
+	router.GET(baseURL+"/audit", wrapper.ListAuditEntries)
+	router.POST(baseURL+"/batch", wrapper.Batch)
+	router.GET(baseURL+"/car", wrapper.GetCarsByIds)
+	router.POST(baseURL+"/car", wrapper.CreateCar)
+	router.GET(baseURL+"/car/export", wrapper.ExportCars)
+	router.POST(baseURL+"/car/import", wrapper.ImportCars)
+	router.DELETE(baseURL+"/car/:carId", wrapper.DeleteCar)
+	router.GET(baseURL+"/car/:carId", wrapper.GetCarById)
+	router.PATCH(baseURL+"/car/:carId", wrapper.PatchCar)
+	router.PUT(baseURL+"/car/:carId", wrapper.UpdateCar)
+	router.GET(baseURL+"/customer", wrapper.GetCustomersByIds)
+	router.POST(baseURL+"/customer", wrapper.CreateCustomer)
+	router.DELETE(baseURL+"/customer/:customerId", wrapper.DeleteCustomer)
+	router.GET(baseURL+"/customer/:customerId", wrapper.GetCustomerById)
+	router.PATCH(baseURL+"/customer/:customerId", wrapper.PatchCustomer)
+	router.PUT(baseURL+"/customer/:customerId", wrapper.UpdateCustomer)
+	router.GET(baseURL+"/customer/:customerId/rentals", wrapper.ListCustomerRentals)
+	router.GET(baseURL+"/events", wrapper.StreamEvents)
+	router.POST(baseURL+"/rentals", wrapper.CreateRental)
+	router.GET(baseURL+"/rentals/:rentalId", wrapper.GetRentalById)
+	router.POST(baseURL+"/rentals/:rentalId/return", wrapper.ReturnRental)
+	router.GET(baseURL+"/tenant", wrapper.ListTenants)
+	router.POST(baseURL+"/tenant", wrapper.CreateTenant)
+	router.GET(baseURL+"/tenant/:tenantId", wrapper.GetTenantById)
+	router.POST(baseURL+"/token", wrapper.IssueToken)
+	router.GET(baseURL+"/user", wrapper.ListUsers)
+	router.POST(baseURL+"/user", wrapper.CreateUser)
+	router.DELETE(baseURL+"/user/:userId", wrapper.DeleteUser)
+	router.GET(baseURL+"/user/:userId", wrapper.GetUserById)
+	router.PUT(baseURL+"/user/:userId", wrapper.UpdateUser)
+
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+w9i24jN5K/QvQtsJu7tmQ7k+yOgcPdvLLnzWxi2JOdPURzA6q7JDHuJrUk27Ji6N8P",
+	"xUc/2ZLskTX2jBEgY3WzyWKxqlhP8iZKRD4XHLhW0clNNAOagjR/vnlHp/hvCiqRbK6Z4NFJ9A+QiglO",
+	"xIToGRDgmullTLQgCnhKGDePTycHf6c6mRHbH7ZOZpRPQUVxpJIZ5BS71ss5RCeR0pLxabRareJoTiXN",
+	"QTsYTlPVBeGVyHNKFGBTDSk5fa0a4DBQCM8EdDKLCdUkF0qTo8PDAXnj3o+4nlFNUsH/qAlcM6UJlUAy",
+	"mGgiCj0Y8SiO4HqeiRSikwnNFMQRw8H/VYBcRnHEaY6gsxRnJOFfBZOQRidaFlCfIdOQmylMhMypxi+4",
+	"/v5ZFPu5M65hCjJaxVFOr09t86PDw7IBlZIu8bXSywwfYE/4+3RiUNzFDy6cR8hVaLnMn3Y9CFNkTBWk",
+	"RPCYUEUk6EJySEd8vCRMK5JRpYkEmg7IuZtmTHDCoLQiC6ZnotCEWQRK+A0SXBN8TuiIPzv+CzmTkAie",
+	"MgSv7MOi2KDU0kiFU088m0jFAfFSpAwMjl9JoBp+madUwysqz+17fJMIroGbP+l8nrGEIjDD3xQi7KY2",
+	"zB8kTKKT6N+GFVsM7Vs17Ol+ZYBpvCyUFjncLwCtMVYrhxM1F1xZfLyk6a5BeCOlkOduELsMTeI75Vc0",
+	"YygI5oUeIJ3+IOSYpSnwfQKhismEJQy4JnOQOVPIBcqwDdcgOc0uQF6BNF3tEzA7OFFmdAJm+FUc1Xnk",
+	"B8oySPcH1LtKMCyoIrlI2YRBShTjCSBnL4xcoGkb0PNS7O0T1PbewhQx64tiIY5+4bTQMyHZ75Dun+oT",
+	"CSmikmYqiuub6fv37w9eFHqGLxOqoTk28CKPTn6NXlLFEkR1lv/nKDoHlHYoTkdRFEcvgUqQ5VsJXNNs",
+	"FEUf4q5wRAgd8Nj/i7PTH2GJf82lmIPUTmAmRqCkH6lubFEoXw40yyHqdB2X34xNf3BN87nZliw8oS/g",
+	"es4kqFuNwtJG70fxNhsoblUfC1VOqEU7LAe/D2JLUqjy9yUsY7dnEcGB5IwXGshcQsJQckTxlnDbLayO",
+	"lzHLMnwZaDyXMGHXXUhfwpRxzvi0AR4zlDVZ4nOmy51XwhXQzD6M4trA8vLjt/86/ufVc/oyNLiEK3F5",
+	"y7VXiZhbyimH+TVKqFQnRjzEjgjUyUIyDUiZpf7T6aup3qzqStSvuPwOlyWWytEbJBjXabjiBDFGPQSH",
+	"eVGkTL/hWgbInyYW4RUD2s6iOCrMJhshn2egwU3N/IP6UeTxh2NWKC+/6syWJtruM9swDJ1okF2yuNBU",
+	"Q0uTM03NAzuZmNCxAq6J4MSAbom3g5YxTISE7cawbXsGMRjrGeQu4iVlk0kXrB8YZKkiRmm3imsak5zO",
+	"55Ciqq9nwCRy6xUThSKUp4TDglzRrDAEUyL9JloCNaidSJFHJ8eHR9/FkRbmr+9Xq8AkLBI+3kkguW/t",
+	"ixqVUYmE6zQ4pDZl/qFz9vESkKg1cMp1k7jsV7uQlCFmswQae55owl7HgluijYz3Evfon+cgqWeylhXn",
+	"CCcmlmuIkCXFIgFSklCJDynxmBoQ3P9xZVPQlGVqxFvcIIFM2RVwtEPd5/7j2JCFHcuSiB8NrUF6aQl8",
+	"K9vJDuTNJmvJ3FmyfAjtsVRuUkn6jJEaXX2KNeFXfA3ZfuglxpZu9LqNQdFecoji21FwHLHJx/zO5u96",
+	"AKJRdDSKqlFLtarJNi1O6WeCmg3WpBLhuUM1XAXrFq3FVV2vQc64/7lhj62NvgZ0p/N2YJegikwHvDPn",
+	"9oVHeTVK7J1DQjqXEP5wRnwU32L+doiNSoQHcd3ssJ+uWrwF+1F5K17z7ZCxvMnZxhtVFaVOKMsKCR0s",
+	"Nuj0FZWEC00mouBpUPPWdHoXBrHckbaY4jjEFHGkNNVFgBD+5927M2JfkkSk3bkYfxObeAtzDhL5HlJC",
+	"M8FhMOIlnSsiRZZBSsY0uSTCzrpqP4aEojJPudAzJC3/3YhPjC1NZvQKCCXPjp85iLyHz83u+PCwK2Ra",
+	"5OTmGaKmV1R2qehOGkNOL1v2wzuxFJqGEJ+LFLJm4/+lkqmwts81yIAW0wXBa0g15Bw9306JMNDXB/NA",
+	"ul57cPfmylnod2XD1rSeHW+Fa6OCbq2YBpS4gVOBIrNjD+xO4n8ZJFQ/rFe1o9FVzdZvNqxsEntFEOHs",
+	"QehpPhdSl76tJlozxgOK/1vGSw6VYuFlNTM9QUomLGvu0sdB8gWl6LRFwSimcPlJXihNxkDmQjHNrmDj",
+	"rA2oVa9rZ3sO+P/udI24De1TYuENClFkKQYCxtV8t92PWshedd32ZY8dCH4q8rGLjVCp6kPXsLxZLtW/",
+	"s3PtwdOZ15doav13NDurocpFOZow/u3i55/I30FOgZjPyZ/Of3hF/vzt8++/KZX0mAie2aDCxNppcwnG",
+	"PqQSRtzZayE1+b6k3fYirIsnw9LWYdaruDU9Wl0X5SUsCQfj4LUtcZuzNvP9eJI2OGbu7ohxPhjX/4de",
+	"hJ0bZ0YvwhIq72ZBewXr43rLwjcjErhGX5h5iKTpJ0MKnoFS7kWWWcdxZVve1gJp4clNsB9B74xF34ug",
+	"7mpbjJK3y7pvpbbiWTFtfpDZlnOqNUhEz//9Sg9+Pzx4/uFP7o+DDzeH8fdHK//8m//6w0YRbMZx1Ng/",
+	"u1DM7aGxen1WTlHZrJysj+ptWsMz9vvvlLwWm3e6TfhVa8a9LY/gj0KBRG+eIlTVmGQiZLOtFBkY8R5t",
+	"xa5zqtRCyLShVpUPjXX6FvhUz6KTv4S0VJHBph33HNusrM+si/HfKN+M7PLTuA6bGbx/CdK+IIr32m2A",
+	"232+iiPXuLttvDg7da5+jcLJ7KpecySCJ2DcVgl1uooELRlcQUoyqkFunLZ1LXpwgzOtmbM7MGY+hSGq",
+	"CMA6QO9HpSk9hnfVa2478c78mgHHrjANqdlG+fOe0Y34XadTI4bewzhM7dk0qEVA8OklS8PP9TL4nAef",
+	"FirUe5u69TKyA9oPYgMqdhlHm6Z5AQGxegnL7f1yVV8bVSrTbwgeu+Pfn/J0y6/v9JEXV/0RWKeZuaQC",
+	"27oMKC1mLLNNbHTM6GiJsxW3jFFqKm8XdApJHofzJg4bnQdX0O1fbUszAytZcOOJkUVB5ox7PdUq12Qq",
+	"KddlPCs/GXFC/t35soQ8ITTLTHOa5oz7j3BDsMEidWJexIjDZIatxUJhH4TMpbgyYWwzom1tvkTUYpSZ",
+	"kzHMaDYxUPKla2MBML3a0e2YMYHrBObawg7Z5ACTWVgCRPAQRK6bKXB9QkqbJLZ/mkBxXIrc8l352zVo",
+	"BJZtj9jyACV0s9dGT7alf9bTEOdQjeB/+dwLtx4Wk10dCvPn0P1KtLAuRece8usWxZHBAv479SFkB3gr",
+	"mlKJct+yQ9zWithNIkeLv4/vuKVvaae0tFKby2AyNWfg6MX7nP55YKfpE3wMpSqiinEqcsp4POKUZGIB",
+	"MqEKyOufLkhGx5A1XbpRFoQoxOx1G2djXPOduATe41orXfueCpjNC/pYi3K4J4bba78Nb+HWxVUxtw4d",
+	"28ZGYEPBNjPaxwZiN22SFsDeafWaGDVIarOrK88wkaBmHzV2E4S2bhq0Ix/O+hhbZcu3JB5HuuvNro+2",
+	"scNG8/5e7RJ0UyLmNIGDKt04Y0qjoHRrCmkpF1OYUBP70sJL6xG3L70RhjLjjyacATEGMsSkDiExEFqN",
+	"MqQFlQbPpyGwRRRBMutQRp8uSpMElKqWojf9i/GQ13kCupaXZTuzaEBxoEyyoepRNVpE8KkL6jfgMs+o",
+	"06EZqmKDUtTY1LwtIsY1VDV6a2CpPTU/kdDiWNfEY3QPtHzhsKjItsYrMSm8oRX2od6Hc6G1bL2OAUT7",
+	"feDbmJ0KdBjfd8iVuJMmvx9fjLfXvEOmB9nIzZAUkunlBQ5uUf1izn6EJebVdjHtnClW8c5pMmMcSJIx",
+	"4Dombo8neiZFMZ2RIZ2zS1gOyI+wVCa7B9dgxEudvFLTTf7PAiSUnWAm5ppyhn8evDg7PfjR5nU5w9DA",
+	"bfMQFEv8BAxajacfn1bNZ1rPTWMjZnzrsfn1g1/Jv71/F7VdHecXx999TxSbcoyqN2SrUoXdMM5+vnhH",
+	"hnbjIUbWWwRcgTRp4COO8/NpqIrMi3HG1Aw71GQ4WECWHVxyseDD3xaXaoCJ1WWuKiv3pBG/ZGmtGgi7",
+	"q2121cxbktROHZef8YnwCd00MXIOcsoybER/YzlwkZv//nuKjweJMFUy7Z3SKKsvzk7jMvXheEDsYztv",
+	"SiQoUcgErLlgHtooLRkvR9ysOlpN1NuoqJ+WbjpPUZUNGxNJTVqCnlFeouOvb96RYULl8Cah8jRdDaVx",
+	"LPG0/RT7rWXRIPI85EcWeRlLwG3NjuRezGkyA3I8OETmkplD5MlwuFgsBtS8HQg5HbpP1fDt6as3P128",
+	"OTgeHA5mOs9sfFwbPr5gyNCkwl0URw6E6CTCLw6xuZgDp3MWnUTfDg4HxzYUMTN8GiYTfDO1npdyfqdp",
+	"dBL9FfTf3v94EbXKWY4PD3eW0d/0/QQy+rEBeQ9jlAjkArTzEn539OdvcLLfHX67x6qHkmUpT4AUnF5R",
+	"ltFxBg3BGJ38+iGOVJHnVC4tGiu2NexscsfrcsBoOXSqjHaCcuUDduikYe/6vGVKW3e2+tQ12sq5VrnO",
+	"W461VRwW+sqlRK3i6NnhUV/3JeDDRtGI+ejbzR9VdU2GHg43fxEqPWot4E19R7CRCudGiT6s4pvGFhB4",
+	"Xd8Q268btIFL6OMNDSLAxgo7i+ZC6Z60XePm8Z8bLaW9xdpUXXxbhivcXlpaRCb3ohaOXVCFLV0zK9qa",
+	"dFePzUf1+r/ljkvsmuH/QGXd8eHRjof04aV+ovb6hiXQLcitVv73xAh9jGCxT6hJK3eYDvFDJRaHN1Z9",
+	"O01Xlj1M/nBHSJ6b+pCSWOt1zb/26arMeBtdZYlTJ3ETrZRJP/TaouPNyQsfOvT8rF+DtvDsU5o+WwdO",
+	"lfH6CMnNUkVdfIaFb59eZOnppaWAtTRlLU2PNENWrnJpn2S1O41ts3zc957/5VLpD4ynJemMl+T0da9I",
+	"xPK6mqLYNreQ5KyP37QkwLVkoIip3fABsAnLNEgV24MaJCTANZkwaRzmAdXTl/QxUJu44Ocqh8IVr9UT",
+	"zY0xxhRx9VehAx58XVT/SQTxNmMak5cp4rx9PSPZBP9PHKqanuDVgRhbAGDaLt/ZBrsEolZZ4HwJTFma",
+	"6gfjNG0AsUU+3u3AotrUlrnySUSMDZOFIDKF8GFw1oaVb0mMvsxyPTAF1yzbATB/p9csL3LCyyxkz5ud",
+	"vaIFQcZyphsQuMCHL0GyPZtfh8Yt637ew46xnf1Y1QBvY0M2BFVtU/nKVG4jsF3+cHdzab9t7i2Nt13D",
+	"s7EX9G0tY5/bFTZEzyzjuIxeV0yq4nCFZ5lkj0/LyD8REzwICEs5MQ+McVuaZgrVKFGMTzMgWlKuLLee",
+	"EGDGk4fBvcolN+KqSBKANLalSdycqmPoFgUNT0k1BtYjKX/SwLPjY1+OhObyiNuKNcOLNJlVQ2B/Elwl",
+	"xpoqusGIv2l+6LQ5iyWTPGH92Pgh08qJ5ZCx/dIdwXMfVnajLHK1WrW1ztU96pHNusYQ/zcWl7i1bdjd",
+	"+z1UxDLCHvXa4+M9Yrvm3Ca2Wi8mXNQeYqKYY6Z7lHZVNlJI2rXfNqVd59tGx81UpmDvoSatITpNGlLV",
+	"CcM63RoZNnZMHBSwrr6uz9R8RaVCQzOgY4eQXzUZ4jf72dld/d+mLR2nYnfy2MpNq/ufvv5sPH36Wj1+",
+	"DaFWYNTDMn36Qf1l1/LEt3Z96soBkmvdLx3yDb8ypZGtLSs87drZcWsOU7g/ry9SbpBSA37ez3B02xdC",
+	"nbuQ6P3+YleJ2yBQJ1mHcO3rUYOukQstgTrtdZIBaILbHHl18Q9TautURE6YOejmEtA5kkIW20paylPU",
+	"Fl2ZtYurG/XT9PLTaxO+rHVkT0MxyRRkDpJkzFTYv7mulfcqjNXUi2CxM6ZCyqH9DsXqJgeMzU/weipc",
+	"N8uJQ+alM2eD9mXEU0P0VZ5toq6i2D8OHIV2u23o+oCnXa4KFGzAtR7i0Gvbhfchj4Mn+R+Q/5awzA7Q",
+	"y1mWPPstQx+idJUGyqYAec7CBHmhwOeiINHZpjUus/xlOC0RWZFz5ZM1G7wVr2Mu8rOegRxx1wF26cqI",
+	"YgLTgRmSmVy3kikQ2Njkm7ApF/aQ1BF/cwVyaWvyFTESGneHATnlhGqRs8TAHJsOHaMYDRqBYmrES27G",
+	"/Dnfj0s7jkvz1D3A9z7lxX2GcxzxprV6inJC6Y8wmeByVQC0OqkkiR1mxBvj+DHMPE/zGhrM14mYszIB",
+	"n6RU0zFVYFx3PIG4PIqJ/1GPeJWX5D2OV8A1UUbOEi4kkZCg8lda0NYLkYlpSMJZaLaRcO9nYNN7hJsu",
+	"4UIb37bL3qtNeDEDTpTIoYYii5AeYYiY7RGFdvFrorB8UFuaXpG4jVG/c2m4Pyu/fSxEnzD2BPrZlC2z",
+	"Ee7Rtj/6bn9z/KWqZSBuRBt/2LGTYYvFPq2JnVI6LmiTAB6VImpnvH6ndFmE69IUXpvn1m5aK+bQMrH5",
+	"Ca6rYCDZDPiJUeR4s4PBHX2+XR4DQu7P5vnM0eHmiV2m1fP9np6MdD8zpZcm5bUsF5SYrTU3NVhMKzJj",
+	"SgsbL3l2dLx56oHzqw2T/+V2n5YnSj82brRspKwuuDalIxwhL8MN9vOQM277pA9cY7wAgfH0/rj0w/3u",
+	"3n3OEX/WXeCiilCPrtnQtFmtHhr7Pz7HXH86CJJm8EDOV/bmj/pBCr4GhsryTAWnlK85nWHExyJdxiQD",
+	"euVTR8yBf6oqEgqp8qafLXa4GvNgaioHSE0wfAy1AxEfyJ63jfqeIxYPzKL8x63578wOtnetvY/v3QrY",
+	"cJxSkyIzRWefIAYeajjfSY79qARdheQTdvuj7/ary6A4IMxkjdOO4Pi69I8zKvHWiWxZ5T/0ayLzIhBA",
+	"KaMfX5uYvJOI+mKk4JNIezJgliX7rxEbxqdQOx8saM68KlOrMJRk6pjNNWzuhgax4GQuBfq8BkErx3/+",
+	"4PMOaud5b0w+KHHylIGwc15onPazPrem1+QJ9REeBM8GWj+IaxEwnUoq6CY2lGcAbcpu8A0/NcWhe9vD",
+	"/eU5lJzSzxlPGQ+75oZ7TDVrJkBUFBmg5vqWMbzxf23nkK46Xu+V9iS0hWu6HP+h+af9HB6Kk9rD85k9",
+	"1R6Mr9tdvQ+ernmvuwx9exd2eV1TQB3EPTagDY74On3wFq5vTzQbCh53Jgzu1Q2+zd75uB3iQVHzpCx6",
+	"tOzU2e473ZfHfcstvM24t3Mqfa5N/Z4d8I3zrffthd9G7Dz54+/HeRXWvZ6c8l+AlhX00a/Vt9Y56r96",
+	"6brbu/O/PHn6JByfDL4tvP1rBFCv/2bozPGtDv4w6dxJIfEbYwC6oxQbF/t2LcURN+fyVqaiGzOkb2JR",
+	"ecXUFrSt5GJDL7Z1AW6YR2NA7rxOslqgOnaiJ0vxgVmKbx17kKS5apt52lRG9DOvxdPBBXBNzNWcyldR",
+	"uLi/v9TVmpoDYs4cMJ0aXx3TeKP2cg6EKvcYmcae40/8fZ/40qiHWNdRv1NixHFCg7J0BBwIZuLs99rt",
+	"bqo8gtdMe1xkly6fOx5xy83Yub3oDWnbl5gYAAbklTmq0J1EaKQ8h0TXDrx9S5U+MI0PTl/7iiEJCbAr",
+	"qGpM3JnAOVPKXGWiSCb4dMSpeUGUQSdRmmUZmQl7jz3kLddlbA3rBVNgu5uCJnTEnx0dGsSZuzMllNFU",
+	"VyRrz4yxy4OYQdBFoZuQh0SmrT+0y7u9rMyo0m5JHRoqQWGQ2XcIcgOeaNcS0hSgGLAOLCpulWxhwAqJ",
+	"w4sG1Tuuedi26tEeo2deNjCetFmFC8MDIMkMMlNwVfPQ7/Xs3Df1KrA1J+fefwato6auAO0T0zUlK1zo",
+	"eG7wT32+d1ifwl6ssHAXCynIrkDFTtSW8TNFNMVjhidS5E7rSiSYc7x7tK76zZ/3ehxr83LRrbxhuwtg",
+	"u/n1JBqV1zfXDLW3wg7UXbBfzt9WJ/Y4tK0pmPus4fC4zNBuX0KQClBGDYNrtmdP3PP9ppHRDBnZ2yym",
+	"BjgXqT3XPhG8NGruURVtXHwVEkaBBk2BFOohNEKfFtp63zrOFBW5dSlj7uvhjf3DBf+3CyTaT7YJI66x",
+	"Df8K2rLw9kFE29smC9BP6AEHEPtll33zuY5M3Q8Huzk+GYz10KKj7bWHunZ51t1DsU4VaTh87FEJ/v4L",
+	"4Km32vpZesTtGJVxY8wgK3pDjG2HLNWPLS0YN39rHTrF6Yvlcquh2JtJviYu/4yKgr/Q9ElV6KgKhrup",
+	"91St0Rl0ectl7/Uj9obI/Vw/Ysfaxn/qoPpSbh9pXOMaWu9AgyZBtRsEHJjlQt7lChLI59pfVzsgp1rZ",
+	"Y8PdHbms6SDk5WW6Ix66+ba6iTQmpQuwdRlpvx1sm92rHWyH2NO1JJ7q+6j8cVxH4naC4ATw2tdScjNO",
+	"CgWPl5nO/B3PLjNbe3JcI2GHN/bfplHWMZ8surY3n2ynmxQrP/QDVqw2ssCDuHHCwbIPM+eeadgYKI56",
+	"1hoo5ZWv4Q3iZxwNb79zlx0CT+eCcXeH2/d/fvb8G+JOJvJ2SXn3qDtZsXNhsMJbAmuX9NrDu5yjolBw",
+	"Yo/sdl96Y0Y5bmzc7+suDLQXwZjBJVwxUSgi8Ny4cyiwWwz+2EZp63P/qb8QXjbhcid7Fyq8W50qVcA7",
+	"d7HstueALRaLA+TFg0JmwBORQnoLNqpfLb3nXMbm5cVr79bbff1R7arwNd5Wv6jl1nh35n0c9xIaCkR1",
+	"rH4TYfgiwkKBXGsH4BW8+7ECcKRtbAAD0dP9g9vcP1i4xVuj+Yc07V9UqARyl1mCakNu4O60bEtVYSp6",
+	"VBr2nk4UdBdFl2q7iVk++nsH3f1TQV0H3w1v8P9bFU465lirphvi2lwwacfcx02DBqAHUv1oYHnUt7dV",
+	"1XyF2lDJ17H1cPbbW3rY/yY7756o6HA/IvhBWHiPnySNZWeoZV1FW3+1wzZSrU6St6py2B2F7l4hqaa/",
+	"p2KFtdwQLFJ4Kt76cvi0qgvoVUnWAdaFpTu8HdFM1XJxIbPoJJppPT8ZDjOR0GwmlD55fvj8cHh1HK3i",
+	"ehN1MnTh6UGeg1KDFK5Mqw8lrB1vUHUnUmmf2k0Lu7GhAjWoxIF9EK0+rP5/ACaBrL4dtwAA",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %s", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	var res = make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	var resolvePath = PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		var pathToFile = url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// mimeMergePatchJSON is the media type of JSON Merge Patch documents (RFC 7396).
+const mimeMergePatchJSON = "application/merge-patch+json"
+
+// readMergePatch reads the JSON Merge Patch document in the body of a request.
+func readMergePatch(ctx echo.Context) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != mimeMergePatchJSON {
+		return nil, echo.NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type must be "+mimeMergePatchJSON)
+	}
+	patch, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return patch, nil
+}
+
+// applyMergePatch applies a JSON Merge Patch document to the JSON
+// representation of target and decodes the result into merged. Fields of the
+// patch unknown to merged are rejected.
+func applyMergePatch(target interface{}, patch []byte, merged interface{}) error {
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return err
+	}
+	targetJSON, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	var targetValue interface{}
+	if err := json.Unmarshal(targetJSON, &targetValue); err != nil {
+		return err
+	}
+	mergedJSON, err := json.Marshal(mergePatch(targetValue, patchValue))
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(mergedJSON))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(merged)
+}
+
+// mergePatch returns target patched as defined by RFC 7396: the members of
+// patch objects replace the members of target recursively, and null members
+// remove them.
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+		} else {
+			targetObject[name] = mergePatch(targetObject[name], value)
+		}
+	}
+	return targetObject
+}
+
+// Partially updates a car
+// (PATCH /car/{carId})
+func (s *Server) PatchCar(ctx echo.Context, carId int64, params gen.PatchCarParams) error {
+	patchDocument, err := readMergePatch(ctx)
+	if err != nil {
+		return err
+	}
+	car, err := s.CarCRUDService.Get(ctx.Request().Context(), int(carId))
+	if err == rental.ErrCarNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if err := checkIfMatch(params.IfMatch, car.Version); err != nil {
+		return err
+	}
+
+	current := gen.CarPatch{Make: &car.Make, Model: &car.Model, Year: &car.Year}
+	var merged gen.CarPatch
+	if err := applyMergePatch(current, patchDocument, &merged); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	after := car
+	after.Make, after.Model, after.Year = valueOrZero(merged.Make), valueOrZero(merged.Model), valueOrZero(merged.Year)
+	if err := after.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Only write the changed details
+	var patch rental.CarPatch
+	if after.Make != car.Make {
+		patch.Make = &after.Make
+	}
+	if after.Model != car.Model {
+		patch.Model = &after.Model
+	}
+	if after.Year != car.Year {
+		patch.Year = &after.Year
+	}
+	if !patch.Empty() {
+		err = s.CarCRUDService.Patch(ctx.Request().Context(), car.ID, car.Version, patch)
+		if err == rental.ErrCarNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		if err == rental.ErrCarVersionMismatch {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		after.Version++
+		s.audit(ctx, rental.AuditActionUpdate, rental.AuditEntityCar, car.ID, car, after)
+	}
+	setETag(ctx, after.Version)
+	return ctx.JSON(http.StatusOK, toAPICar(ctx, after))
+}
+
+// Partially updates a customer
+// (PATCH /customer/{customerId})
+func (s *Server) PatchCustomer(ctx echo.Context, customerId int64, params gen.PatchCustomerParams) error {
+	patchDocument, err := readMergePatch(ctx)
+	if err != nil {
+		return err
+	}
+	customer, err := s.CustomerCRUDService.Get(ctx.Request().Context(), int(customerId))
+	if err == rental.ErrCustomerNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if err := checkIfMatch(params.IfMatch, customer.Version); err != nil {
+		return err
+	}
+
+	current := gen.CustomerPatch{Name: &customer.Name}
+	var merged gen.CustomerPatch
+	if err := applyMergePatch(current, patchDocument, &merged); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	after := customer
+	after.Name = valueOrZero(merged.Name)
+	if err := after.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var patch rental.CustomerPatch
+	if after.Name != customer.Name {
+		patch.Name = &after.Name
+	}
+	if !patch.Empty() {
+		err = s.CustomerCRUDService.Patch(ctx.Request().Context(), customer.ID, customer.Version, patch)
+		if err == rental.ErrCustomerNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		if err == rental.ErrCustomerVersionMismatch {
+			return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		after.Version++
+		s.audit(ctx, rental.AuditActionUpdate, rental.AuditEntityCustomer, customer.ID, customer, after)
+	}
+	setETag(ctx, after.Version)
+	return ctx.JSON(http.StatusOK, toAPICustomer(after))
+}
+
+// valueOrZero returns the value p points to, or the zero value if p is nil,
+// ie. the field was removed by the patch.
+func valueOrZero[T any](p *T) T {
+	var value T
+	if p != nil {
+		value = *p
+	}
+	return value
+}
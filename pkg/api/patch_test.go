@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestMergePatch(t *testing.T) {
+	// Example of RFC 7396, section 3
+	target := `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`
+	patch := `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`
+	want := `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`
+
+	var targetValue, patchValue, wantValue interface{}
+	for s, v := range map[string]*interface{}{target: &targetValue, patch: &patchValue, want: &wantValue} {
+		if err := json.Unmarshal([]byte(s), v); err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+	}
+	if got := mergePatch(targetValue, patchValue); !reflect.DeepEqual(got, wantValue) {
+		t.Errorf("got %v, want %v", got, wantValue)
+	}
+}
+
+func TestServer_PatchCar(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{CarCRUDService: mock.NewMockCarCRUDService()}
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+	if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	patchCar := func(contentType, body string) (*httptest.ResponseRecorder, error) {
+		current, _ := s.CarCRUDService.Get(context.Background(), car.ID)
+		req := httptest.NewRequest(http.MethodPatch, "/car/1", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		resp := httptest.NewRecorder()
+		return resp, s.PatchCar(e.NewContext(req, resp), int64(car.ID), gen.PatchCarParams{IfMatch: ifMatch(current.Version)})
+	}
+
+	// Test
+
+	t.Run("patch the year", func(t *testing.T) {
+		resp, err := patchCar(mimeMergePatchJSON, `{"year":2016}`)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		wantBody := `{"id":1,"make":"Toyota","model":"Corolla","renter_id":0,"year":2016}` + "\n"
+		if resp.Body.String() != wantBody {
+			t.Errorf("got %s, want %s", resp.Body.String(), wantBody)
+		}
+		if got := resp.Header().Get(headerETag); got != `"1"` {
+			t.Errorf("got ETag %s, want \"1\"", got)
+		}
+		got, _ := s.CarCRUDService.Get(context.Background(), car.ID)
+		want := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2016, Version: 1}
+		if got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+	t.Run("invalid patches", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"removes a required field": `{"make":null}`,
+			"unknown field":            `{"renter_id":2}`,
+			"wrong type":               `{"year":"2016"}`,
+			"not JSON":                 `year=2016`,
+		} {
+			_, err := patchCar(mimeMergePatchJSON, body)
+			if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusBadRequest {
+				t.Errorf("%s: got error %v, want %d status code", name, err, http.StatusBadRequest)
+			}
+		}
+	})
+	t.Run("not a merge patch", func(t *testing.T) {
+		_, err := patchCar(echo.MIMEApplicationJSON, `{"year":2017}`)
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("got error %v, want %d status code", err, http.StatusUnsupportedMediaType)
+		}
+	})
+}
+
+// vanishingCars deletes the cars it is asked to change before changing them,
+// as if they were deleted concurrently.
+type vanishingCars struct {
+	*mock.MockCarCRUDService
+}
+
+func (s vanishingCars) Patch(ctx context.Context, carID int, version int, patch rental.CarPatch) error {
+	if err := s.MockCarCRUDService.Delete(ctx, carID, version); err != nil {
+		return err
+	}
+	return s.MockCarCRUDService.Patch(ctx, carID, version, patch)
+}
+
+func TestServer_PatchCar_Deleted(t *testing.T) {
+	s := &Server{CarCRUDService: vanishingCars{mock.NewMockCarCRUDService()}}
+	if _, err := s.CarCRUDService.Create(context.Background(), rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	req := httptest.NewRequest(http.MethodPatch, "/car/1", strings.NewReader(`{"year":2016}`))
+	req.Header.Set(echo.HeaderContentType, mimeMergePatchJSON)
+
+	err := s.PatchCar(echo.New().NewContext(req, httptest.NewRecorder()), 1, gen.PatchCarParams{IfMatch: ifMatch(0)})
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusNotFound {
+		t.Errorf("got error %v, want %d status code", err, http.StatusNotFound)
+	}
+}
+
+func TestServer_PatchCustomer(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{CustomerCRUDService: mock.NewMockCustomerCRUDService()}
+	customer := rental.Customer{ID: 1, Name: "John Doe"}
+	if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, "/customer/1", strings.NewReader(`{"name":"Jane Doe"}`))
+	req.Header.Set(echo.HeaderContentType, mimeMergePatchJSON)
+	resp := httptest.NewRecorder()
+
+	// Test
+
+	if err := s.PatchCustomer(e.NewContext(req, resp), 1, gen.PatchCustomerParams{IfMatch: ifMatch(customer.Version)}); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	got, err := s.CustomerCRUDService.Get(context.Background(), 1)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	want := rental.Customer{ID: 1, Name: "Jane Doe", Version: 1}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
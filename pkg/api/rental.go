@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+	"github.com/shidenkai0/rental/pkg/events"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// toAPIRental converts a rental.Rental to an api.Rental and deals with nullable fields.
+func toAPIRental(r rental.Rental) genv2.Rental {
+	return genv2.Rental{
+		Id:         int64(r.ID),
+		CarId:      int64(r.CarID),
+		CustomerId: int64(r.CustomerID),
+		StartedAt:  r.StartedAt,
+		ReturnedAt: r.ReturnedAt.Ptr(),
+	}
+}
+
+// rentingCustomer returns the customer renting a car, the requested customer,
+// or the customer of the principal when restricted to self-service. param is
+// the name of the request parameter holding the requested customer.
+func rentingCustomer(ctx echo.Context, requested *int64, param string) (int, error) {
+	if self, ok := selfServiceCustomer(ctx, rental.ScopeRentalsWrite); ok {
+		if self == 0 || (requested != nil && int(*requested) != self) {
+			return 0, errForbidden
+		}
+		return self, nil
+	}
+	if requested == nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, param+" is required")
+	}
+	return int(*requested), nil
+}
+
+// startRental rents a car to a customer and records the rental, in a single
+// transaction so that a car is never rented without its rental. The rental
+// is audited and published once the transaction is committed.
+func (s *Server) startRental(ctx echo.Context, carID int, customerID int) (rental.Rental, error) {
+	var before, car rental.Car
+	r := rental.Rental{CustomerID: customerID, StartedAt: time.Now()}
+	err := s.Transactor.WithinTransaction(ctx.Request().Context(), func(txCtx context.Context) error {
+		var err error
+		before, car, err = s.rent(txCtx, carID, customerID)
+		if err != nil {
+			return err
+		}
+		r.CarID = car.ID
+		r.ID, err = s.RentalService.Create(txCtx, r)
+		return err
+	})
+	if err == rental.ErrCarAlreadyRented || err == rental.ErrCarVersionMismatch {
+		s.Metrics.RentConflict()
+	}
+	if err != nil {
+		return rental.Rental{}, err
+	}
+	s.Metrics.RentalStarted()
+	s.audit(ctx, rental.AuditActionRent, rental.AuditEntityCar, car.ID, before, car)
+	s.publish(ctx, events.CarRented, car)
+	return r, nil
+}
+
+// returnCar returns a rented car and ends its rental, in a single
+// transaction. The return is audited and published once the transaction is
+// committed.
+func (s *Server) returnCar(ctx echo.Context, car rental.Car) error {
+	before := car
+	err := s.Transactor.WithinTransaction(ctx.Request().Context(), func(txCtx context.Context) error {
+		if err := car.Return(); err != nil {
+			return err
+		}
+		if err := s.CarCRUDService.Update(txCtx, car); err != nil {
+			return err
+		}
+		r, err := s.RentalService.GetActiveByCar(txCtx, car.ID)
+		if err == rental.ErrRentalNotFound {
+			// The rental wasn't recorded, the car state is the reference
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return s.RentalService.End(txCtx, r.ID, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+	car.Version++
+	s.Metrics.RentalReturned()
+	s.audit(ctx, rental.AuditActionReturn, rental.AuditEntityCar, car.ID, before, car)
+	s.publish(ctx, events.CarReturned, car)
+	return nil
+}
+
+// Rent a car
+// (POST /rentals)
+func (s *ServerV2) CreateRental(ctx echo.Context) error {
+	createRental := genv2.CreateRentalRequest{}
+	if err := ctx.Bind(&createRental); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	customerID, err := rentingCustomer(ctx, createRental.CustomerId, "customer_id")
+	if err != nil {
+		return err
+	}
+
+	r, err := s.startRental(ctx, int(createRental.CarId), customerID)
+	if err == rental.ErrCarNotFound || err == rental.ErrCustomerNotFound {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err == rental.ErrCarAlreadyRented || err == rental.ErrCarVersionMismatch {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	location := fmt.Sprintf("%s/%d", strings.TrimSuffix(ctx.Request().URL.Path, "/"), r.ID)
+	ctx.Response().Header().Set(echo.HeaderLocation, location)
+	return ctx.JSON(http.StatusCreated, toAPIRental(r))
+}
+
+// Find rental by ID
+// (GET /rentals/{rentalId})
+func (s *ServerV2) GetRentalById(ctx echo.Context, rentalId int64) error {
+	r, err := s.RentalService.Get(ctx.Request().Context(), int(rentalId))
+	if err == rental.ErrRentalNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if self, ok := selfServiceCustomer(ctx, rental.ScopeCustomersRead); ok && r.CustomerID != self {
+		return errForbidden
+	}
+	return ctx.JSON(http.StatusOK, toAPIRental(r))
+}
+
+// Return a rented car
+// (POST /rentals/{rentalId}/return)
+func (s *ServerV2) ReturnRental(ctx echo.Context, rentalId int64) error {
+	r, err := s.RentalService.Get(ctx.Request().Context(), int(rentalId))
+	if err == rental.ErrRentalNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if self, ok := selfServiceCustomer(ctx, rental.ScopeRentalsWrite); ok && r.CustomerID != self {
+		return errForbidden
+	}
+	if !r.Active() {
+		return echo.NewHTTPError(http.StatusConflict, rental.ErrRentalAlreadyReturned.Error())
+	}
+
+	car, err := s.CarCRUDService.Get(ctx.Request().Context(), r.CarID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	err = s.returnCar(ctx, car)
+	if err == rental.ErrCarNotRented || err == rental.ErrCarVersionMismatch || err == rental.ErrRentalAlreadyReturned {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	r, err = s.RentalService.Get(ctx.Request().Context(), r.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return ctx.JSON(http.StatusOK, toAPIRental(r))
+}
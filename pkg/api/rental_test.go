@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// createRental posts body to the rentals of the v2 server, and returns the response.
+func createRental(s *ServerV2, body string) (*httptest.ResponseRecorder, error) {
+	req := httptest.NewRequest(http.MethodPost, "/v2/rentals", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+	return resp, s.CreateRental(echo.New().NewContext(req, resp))
+}
+
+// failingRentals fails to record the rentals.
+type failingRentals struct {
+	rental.RentalService
+}
+
+func (failingRentals) Create(ctx context.Context, r rental.Rental) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+// rollbackTransactor records whether the transactions it runs are rolled back.
+type rollbackTransactor struct {
+	rolledBack bool
+}
+
+func (t *rollbackTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(ctx)
+	t.rolledBack = err != nil
+	return err
+}
+
+func TestServerV2_CreateRental(t *testing.T) {
+	t.Run("rent a car", func(t *testing.T) {
+		s := NewServerV2(newSelfServiceTestServer(t))
+
+		resp, err := createRental(s, `{"car_id": 1, "customer_id": 1}`)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if resp.Code != http.StatusCreated {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusCreated)
+		}
+		var got genv2.Rental
+		if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if got.CarId != 1 || got.CustomerId != 1 || got.ReturnedAt != nil {
+			t.Errorf("got %v, want active rental of car 1 to customer 1", got)
+		}
+		if location, want := resp.Header().Get(echo.HeaderLocation), "/v2/rentals/1"; location != want {
+			t.Errorf("got location %s, want %s", location, want)
+		}
+		car, err := s.CarCRUDService.Get(context.Background(), 1)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if car.RenterID() != 1 {
+			t.Errorf("got car rented by customer %d, want customer 1", car.RenterID())
+		}
+	})
+	t.Run("rental failing to be recorded", func(t *testing.T) {
+		server := newSelfServiceTestServer(t)
+		transactor := &rollbackTransactor{}
+		server.RentalService, server.Transactor = failingRentals{server.RentalService}, transactor
+		server.AuditLogService = mock.NewMockAuditLogService()
+		s := NewServerV2(server)
+
+		_, err := createRental(s, `{"car_id": 1, "customer_id": 1}`)
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusInternalServerError {
+			t.Errorf("got error %v, want %d status code", err, http.StatusInternalServerError)
+		}
+		if !transactor.rolledBack {
+			t.Errorf("got transaction committed, want the car rental rolled back")
+		}
+		entries, err := s.AuditLogService.List(context.Background(), rental.AuditFilter{})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("got %d audit entries, want none for a rolled back rental", len(entries))
+		}
+	})
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "rent a rented car", body: `{"car_id": 2, "customer_id": 1}`, wantCode: http.StatusConflict},
+		{name: "rent a non-existent car", body: `{"car_id": 3, "customer_id": 1}`, wantCode: http.StatusBadRequest},
+		{name: "rent to a non-existent customer", body: `{"car_id": 1, "customer_id": 3}`, wantCode: http.StatusBadRequest},
+		{name: "rent without customer", body: `{"car_id": 1}`, wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServerV2(newSelfServiceTestServer(t))
+
+			_, err := createRental(s, tt.body)
+			if he, ok := err.(*echo.HTTPError); !ok || he.Code != tt.wantCode {
+				t.Errorf("got error %v, want %d status code", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestServerV2_GetRentalById(t *testing.T) {
+	// Setup
+
+	s := NewServerV2(newSelfServiceTestServer(t))
+	if _, err := createRental(s, `{"car_id": 1, "customer_id": 1}`); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	// Test
+
+	t.Run("get rental", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v2/rentals/1", nil), resp)
+		if err := s.GetRentalById(ctx, 1); err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		var got genv2.Rental
+		if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if got.Id != 1 || got.CarId != 1 {
+			t.Errorf("got %v, want rental 1 of car 1", got)
+		}
+	})
+	t.Run("get rental of another customer", func(t *testing.T) {
+		ctx := newCustomerContext(httptest.NewRequest(http.MethodGet, "/v2/rentals/1", nil), httptest.NewRecorder(), 2)
+		err := s.GetRentalById(ctx, 1)
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusForbidden {
+			t.Errorf("got error %v, want %d status code", err, http.StatusForbidden)
+		}
+	})
+	t.Run("get non-existent rental", func(t *testing.T) {
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/v2/rentals/2", nil), httptest.NewRecorder())
+		err := s.GetRentalById(ctx, 2)
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusNotFound {
+			t.Errorf("got error %v, want %d status code", err, http.StatusNotFound)
+		}
+	})
+}
+
+func TestServerV2_ReturnRental(t *testing.T) {
+	// Setup
+
+	s := NewServerV2(newSelfServiceTestServer(t))
+	if _, err := createRental(s, `{"car_id": 1, "customer_id": 1}`); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	returnRental := func() (*httptest.ResponseRecorder, error) {
+		resp := httptest.NewRecorder()
+		ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/v2/rentals/1/return", nil), resp)
+		return resp, s.ReturnRental(ctx, 1)
+	}
+
+	// Test
+
+	t.Run("return rental", func(t *testing.T) {
+		resp, err := returnRental()
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		var got genv2.Rental
+		if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if got.ReturnedAt == nil {
+			t.Errorf("got %v, want returned rental", got)
+		}
+		car, err := s.CarCRUDService.Get(context.Background(), 1)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if car.Rented() {
+			t.Errorf("got rented car, want car returned")
+		}
+	})
+	t.Run("return returned rental", func(t *testing.T) {
+		_, err := returnRental()
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusConflict {
+			t.Errorf("got error %v, want %d status code", err, http.StatusConflict)
+		}
+	})
+}
+
+func TestServer_ReturnCar_EndsRental(t *testing.T) {
+	// Setup
+
+	s := newSelfServiceTestServer(t)
+	rentalID, err := s.RentalService.Create(context.Background(), rental.Rental{CarID: 2, CustomerID: 2})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	ctx := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/v1/car/2/return", nil), httptest.NewRecorder())
+
+	// Test
+
+	if err := s.ReturnCar(ctx, 2); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	r, err := s.RentalService.Get(context.Background(), rentalID)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if r.Active() {
+		t.Errorf("got active rental, want rental ended")
+	}
+}
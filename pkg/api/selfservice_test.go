@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+// newSelfServiceTestServer returns a Server with two customers, 1 and 2, and two cars, car 2 being rented by customer 2.
+func newSelfServiceTestServer(t *testing.T) *Server {
+	s := &Server{CarCRUDService: mock.NewMockCarCRUDService(), CustomerCRUDService: mock.NewMockCustomerCRUDService(), RentalService: mock.NewMockRentalService(), Transactor: mock.NewMockTransactor()}
+	for _, customer := range []rental.Customer{{ID: 1, Name: "John Doe"}, {ID: 2, Name: "Jane Doe"}} {
+		if _, err := s.CustomerCRUDService.Create(context.Background(), customer); err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+	}
+	cars := []rental.Car{
+		{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015},
+		{ID: 2, Make: "Ford", Model: "Fiesta", Year: 2016, CustomerID: null.IntFrom(2)},
+	}
+	for _, car := range cars {
+		if _, err := s.CarCRUDService.Create(context.Background(), car); err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+	}
+	return s
+}
+
+// newCustomerContext returns a context authenticated as the customer user of customerID.
+func newCustomerContext(req *http.Request, resp *httptest.ResponseRecorder, customerID int) echo.Context {
+	ctx := echo.New().NewContext(req, resp)
+	auth.SetPrincipal(ctx, auth.Principal{Subject: "john", Role: rental.RoleCustomer, Scopes: rental.RoleCustomer.Scopes(), CustomerID: customerID})
+	return ctx
+}
+
+func TestServer_RentCar_SelfService(t *testing.T) {
+	otherCustomer := int64(2)
+	tests := []struct {
+		name     string
+		params   gen.RentCarParams
+		wantCode int
+	}{
+		{name: "rent a car to themselves", wantCode: http.StatusNoContent},
+		{name: "rent a car to another customer", params: gen.RentCarParams{CustomerId: &otherCustomer}, wantCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			s := newSelfServiceTestServer(t)
+			resp := httptest.NewRecorder()
+			ctx := newCustomerContext(httptest.NewRequest(http.MethodGet, "/car/1/rent", nil), resp, 1)
+
+			// Test
+
+			err := s.RentCar(ctx, 1, tt.params)
+			if tt.wantCode != http.StatusNoContent {
+				if he, ok := err.(*echo.HTTPError); !ok || he.Code != tt.wantCode {
+					t.Errorf("got error %v, want %d status code", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			car, err := s.CarCRUDService.Get(context.Background(), 1)
+			if err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+			if car.RenterID() != 1 {
+				t.Errorf("got car rented by customer %d, want customer 1", car.RenterID())
+			}
+		})
+	}
+}
+
+func TestServer_ReturnCar_SelfService(t *testing.T) {
+	s := newSelfServiceTestServer(t)
+	ctx := newCustomerContext(httptest.NewRequest(http.MethodGet, "/car/2/return", nil), httptest.NewRecorder(), 1)
+
+	err := s.ReturnCar(ctx, 2)
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusForbidden {
+		t.Errorf("got error %v, want %d status code", err, http.StatusForbidden)
+	}
+}
+
+func TestServer_GetCarById_SelfService(t *testing.T) {
+	tests := []struct {
+		name         string
+		customerID   int
+		wantRenterID int
+	}{
+		{name: "car rented by another customer", customerID: 1, wantRenterID: 0},
+		{name: "car rented by themselves", customerID: 2, wantRenterID: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			s := newSelfServiceTestServer(t)
+			resp := httptest.NewRecorder()
+			ctx := newCustomerContext(httptest.NewRequest(http.MethodGet, "/car/2", nil), resp, tt.customerID)
+
+			// Test
+
+			if err := s.GetCarById(ctx, 2); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			var got gen.Car
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if got.RenterId != tt.wantRenterID {
+				t.Errorf("got renter %d, want %d", got.RenterId, tt.wantRenterID)
+			}
+		})
+	}
+}
+
+func TestServer_GetCustomerById_SelfService(t *testing.T) {
+	t.Run("read their own profile", func(t *testing.T) {
+		s := newSelfServiceTestServer(t)
+		resp := httptest.NewRecorder()
+		ctx := newCustomerContext(httptest.NewRequest(http.MethodGet, "/customer/1", nil), resp, 1)
+
+		if err := s.GetCustomerById(ctx, 1); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if resp.Code != http.StatusOK {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+		}
+	})
+	t.Run("read another customer's profile", func(t *testing.T) {
+		s := newSelfServiceTestServer(t)
+		ctx := newCustomerContext(httptest.NewRequest(http.MethodGet, "/customer/2", nil), httptest.NewRecorder(), 1)
+
+		err := s.GetCustomerById(ctx, 2)
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusForbidden {
+			t.Errorf("got error %v, want %d status code", err, http.StatusForbidden)
+		}
+	})
+}
+
+func TestServer_ListCustomerRentals(t *testing.T) {
+	t.Run("list their own rentals", func(t *testing.T) {
+		s := newSelfServiceTestServer(t)
+		resp := httptest.NewRecorder()
+		ctx := newCustomerContext(httptest.NewRequest(http.MethodGet, "/customer/2/rentals", nil), resp, 2)
+
+		if err := s.ListCustomerRentals(ctx, 2); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		var got []gen.Car
+		if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(got) != 1 || got[0].Id != 2 {
+			t.Errorf("got %v, want car 2", got)
+		}
+	})
+	t.Run("list another customer's rentals", func(t *testing.T) {
+		s := newSelfServiceTestServer(t)
+		ctx := newCustomerContext(httptest.NewRequest(http.MethodGet, "/customer/2/rentals", nil), httptest.NewRecorder(), 1)
+
+		err := s.ListCustomerRentals(ctx, 2)
+		if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusForbidden {
+			t.Errorf("got error %v, want %d status code", err, http.StatusForbidden)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// toAPITenant converts a rental.Tenant to an api.Tenant.
+func toAPITenant(tenant rental.Tenant) gen.Tenant {
+	return gen.Tenant{
+		Id:        int64(tenant.ID),
+		Slug:      tenant.Slug,
+		Name:      tenant.Name,
+		CreatedAt: tenant.CreatedAt,
+	}
+}
+
+// List tenants
+// (GET /tenant)
+func (s *Server) ListTenants(ctx echo.Context) error {
+	tenants, err := s.TenantService.List(ctx.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiTenants := make([]gen.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		apiTenants = append(apiTenants, toAPITenant(tenant))
+	}
+	return ctx.JSON(http.StatusOK, apiTenants)
+}
+
+// Provision a new tenant
+// (POST /tenant)
+func (s *Server) CreateTenant(ctx echo.Context) error {
+	createTenant := gen.CreateTenantRequest{}
+	if err := ctx.Bind(&createTenant); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	tenant := rental.Tenant{Slug: createTenant.Slug, Name: createTenant.Name}
+	if err := tenant.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	id, err := s.TenantService.Create(ctx.Request().Context(), tenant)
+	if err == rental.ErrTenantAlreadyExists {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	tenant, err = s.TenantService.Get(ctx.Request().Context(), id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	s.audit(ctx, rental.AuditActionCreate, rental.AuditEntityTenant, tenant.ID, nil, tenant)
+	return ctx.JSON(http.StatusCreated, toAPITenant(tenant))
+}
+
+// Find tenant by ID
+// (GET /tenant/{tenantId})
+func (s *Server) GetTenantById(ctx echo.Context, tenantId int64) error {
+	tenant, err := s.TenantService.Get(ctx.Request().Context(), int(tenantId))
+	if err == rental.ErrTenantNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return ctx.JSON(http.StatusOK, toAPITenant(tenant))
+}
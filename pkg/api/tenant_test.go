@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestServer_CreateTenant(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  gen.CreateTenantRequest
+		wantCode int
+	}{
+		{name: "create a tenant", request: gen.CreateTenantRequest{Slug: "lyon", Name: "Rental Lyon"}, wantCode: http.StatusCreated},
+		{name: "create a tenant with an invalid slug", request: gen.CreateTenantRequest{Slug: "Rental Lyon", Name: "Rental Lyon"}, wantCode: http.StatusBadRequest},
+		{name: "create a tenant with a duplicate slug", request: gen.CreateTenantRequest{Slug: "default", Name: "Default"}, wantCode: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			e := echo.New()
+			s := &Server{TenantService: mock.NewMockTenantService(), AuditLogService: mock.NewMockAuditLogService()}
+			if _, err := s.TenantService.Create(context.Background(), rental.Tenant{Slug: "default", Name: "Default"}); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+
+			createTenantJSON, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest(http.MethodPost, "/tenant", bytes.NewBuffer(createTenantJSON))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+			ctx := e.NewContext(req, resp)
+			ctx.SetPath("/tenant")
+
+			// Test
+
+			err := s.CreateTenant(ctx)
+			if tt.wantCode != http.StatusCreated {
+				he, ok := err.(*echo.HTTPError)
+				if !ok || he.Code != tt.wantCode {
+					t.Errorf("got error %v, want %d status code", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if resp.Code != http.StatusCreated {
+				t.Errorf("got %d status code, want %d", resp.Code, http.StatusCreated)
+			}
+			var got gen.Tenant
+			if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if got.Id != 2 || got.Slug != tt.request.Slug {
+				t.Errorf("got %v, want tenant 2 %s", got, tt.request.Slug)
+			}
+		})
+	}
+}
+
+func TestServer_GetCarById_OtherTenant(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{CarCRUDService: mock.NewMockCarCRUDService()}
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+	if _, err := s.CarCRUDService.Create(rental.WithTenant(context.Background(), 2), car); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/car/1", nil)
+	req = req.WithContext(rental.WithTenant(req.Context(), 3))
+	resp := httptest.NewRecorder()
+	ctx := e.NewContext(req, resp)
+	ctx.SetPath("/car/:carId")
+
+	// Test
+
+	err := s.GetCarById(ctx, 1)
+	he, ok := err.(*echo.HTTPError)
+	if !ok || he.Code != http.StatusNotFound {
+		t.Errorf("got error %v, want %d status code", err, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+)
+
+// errTokensUnavailable is returned by the token endpoints when token issuance is disabled.
+var errTokensUnavailable = echo.NewHTTPError(http.StatusServiceUnavailable, "Token issuance unavailable")
+
+// tokenError responds with an OAuth 2.0 error (RFC 6749, section 5.2).
+func tokenError(ctx echo.Context, code gen.TokenErrorError, description string) error {
+	return ctx.JSON(http.StatusBadRequest, gen.TokenError{Error: code, ErrorDescription: &description})
+}
+
+// Issue an access token
+// (POST /token)
+func (s *Server) IssueToken(ctx echo.Context) error {
+	if s.Tokens == nil {
+		return errTokensUnavailable
+	}
+	// Responses carry credentials, they must not be cached
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	var tokens auth.Tokens
+	var err error
+	scope := ctx.FormValue("scope")
+	switch gen.TokenRequestGrantType(ctx.FormValue("grant_type")) {
+	case gen.Password:
+		username, password := ctx.FormValue("username"), ctx.FormValue("password")
+		if username == "" || password == "" {
+			return tokenError(ctx, gen.InvalidRequest, "username and password are required")
+		}
+		tokens, err = s.Tokens.PasswordGrant(ctx.Request().Context(), username, password, scope)
+	case gen.RefreshToken:
+		refreshToken := ctx.FormValue("refresh_token")
+		if refreshToken == "" {
+			return tokenError(ctx, gen.InvalidRequest, "refresh_token is required")
+		}
+		tokens, err = s.Tokens.RefreshGrant(ctx.Request().Context(), refreshToken, scope)
+	default:
+		return tokenError(ctx, gen.UnsupportedGrantType, "grant_type must be password or refresh_token")
+	}
+	switch err {
+	case nil:
+	case auth.ErrInvalidGrant:
+		// Wrong credentials count as failed authentications, which are throttled
+		auth.SetFailed(ctx)
+		return tokenError(ctx, gen.InvalidGrant, err.Error())
+	case auth.ErrInvalidScope:
+		return tokenError(ctx, gen.InvalidScope, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.JSON(http.StatusOK, gen.TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+		RefreshToken: tokens.RefreshToken,
+		Scope:        tokens.Scope(),
+	})
+}
+
+// Get the keys verifying access tokens
+// (GET /.well-known/jwks.json)
+func (s *Server) GetJWKS(ctx echo.Context) error {
+	if s.Tokens == nil {
+		return errTokensUnavailable
+	}
+	jwks := s.Tokens.Keys.JWKS()
+	keys := make([]gen.JSONWebKey, 0, len(jwks))
+	for _, key := range jwks {
+		keys = append(keys, gen.JSONWebKey{
+			Kty: key.KeyType,
+			Kid: key.KeyID,
+			Use: key.Use,
+			Alg: key.Algorithm,
+			N:   key.Modulus,
+			E:   key.Exponent,
+		})
+	}
+	return ctx.JSON(http.StatusOK, gen.JSONWebKeySet{Keys: keys})
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/ratelimit"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestServer_IssueToken(t *testing.T) {
+	keys, err := auth.GenerateKeySet()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	users := mock.NewMockUserCRUDService()
+	user := rental.User{ID: 1, Username: "jane", Role: rental.RoleAgent}
+	if err := user.SetPassword("correct horse"); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if _, err := users.Create(context.Background(), user); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	s := &Server{Tokens: auth.NewTokenService(keys, "rental", "rental-api", time.Minute, time.Hour, users, mock.NewMockRefreshTokenService())}
+
+	tests := []struct {
+		name      string
+		form      url.Values
+		wantCode  int
+		wantError gen.TokenErrorError
+	}{
+		{name: "password grant", form: url.Values{"grant_type": {"password"}, "username": {"jane"}, "password": {"correct horse"}}, wantCode: http.StatusOK},
+		{name: "wrong password", form: url.Values{"grant_type": {"password"}, "username": {"jane"}, "password": {"battery staple"}}, wantCode: http.StatusBadRequest, wantError: gen.InvalidGrant},
+		{name: "missing password", form: url.Values{"grant_type": {"password"}, "username": {"jane"}}, wantCode: http.StatusBadRequest, wantError: gen.InvalidRequest},
+		{name: "scope beyond role", form: url.Values{"grant_type": {"password"}, "username": {"jane"}, "password": {"correct horse"}, "scope": {"audit:read"}}, wantCode: http.StatusBadRequest, wantError: gen.InvalidScope},
+		{name: "unsupported grant", form: url.Values{"grant_type": {"client_credentials"}}, wantCode: http.StatusBadRequest, wantError: gen.UnsupportedGrantType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(tt.form.Encode()))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+			resp := httptest.NewRecorder()
+			ctx := e.NewContext(req, resp)
+			ctx.SetPath("/token")
+
+			// Test
+
+			if err := s.IssueToken(ctx); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("got %d status code, want %d", resp.Code, tt.wantCode)
+			}
+			if resp.Header().Get(echo.HeaderCacheControl) != "no-store" {
+				t.Errorf("got %q %s header, want no-store", resp.Header().Get(echo.HeaderCacheControl), echo.HeaderCacheControl)
+			}
+
+			if tt.wantCode != http.StatusOK {
+				var got gen.TokenError
+				if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+					t.Errorf("got error %v, want nil", err)
+				}
+				if got.Error != tt.wantError {
+					t.Errorf("got error %q, want %q", got.Error, tt.wantError)
+				}
+				return
+			}
+			var got gen.TokenResponse
+			if err := json.Unmarshal(resp.Body.Bytes(), &got); err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+			if got.TokenType != "Bearer" || got.ExpiresIn != 60 || got.RefreshToken == "" {
+				t.Errorf("got %v, want a Bearer token expiring in 60 seconds with a refresh token", got)
+			}
+			if _, err := s.Tokens.Verify(got.AccessToken); err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestServer_IssueToken_Throttled(t *testing.T) {
+	// Setup
+
+	keys, err := auth.GenerateKeySet()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	users := mock.NewMockUserCRUDService()
+	user := rental.User{ID: 1, Username: "jane", Role: rental.RoleAgent}
+	if err := user.SetPassword("correct horse"); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if _, err := users.Create(context.Background(), user); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	s := &Server{Tokens: auth.NewTokenService(keys, "rental", "rental-api", time.Minute, time.Hour, users, mock.NewMockRefreshTokenService())}
+	e := echo.New()
+	e.POST("/token", s.IssueToken, ratelimit.ThrottleAuthentication(ratelimit.AuthenticationConfig{Failures: ratelimit.Limit{Rate: 0.1, Burst: 3}}))
+
+	issueToken := func(password string) int {
+		form := url.Values{"grant_type": {"password"}, "username": {"jane"}, "password": {password}}
+		req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	// Test
+
+	for i := 0; i < 3; i++ {
+		if got := issueToken("battery staple"); got != http.StatusBadRequest {
+			t.Errorf("got %d status code, want %d", got, http.StatusBadRequest)
+		}
+	}
+	// Even the right password is refused once the budget is exhausted
+	if got := issueToken("correct horse"); got != http.StatusTooManyRequests {
+		t.Errorf("got %d status code, want %d", got, http.StatusTooManyRequests)
+	}
+}
+
+func TestServer_GetJWKS(t *testing.T) {
+	t.Run("token issuance disabled", func(t *testing.T) {
+		e := echo.New()
+		s := &Server{}
+		ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil), httptest.NewRecorder())
+
+		err := s.GetJWKS(ctx)
+		he, ok := err.(*echo.HTTPError)
+		if !ok || he.Code != http.StatusServiceUnavailable {
+			t.Errorf("got error %v, want %d status code", err, http.StatusServiceUnavailable)
+		}
+	})
+}
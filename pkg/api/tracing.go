@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// pathParameter matches the parameters of OpenAPI paths, eg. {carId}.
+var pathParameter = regexp.MustCompile(`\{([^}]+)\}`)
+
+// tracedRouter registers the handlers of the generated server interfaces
+// on a route group, each traced in a span named after its operation ID, eg.
+// RentCar. It implements both gen.EchoRouter and genv2.EchoRouter.
+type tracedRouter struct {
+	group      *echo.Group
+	operations map[string]string
+}
+
+// newTracedRouter returns a tracedRouter registering the operations of spec on g.
+func newTracedRouter(g *echo.Group, spec *openapi3.T) *tracedRouter {
+	operations := map[string]string{}
+	for path, item := range spec.Paths {
+		route := pathParameter.ReplaceAllString(path, ":$1")
+		for method, operation := range item.Operations() {
+			operations[method+" "+route] = operation.OperationID
+		}
+	}
+	return &tracedRouter{group: g, operations: operations}
+}
+
+// add registers h traced in a span named after the operation of method and path.
+func (r *tracedRouter) add(method, path string, h echo.HandlerFunc, m []echo.MiddlewareFunc) *echo.Route {
+	name, ok := r.operations[method+" "+path]
+	if !ok {
+		name = method + " " + path
+	}
+	tracer := otel.Tracer(tracing.InstrumentationName)
+	return r.group.Add(method, path, func(c echo.Context) error {
+		ctx, span := tracer.Start(c.Request().Context(), name)
+		defer span.End()
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		err := h(c)
+		if err != nil {
+			// Client errors are expected, eg. renting a rented car
+			if he, ok := err.(*echo.HTTPError); !ok || he.Code >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, err.Error())
+			}
+		}
+		return err
+	}, m...)
+}
+
+func (r *tracedRouter) CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodConnect, path, h, m)
+}
+
+func (r *tracedRouter) DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodDelete, path, h, m)
+}
+
+func (r *tracedRouter) GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodGet, path, h, m)
+}
+
+func (r *tracedRouter) HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodHead, path, h, m)
+}
+
+func (r *tracedRouter) OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodOptions, path, h, m)
+}
+
+func (r *tracedRouter) PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodPatch, path, h, m)
+}
+
+func (r *tracedRouter) POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodPost, path, h, m)
+}
+
+func (r *tracedRouter) PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodPut, path, h, m)
+}
+
+func (r *tracedRouter) TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodTrace, path, h, m)
+}
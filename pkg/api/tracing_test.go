@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedRouter(t *testing.T) {
+	// Setup
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	swagger, err := gen.GetSwagger()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	e := echo.New()
+	router := newTracedRouter(e.Group("/v1"), swagger)
+	router.GET("/car/:carId/rent", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusConflict, "Car already rented")
+	})
+
+	// Test
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/car/1/rent", nil))
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if got, want := spans[0].Name(), "RentCar"; got != want {
+		t.Errorf("got span %q, want %q", got, want)
+	}
+	if got := spans[0].Status().Code; got != codes.Unset {
+		t.Errorf("got span status %v for a client error, want %v", got, codes.Unset)
+	}
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+// toAPIUser converts a rental.User to an api.User.
+func toAPIUser(user rental.User) gen.User {
+	return gen.User{
+		Id:         int64(user.ID),
+		Username:   user.Username,
+		Role:       gen.Role(user.Role),
+		CustomerId: user.CustomerID.Ptr(),
+	}
+}
+
+// checkCustomer returns an error if the user isn't bound to an existing customer as required by its role.
+func (s *Server) checkCustomer(ctx context.Context, user rental.User) error {
+	if err := user.CheckCustomer(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if !user.CustomerID.Valid {
+		return nil
+	}
+	_, err := s.CustomerCRUDService.Get(ctx, int(user.CustomerID.Int64))
+	if err == rental.ErrCustomerNotFound {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return nil
+}
+
+// checkRole returns an error if the caller doesn't hold every scope of role,
+// or its unrestricted counterpart, so that users can't be used to escalate
+// privileges.
+func checkRole(ctx echo.Context, role rental.Role) error {
+	principal, _ := auth.PrincipalFrom(ctx)
+	for _, scope := range role.Scopes() {
+		if !principal.HasScope(scope) && !principal.HasScope(scope.Unrestricted()) {
+			return echo.NewHTTPError(http.StatusForbidden, "Insufficient permissions to grant role "+string(role))
+		}
+	}
+	return nil
+}
+
+// passwordError converts an error setting the password of a user to an HTTP error.
+func passwordError(err error) error {
+	if err == rental.ErrPasswordTooShort || err == rental.ErrPasswordTooLong {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// checkLastAdmin returns an error if user is the last user of its tenant
+// managing users, so that deleting it or changing its role doesn't lock the
+// tenant out.
+func (s *Server) checkLastAdmin(ctx context.Context, user rental.User) error {
+	if !user.Role.HasScope(rental.ScopeUsersAdmin) {
+		return nil
+	}
+	users, err := s.UserCRUDService.List(ctx)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for _, other := range users {
+		if other.ID != user.ID && other.Role.HasScope(rental.ScopeUsersAdmin) {
+			return nil
+		}
+	}
+	return echo.NewHTTPError(http.StatusConflict, rental.ErrLastAdmin.Error())
+}
+
+// List users
+// (GET /user)
+func (s *Server) ListUsers(ctx echo.Context) error {
+	users, err := s.UserCRUDService.List(ctx.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiUsers := make([]gen.User, 0, len(users))
+	for _, user := range users {
+		apiUsers = append(apiUsers, toAPIUser(user))
+	}
+	return ctx.JSON(http.StatusOK, apiUsers)
+}
+
+// Create a new user
+// (POST /user)
+func (s *Server) CreateUser(ctx echo.Context) error {
+	createUser := gen.CreateUserRequest{}
+	if err := ctx.Bind(&createUser); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	user := rental.User{
+		Username:   createUser.Username,
+		Role:       rental.Role(createUser.Role),
+		CustomerID: null.IntFromPtr(createUser.CustomerId),
+	}
+	if user.Username == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Username must not be empty")
+	}
+	if !user.Role.Valid() {
+		return echo.NewHTTPError(http.StatusBadRequest, rental.ErrInvalidRole.Error())
+	}
+	if err := checkRole(ctx, user.Role); err != nil {
+		return err
+	}
+	if err := s.checkCustomer(ctx.Request().Context(), user); err != nil {
+		return err
+	}
+	if err := user.SetPassword(createUser.Password); err != nil {
+		return passwordError(err)
+	}
+
+	var err error
+	user.ID, err = s.UserCRUDService.Create(ctx.Request().Context(), user)
+	if err == rental.ErrUserAlreadyExists {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	s.audit(ctx, rental.AuditActionCreate, rental.AuditEntityUser, user.ID, nil, user)
+	return ctx.JSON(http.StatusCreated, toAPIUser(user))
+}
+
+// Deletes a user
+// (DELETE /user/{userId})
+func (s *Server) DeleteUser(ctx echo.Context, userId int64) error {
+	user, err := s.UserCRUDService.Get(ctx.Request().Context(), int(userId))
+	if err == rental.ErrUserNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if err := checkRole(ctx, user.Role); err != nil {
+		return err
+	}
+	if err := s.checkLastAdmin(ctx.Request().Context(), user); err != nil {
+		return err
+	}
+	if err := s.UserCRUDService.Delete(ctx.Request().Context(), user.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	s.audit(ctx, rental.AuditActionDelete, rental.AuditEntityUser, user.ID, user, nil)
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Find user by ID
+// (GET /user/{userId})
+func (s *Server) GetUserById(ctx echo.Context, userId int64) error {
+	user, err := s.UserCRUDService.Get(ctx.Request().Context(), int(userId))
+	if err == rental.ErrUserNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return ctx.JSON(http.StatusOK, toAPIUser(user))
+}
+
+// Updates a user
+// (PUT /user/{userId})
+func (s *Server) UpdateUser(ctx echo.Context, userId int64) error {
+	updateUser := gen.UpdateUserRequest{}
+	if err := ctx.Bind(&updateUser); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	before, err := s.UserCRUDService.Get(ctx.Request().Context(), int(userId))
+	if err == rental.ErrUserNotFound {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	// Changing a user of a role beyond the caller's, e.g. its password, would
+	// also let the caller act with that role
+	if err := checkRole(ctx, before.Role); err != nil {
+		return err
+	}
+
+	user := before
+	user.Role = rental.Role(updateUser.Role)
+	user.CustomerID = null.IntFromPtr(updateUser.CustomerId)
+	if !user.Role.Valid() {
+		return echo.NewHTTPError(http.StatusBadRequest, rental.ErrInvalidRole.Error())
+	}
+	if err := checkRole(ctx, user.Role); err != nil {
+		return err
+	}
+	if err := s.checkCustomer(ctx.Request().Context(), user); err != nil {
+		return err
+	}
+	if updateUser.Password != nil {
+		if err := user.SetPassword(*updateUser.Password); err != nil {
+			return passwordError(err)
+		}
+	}
+	if !user.Role.HasScope(rental.ScopeUsersAdmin) {
+		if err := s.checkLastAdmin(ctx.Request().Context(), before); err != nil {
+			return err
+		}
+	}
+
+	if err := s.UserCRUDService.Update(ctx.Request().Context(), user); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	s.audit(ctx, rental.AuditActionUpdate, rental.AuditEntityUser, user.ID, before, user)
+	return ctx.JSON(http.StatusOK, toAPIUser(user))
+}
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// adminPrincipal is the principal of a tenant admin managing users.
+var adminPrincipal = auth.Principal{Subject: "jane", Role: rental.RoleAdmin, Scopes: rental.RoleAdmin.Scopes()}
+
+func TestServer_CreateUser(t *testing.T) {
+	t.Run("create a user", func(t *testing.T) {
+		// Setup
+
+		e := echo.New()
+		s := &Server{UserCRUDService: mock.NewMockUserCRUDService()}
+
+		createUser := gen.CreateUserRequest{Username: "jane", Password: "correct horse", Role: gen.RoleAgent}
+		createUserJSON, _ := json.Marshal(createUser)
+
+		req := httptest.NewRequest(http.MethodPost, "/user", bytes.NewBuffer(createUserJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp := httptest.NewRecorder()
+		ctx := e.NewContext(req, resp)
+		ctx.SetPath("/user")
+		auth.SetPrincipal(ctx, adminPrincipal)
+
+		// Test
+
+		if err := s.CreateUser(ctx); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if resp.Code != http.StatusCreated {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusCreated)
+		}
+
+		wantBody := `{"id":0,"role":"agent","username":"jane"}` + "\n"
+		if resp.Body.String() != wantBody {
+			t.Errorf("got %s, want %s", resp.Body.String(), wantBody)
+		}
+
+		got, err := s.UserCRUDService.GetByUsername(context.Background(), "jane")
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if !got.CheckPassword(createUser.Password) {
+			t.Errorf("got a user whose password doesn't match %q", createUser.Password)
+		}
+	})
+	t.Run("create a customer user without customer", func(t *testing.T) {
+		// Setup
+
+		e := echo.New()
+		s := &Server{UserCRUDService: mock.NewMockUserCRUDService(), CustomerCRUDService: mock.NewMockCustomerCRUDService()}
+
+		createUser := gen.CreateUserRequest{Username: "john", Password: "correct horse", Role: gen.RoleCustomer}
+		createUserJSON, _ := json.Marshal(createUser)
+
+		req := httptest.NewRequest(http.MethodPost, "/user", bytes.NewBuffer(createUserJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp := httptest.NewRecorder()
+		ctx := e.NewContext(req, resp)
+		ctx.SetPath("/user")
+		auth.SetPrincipal(ctx, adminPrincipal)
+
+		// Test
+
+		err := s.CreateUser(ctx)
+		if err == nil {
+			t.Errorf("got nil, want error")
+		}
+
+		he, _ := err.(*echo.HTTPError)
+
+		got, want := he.Code, http.StatusBadRequest
+		if got != want {
+			t.Errorf("got %d status code, want %d", got, want)
+		}
+	})
+	t.Run("create a user with an invalid role", func(t *testing.T) {
+		// Setup
+
+		e := echo.New()
+		s := &Server{UserCRUDService: mock.NewMockUserCRUDService()}
+
+		createUser := gen.CreateUserRequest{Username: "jane", Password: "correct horse", Role: "superuser"}
+		createUserJSON, _ := json.Marshal(createUser)
+
+		req := httptest.NewRequest(http.MethodPost, "/user", bytes.NewBuffer(createUserJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		resp := httptest.NewRecorder()
+		ctx := e.NewContext(req, resp)
+		ctx.SetPath("/user")
+		auth.SetPrincipal(ctx, adminPrincipal)
+
+		// Test
+
+		err := s.CreateUser(ctx)
+		if err == nil {
+			t.Errorf("got nil, want error")
+		}
+
+		he, _ := err.(*echo.HTTPError)
+
+		got, want := he.Code, http.StatusBadRequest
+		if got != want {
+			t.Errorf("got %d status code, want %d", got, want)
+		}
+	})
+}
+
+func TestServer_UpdateUser(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	s := &Server{UserCRUDService: mock.NewMockUserCRUDService()}
+
+	testUserID := 1
+
+	user := rental.User{ID: testUserID, Username: "jane", Role: rental.RoleAgent}
+	if err := user.SetPassword("correct horse"); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if _, err := s.UserCRUDService.Create(context.Background(), user); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	// The password is left unchanged when absent
+	updateUser := gen.UpdateUserRequest{Role: gen.RoleReadOnly}
+	updateUserJSON, _ := json.Marshal(updateUser)
+
+	path := fmt.Sprintf("/user/%d", testUserID)
+	req := httptest.NewRequest(http.MethodPut, path, bytes.NewBuffer(updateUserJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+	ctx := e.NewContext(req, resp)
+	ctx.SetPath(path)
+	auth.SetPrincipal(ctx, adminPrincipal)
+
+	// Test
+
+	if err := s.UpdateUser(ctx, int64(testUserID)); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if resp.Code != http.StatusOK {
+		t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+	}
+
+	got, err := s.UserCRUDService.Get(context.Background(), testUserID)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if got.Role != rental.RoleReadOnly || !got.CheckPassword("correct horse") {
+		t.Errorf("got %v, want read-only user with unchanged password", got)
+	}
+}
+
+func TestServer_CreateUser_PasswordTooLong(t *testing.T) {
+	e := echo.New()
+	s := &Server{UserCRUDService: mock.NewMockUserCRUDService()}
+
+	createUser := gen.CreateUserRequest{Username: "jane", Password: strings.Repeat("correct horse ", 6), Role: gen.RoleAgent}
+	createUserJSON, _ := json.Marshal(createUser)
+	req := httptest.NewRequest(http.MethodPost, "/user", bytes.NewBuffer(createUserJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	ctx := e.NewContext(req, httptest.NewRecorder())
+	auth.SetPrincipal(ctx, adminPrincipal)
+
+	err := s.CreateUser(ctx)
+	if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusBadRequest {
+		t.Errorf("got error %v, want %d status code", err, http.StatusBadRequest)
+	}
+}
+
+func TestServer_LastAdmin(t *testing.T) {
+	// newServer returns a Server with the admin jane, and john of role other.
+	newServer := func(t *testing.T, other rental.Role) *Server {
+		s := &Server{UserCRUDService: mock.NewMockUserCRUDService()}
+		for _, user := range []rental.User{{ID: 1, Username: "jane", Role: rental.RoleAdmin}, {ID: 2, Username: "john", Role: other}} {
+			if _, err := s.UserCRUDService.Create(context.Background(), user); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+		}
+		return s
+	}
+	updateUser := func(s *Server, role gen.Role) error {
+		updateUserJSON, _ := json.Marshal(gen.UpdateUserRequest{Role: role})
+		req := httptest.NewRequest(http.MethodPut, "/user/1", bytes.NewBuffer(updateUserJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		ctx := echo.New().NewContext(req, httptest.NewRecorder())
+		auth.SetPrincipal(ctx, adminPrincipal)
+		return s.UpdateUser(ctx, 1)
+	}
+	deleteUser := func(s *Server) error {
+		req := httptest.NewRequest(http.MethodDelete, "/user/1", nil)
+		ctx := echo.New().NewContext(req, httptest.NewRecorder())
+		auth.SetPrincipal(ctx, adminPrincipal)
+		return s.DeleteUser(ctx, 1)
+	}
+
+	tests := []struct {
+		name     string
+		other    rental.Role
+		change   func(s *Server) error
+		wantCode int
+	}{
+		{name: "demote the last admin", other: rental.RoleAgent, change: func(s *Server) error { return updateUser(s, gen.RoleAgent) }, wantCode: http.StatusConflict},
+		{name: "delete the last admin", other: rental.RoleAgent, change: deleteUser, wantCode: http.StatusConflict},
+		{name: "update the role of the last admin", other: rental.RoleAgent, change: func(s *Server) error { return updateUser(s, gen.RoleAdmin) }},
+		{name: "demote an admin", other: rental.RoleAdmin, change: func(s *Server) error { return updateUser(s, gen.RoleAgent) }},
+		{name: "delete an admin", other: rental.RoleAdmin, change: deleteUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.change(newServer(t, tt.other))
+			if tt.wantCode == 0 && err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+			if he, ok := err.(*echo.HTTPError); tt.wantCode != 0 && (!ok || he.Code != tt.wantCode) {
+				t.Errorf("got error %v, want %d status code", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestServer_RoleEscalation(t *testing.T) {
+	// newServer returns a Server with the operator jane and the agent john.
+	newServer := func(t *testing.T) *Server {
+		s := &Server{UserCRUDService: mock.NewMockUserCRUDService()}
+		for _, user := range []rental.User{{ID: 1, Username: "jane", Role: rental.RoleOperator}, {ID: 2, Username: "john", Role: rental.RoleAgent}} {
+			if _, err := s.UserCRUDService.Create(context.Background(), user); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+		}
+		return s
+	}
+	newContext := func(method, path string, body interface{}) echo.Context {
+		bodyJSON, _ := json.Marshal(body)
+		req := httptest.NewRequest(method, path, bytes.NewBuffer(bodyJSON))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		ctx := echo.New().NewContext(req, httptest.NewRecorder())
+		auth.SetPrincipal(ctx, adminPrincipal)
+		return ctx
+	}
+
+	tests := []struct {
+		name   string
+		change func(s *Server) error
+	}{
+		{name: "create an operator", change: func(s *Server) error {
+			return s.CreateUser(newContext(http.MethodPost, "/user", gen.CreateUserRequest{Username: "mallory", Password: "correct horse", Role: gen.RoleOperator}))
+		}},
+		{name: "promote a user to operator", change: func(s *Server) error {
+			return s.UpdateUser(newContext(http.MethodPut, "/user/2", gen.UpdateUserRequest{Role: gen.RoleOperator}), 2)
+		}},
+		{name: "change the password of an operator", change: func(s *Server) error {
+			password := "correct horse"
+			return s.UpdateUser(newContext(http.MethodPut, "/user/1", gen.UpdateUserRequest{Role: gen.RoleOperator, Password: &password}), 1)
+		}},
+		{name: "delete an operator", change: func(s *Server) error {
+			return s.DeleteUser(newContext(http.MethodDelete, "/user/1", nil), 1)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t)
+			err := tt.change(s)
+			if he, ok := err.(*echo.HTTPError); !ok || he.Code != http.StatusForbidden {
+				t.Errorf("got error %v, want %d status code", err, http.StatusForbidden)
+			}
+
+			users, err := s.UserCRUDService.List(context.Background())
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			for _, user := range users {
+				if user.ID == 2 && user.Role != rental.RoleAgent || user.ID != 1 && user.ID != 2 {
+					t.Errorf("got %v, want users left unchanged", users)
+				}
+			}
+			if len(users) != 2 {
+				t.Errorf("got %d users, want 2", len(users))
+			}
+		})
+	}
+}
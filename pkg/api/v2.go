@@ -0,0 +1,70 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+)
+
+// ServerV2 implements version 2 of the API as defined in the API
+// specification at api/rental-v2.0.yml. The operations unchanged since
+// version 1 are served by Server, with their parameters converted.
+type ServerV2 struct {
+	*Server
+}
+
+// NewServerV2 returns a ServerV2 serving version 2 of the API with the services of server.
+func NewServerV2(server *Server) *ServerV2 {
+	return &ServerV2{Server: server}
+}
+
+// List audit entries
+// (GET /audit)
+func (s *ServerV2) ListAuditEntries(ctx echo.Context, params genv2.ListAuditEntriesParams) error {
+	return s.Server.ListAuditEntries(ctx, gen.ListAuditEntriesParams(params))
+}
+
+// Deletes a car
+// (DELETE /car/{carId})
+func (s *ServerV2) DeleteCar(ctx echo.Context, carId int64, params genv2.DeleteCarParams) error {
+	return s.Server.DeleteCar(ctx, carId, gen.DeleteCarParams(params))
+}
+
+// Partially updates a car
+// (PATCH /car/{carId})
+func (s *ServerV2) PatchCar(ctx echo.Context, carId int64, params genv2.PatchCarParams) error {
+	return s.Server.PatchCar(ctx, carId, gen.PatchCarParams(params))
+}
+
+// Updates a car
+// (PUT /car/{carId})
+func (s *ServerV2) UpdateCar(ctx echo.Context, carId int64, params genv2.UpdateCarParams) error {
+	return s.Server.UpdateCar(ctx, carId, gen.UpdateCarParams(params))
+}
+
+// Deletes a customer
+// (DELETE /customer/{customerId})
+func (s *ServerV2) DeleteCustomer(ctx echo.Context, customerId int64, params genv2.DeleteCustomerParams) error {
+	return s.Server.DeleteCustomer(ctx, customerId, gen.DeleteCustomerParams(params))
+}
+
+// Partially updates a customer
+// (PATCH /customer/{customerId})
+func (s *ServerV2) PatchCustomer(ctx echo.Context, customerId int64, params genv2.PatchCustomerParams) error {
+	return s.Server.PatchCustomer(ctx, customerId, gen.PatchCustomerParams(params))
+}
+
+// Updates a customer
+// (PUT /customer/{customerId})
+func (s *ServerV2) UpdateCustomer(ctx echo.Context, customerId int64, params genv2.UpdateCustomerParams) error {
+	return s.Server.UpdateCustomer(ctx, customerId, gen.UpdateCustomerParams(params))
+}
+
+// Stream car status changes
+// (GET /events)
+func (s *ServerV2) StreamEvents(ctx echo.Context, params genv2.StreamEventsParams) error {
+	return s.Server.StreamEvents(ctx, gen.StreamEventsParams(params))
+}
+
+// ServerV2 must implement all the operations of version 2.
+var _ genv2.ServerInterface = (*ServerV2)(nil)
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/api/genv2"
+	"github.com/shidenkai0/rental/pkg/versioning"
+)
+
+// Versions returns the registry of the versions of the API, all served by
+// server with the same services, and each handler traced. Version 1 is deprecated in favour of
+// version 2, and may stop being served after v1Sunset unless it is zero.
+func Versions(server *Server, v1Sunset time.Time) (*versioning.Registry, error) {
+	v1Spec, err := gen.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	v2Spec, err := genv2.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	return versioning.NewRegistry(
+		versioning.Version{
+			Name: "v1",
+			Spec: v1Spec,
+			Register: func(g *echo.Group) {
+				gen.RegisterHandlers(newTracedRouter(g, v1Spec), server)
+			},
+			Deprecated: true,
+			Sunset:     v1Sunset,
+		},
+		versioning.Version{
+			Name: "v2",
+			Spec: v2Spec,
+			Register: func(g *echo.Group) {
+				genv2.RegisterHandlers(newTracedRouter(g, v2Spec), NewServerV2(server))
+			},
+		},
+	)
+}
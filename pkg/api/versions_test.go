@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/shidenkai0/rental/pkg/versioning"
+)
+
+// TestVersions_Compatibility enforces the compatibility policy within a
+// major version: its spec may only change additively since it was released,
+// as recorded in testdata.
+func TestVersions_Compatibility(t *testing.T) {
+	versions, err := Versions(&Server{}, time.Time{})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	for _, v := range versions.Versions() {
+		t.Run(v.Name, func(t *testing.T) {
+			released, err := openapi3.NewLoader().LoadFromFile(filepath.Join("testdata", "rental-"+v.Name+".0.yml"))
+			if err != nil {
+				t.Fatalf("got error %v, want released spec of %s", err, v.Name)
+			}
+			for _, change := range versioning.BreakingChanges(released, v.Spec) {
+				t.Errorf("breaking change in %s: %s", v.Name, change)
+			}
+		})
+	}
+}
+
+// TestVersions_Successors enforces the compatibility policy between major
+// versions: an operation may only be removed or broken by a new version once
+// it is deprecated in the previous one, with a link to its successor.
+func TestVersions_Successors(t *testing.T) {
+	versions, err := Versions(&Server{}, time.Time{})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	for _, v := range versions.Versions() {
+		successor, ok := versions.Successor(v.Name)
+		if !ok {
+			continue
+		}
+		t.Run(v.Name+" to "+successor.Name, func(t *testing.T) {
+			if !v.Deprecated {
+				t.Errorf("got %s not deprecated, want deprecated in favour of %s", v.Name, successor.Name)
+			}
+			for _, change := range versioning.BreakingChanges(v.Spec, successor.Spec) {
+				method, path, _ := strings.Cut(change.Operation, " ")
+				operation := v.Spec.Paths[path].GetOperation(method)
+				if !operation.Deprecated {
+					t.Errorf("breaking change of an operation not deprecated in %s: %s", v.Name, change)
+					continue
+				}
+				var link string
+				if raw, ok := operation.Extensions["x-successor"].(json.RawMessage); !ok || json.Unmarshal(raw, &link) != nil || !strings.HasPrefix(link, successor.BaseURL()+"/") {
+					t.Errorf("got deprecated operation %s without successor in %s", change.Operation, successor.Name)
+				}
+			}
+		})
+	}
+}
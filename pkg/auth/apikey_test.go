@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestAuthenticate_APIKey(t *testing.T) {
+	keys := mock.NewMockAPIKeyService()
+	key, apiKey, err := rental.NewAPIKey("billing", []rental.Scope{rental.ScopeCarsRead}, null.Time{})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	id, err := keys.Create(context.Background(), apiKey)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	expiredKey, expiredAPIKey, err := rental.NewAPIKey("legacy", []rental.Scope{rental.ScopeCarsRead}, null.TimeFrom(time.Now().Add(-time.Minute)))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if _, err := keys.Create(context.Background(), expiredAPIKey); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		wantCode int
+	}{
+		{name: "valid key", key: key, wantCode: http.StatusOK},
+		{name: "expired key", key: expiredKey, wantCode: http.StatusUnauthorized},
+		{name: "unknown key", key: "rk_unknown", wantCode: http.StatusUnauthorized},
+		{name: "no key", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			e := echo.New()
+			var got Principal
+			e.GET("/car", func(c echo.Context) error {
+				got, _ = PrincipalFrom(c)
+				return c.NoContent(http.StatusOK)
+			}, Authenticate(NewAPIKeyAuthenticator(keys)))
+
+			req := httptest.NewRequest(http.MethodGet, "/car", nil)
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			e.ServeHTTP(resp, req)
+			if resp.Code != tt.wantCode {
+				t.Errorf("got %d status code, want %d", resp.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && (!got.HasScope(rental.ScopeCarsRead) || got.HasScope(rental.ScopeCarsWrite)) {
+				t.Errorf("got principal %v, want the cars:read scope only", got)
+			}
+		})
+	}
+
+	used, err := keys.Get(context.Background(), id)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if !used.LastUsedAt.Valid {
+		t.Errorf("got no last use, want last use recorded")
+	}
+}
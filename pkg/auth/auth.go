@@ -0,0 +1,154 @@
+// Package auth implements authentication and authorization of the requests
+// made to the rental API.
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// principalContextKey is the echo context key under which the authenticated
+// principal is stored.
+const principalContextKey = "rental.principal"
+
+// failedContextKey is the echo context key set on requests whose credentials
+// were rejected by their handler rather than by Authenticate.
+const failedContextKey = "rental.authentication_failed"
+
+// Principal is the authenticated identity performing a request.
+type Principal struct {
+	Subject string
+	UserID  int
+	Role    rental.Role
+	Scopes  []rental.Scope
+	// CustomerID is the customer the principal acts as, 0 if none.
+	CustomerID int
+	// TenantID is the tenant the principal belongs to.
+	TenantID int
+}
+
+// HasScope returns true if the principal was granted scope.
+func (p Principal) HasScope(scope rental.Scope) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PrincipalFromUser returns the principal of an authenticated user.
+func PrincipalFromUser(user rental.User) Principal {
+	return Principal{
+		Subject:    user.Username,
+		UserID:     user.ID,
+		Role:       user.Role,
+		Scopes:     user.Role.Scopes(),
+		CustomerID: int(user.CustomerID.ValueOrZero()),
+		TenantID:   user.TenantID,
+	}
+}
+
+// SetPrincipal stores the authenticated principal in the echo context.
+func SetPrincipal(c echo.Context, principal Principal) {
+	c.Set(principalContextKey, principal)
+}
+
+// PrincipalFrom returns the authenticated principal stored in the echo context.
+func PrincipalFrom(c echo.Context) (Principal, bool) {
+	principal, ok := c.Get(principalContextKey).(Principal)
+	return principal, ok
+}
+
+// SetFailed marks the request of c as a failed authentication, for the
+// credentials checked by handlers, eg. the password grant of the token
+// endpoint, which don't respond with 401 Unauthorized.
+func SetFailed(c echo.Context) {
+	c.Set(failedContextKey, true)
+}
+
+// Failed returns true if the request of c was marked with SetFailed.
+func Failed(c echo.Context) bool {
+	failed, _ := c.Get(failedContextKey).(bool)
+	return failed
+}
+
+var (
+	// ErrNoCredentials is returned by authenticators when the request doesn't
+	// carry the credentials they handle.
+	ErrNoCredentials      = fmt.Errorf("No credentials")
+	ErrInvalidCredentials = fmt.Errorf("Invalid credentials")
+)
+
+// Authenticator authenticates requests carrying a specific kind of credentials.
+type Authenticator interface {
+	// Authenticate returns the principal authenticated by the request's
+	// credentials, ErrNoCredentials if the request carries none of the kind
+	// handled by the authenticator, or ErrInvalidCredentials.
+	Authenticate(c echo.Context) (Principal, error)
+	// Challenge returns the value of the WWW-Authenticate header sent to
+	// unauthenticated clients, empty if none applies.
+	Challenge() string
+}
+
+// AuthenticateConfig defines the config for the Authenticate middleware.
+type AuthenticateConfig struct {
+	// Skipper defines a function to skip the middleware, eg. for public routes.
+	Skipper middleware.Skipper
+	// Authenticators are tried in order, the first one whose credentials are
+	// present in the request authenticates it.
+	Authenticators []Authenticator
+}
+
+// Authenticate returns a middleware authenticating requests with the first
+// authenticator whose credentials are present in the request, and storing the
+// resulting principal in the echo context. Requests without valid credentials
+// are rejected.
+func Authenticate(authenticators ...Authenticator) echo.MiddlewareFunc {
+	return AuthenticateWithConfig(AuthenticateConfig{Authenticators: authenticators})
+}
+
+// AuthenticateWithConfig returns an Authenticate middleware with config.
+func AuthenticateWithConfig(config AuthenticateConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+	authenticators := config.Authenticators
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			for _, authenticator := range authenticators {
+				principal, err := authenticator.Authenticate(c)
+				if err == ErrNoCredentials {
+					continue
+				}
+				if err == ErrInvalidCredentials {
+					return unauthorized(c, authenticators)
+				}
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+				}
+				SetPrincipal(c, principal)
+				return next(c)
+			}
+			return unauthorized(c, authenticators)
+		}
+	}
+}
+
+// unauthorized rejects a request, challenging the client to authenticate.
+func unauthorized(c echo.Context, authenticators []Authenticator) error {
+	for _, authenticator := range authenticators {
+		if challenge := authenticator.Challenge(); challenge != "" {
+			c.Response().Header().Add(echo.HeaderWWWAuthenticate, challenge)
+		}
+	}
+	return echo.NewHTTPError(http.StatusUnauthorized, ErrInvalidCredentials.Error())
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// pathParameter matches the parameters of OpenAPI paths, eg. {carId}.
+var pathParameter = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Authorize returns a middleware authorizing requests against the security
+// requirements of the matching operation in spec, whose routes are served
+// under baseURL. The scopes listed by a security requirement must all be
+// granted to the principal for the requirement to be met, and at least one
+// requirement must be met. Routes that are not described by spec are not
+// served.
+//
+// The middleware must be installed after Authenticate.
+//
+// Operations with no security requirement are public, they are served
+// without a principal.
+func Authorize(spec *openapi3.T, baseURL string) echo.MiddlewareFunc {
+	requirements := operationSecurity(spec, baseURL)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			security, ok := requirements[c.Request().Method+" "+c.Path()]
+			if !ok {
+				return echo.ErrNotFound
+			}
+			if len(security) == 0 {
+				return next(c)
+			}
+			principal, ok := PrincipalFrom(c)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, ErrInvalidCredentials.Error())
+			}
+			for _, requirement := range security {
+				if meetsRequirement(principal, requirement) {
+					return next(c)
+				}
+			}
+			return echo.NewHTTPError(http.StatusForbidden, "Insufficient permissions")
+		}
+	}
+}
+
+// PublicSkipper returns a middleware skipper matching the public operations
+// of spec, which have no security requirement. It lets Authenticate serve
+// public operations to clients that don't carry credentials.
+func PublicSkipper(spec *openapi3.T, baseURL string) middleware.Skipper {
+	requirements := operationSecurity(spec, baseURL)
+	return func(c echo.Context) bool {
+		security, ok := requirements[c.Request().Method+" "+c.Path()]
+		return ok && len(security) == 0
+	}
+}
+
+// operationSecurity returns the security requirements of the operations of
+// spec, keyed by method and echo route, eg. "GET /v1/car/:carId".
+func operationSecurity(spec *openapi3.T, baseURL string) map[string]openapi3.SecurityRequirements {
+	requirements := map[string]openapi3.SecurityRequirements{}
+	for path, item := range spec.Paths {
+		route := baseURL + pathParameter.ReplaceAllString(path, ":$1")
+		for method, operation := range item.Operations() {
+			security := spec.Security
+			if operation.Security != nil {
+				security = *operation.Security
+			}
+			requirements[method+" "+route] = security
+		}
+	}
+	return requirements
+}
+
+// meetsRequirement returns true if the principal was granted all the scopes of requirement.
+func meetsRequirement(principal Principal, requirement openapi3.SecurityRequirement) bool {
+	for _, scopes := range requirement {
+		for _, scope := range scopes {
+			if !principal.HasScope(rental.Scope(scope)) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestAuthorize(t *testing.T) {
+	swagger, err := gen.GetSwagger()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	tests := []struct {
+		name      string
+		anonymous bool
+		role      rental.Role
+		method    string
+		routePath string
+		wantCode  int
+	}{
+		{name: "read-only user reads a car", role: rental.RoleReadOnly, method: http.MethodGet, routePath: "/v1/car/:carId", wantCode: http.StatusOK},
+		{name: "read-only user updates a car", role: rental.RoleReadOnly, method: http.MethodPut, routePath: "/v1/car/:carId", wantCode: http.StatusForbidden},
+		{name: "agent rents a car", role: rental.RoleAgent, method: http.MethodGet, routePath: "/v1/car/:carId/rent", wantCode: http.StatusOK},
+		{name: "agent creates a user", role: rental.RoleAgent, method: http.MethodPost, routePath: "/v1/user", wantCode: http.StatusForbidden},
+		{name: "admin creates a user", role: rental.RoleAdmin, method: http.MethodPost, routePath: "/v1/user", wantCode: http.StatusOK},
+		{name: "anonymous client requests a token", anonymous: true, method: http.MethodPost, routePath: "/v1/token", wantCode: http.StatusOK},
+		{name: "anonymous client reads a car", anonymous: true, method: http.MethodGet, routePath: "/v1/car/:carId", wantCode: http.StatusUnauthorized},
+		{name: "customer rents a car", role: rental.RoleCustomer, method: http.MethodGet, routePath: "/v1/car/:carId/rent", wantCode: http.StatusOK},
+		{name: "customer lists their rentals", role: rental.RoleCustomer, method: http.MethodGet, routePath: "/v1/customer/:customerId/rentals", wantCode: http.StatusOK},
+		{name: "customer creates a car", role: rental.RoleCustomer, method: http.MethodPost, routePath: "/v1/car", wantCode: http.StatusForbidden},
+		{name: "admin provisions a tenant", role: rental.RoleAdmin, method: http.MethodPost, routePath: "/v1/tenant", wantCode: http.StatusForbidden},
+		{name: "operator provisions a tenant", role: rental.RoleOperator, method: http.MethodPost, routePath: "/v1/tenant", wantCode: http.StatusOK},
+		{name: "admin calls a route missing from the spec", role: rental.RoleAdmin, method: http.MethodGet, routePath: "/v1/secret", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			e := echo.New()
+			setPrincipal := func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					if tt.anonymous {
+						return next(c)
+					}
+					SetPrincipal(c, Principal{Subject: "jane", Role: tt.role, Scopes: tt.role.Scopes()})
+					return next(c)
+				}
+			}
+			e.Add(tt.method, tt.routePath, func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, setPrincipal, Authorize(swagger, "/v1"))
+
+			req := httptest.NewRequest(tt.method, tt.routePath, nil)
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			e.ServeHTTP(resp, req)
+			if resp.Code != tt.wantCode {
+				t.Errorf("got %d status code, want %d", resp.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// dummyUser is checked against when a username doesn't exist, so that
+// response times don't reveal which usernames do.
+var dummyUser = func() rental.User {
+	var user rental.User
+	if err := user.SetPassword("dummy password"); err != nil {
+		panic(err)
+	}
+	return user
+}()
+
+// BasicAuthenticator authenticates users with HTTP Basic Authentication.
+type BasicAuthenticator struct {
+	Users rental.UserCRUDService
+}
+
+// Authenticate authenticates the user whose username and password are in the Authorization header.
+func (a *BasicAuthenticator) Authenticate(c echo.Context) (Principal, error) {
+	username, password, ok := c.Request().BasicAuth()
+	if !ok {
+		return Principal{}, ErrNoCredentials
+	}
+	user, err := a.Users.GetByUsername(c.Request().Context(), username)
+	if err == rental.ErrUserNotFound {
+		dummyUser.CheckPassword(password)
+		return Principal{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+	if !user.CheckPassword(password) {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return PrincipalFromUser(user), nil
+}
+
+// Challenge returns the Basic Authentication challenge.
+func (a *BasicAuthenticator) Challenge() string {
+	return `Basic realm="Restricted"`
+}
+
+// NewBasicAuthenticator returns a new BasicAuthenticator checking credentials against users.
+func NewBasicAuthenticator(users rental.UserCRUDService) *BasicAuthenticator {
+	return &BasicAuthenticator{Users: users}
+}
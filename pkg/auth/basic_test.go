@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestAuthenticate_Basic(t *testing.T) {
+	users := mock.NewMockUserCRUDService()
+	user := rental.User{ID: 1, Username: "jane", Role: rental.RoleAgent}
+	if err := user.SetPassword("correct horse"); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if _, err := users.Create(context.Background(), user); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	tests := []struct {
+		name               string
+		username, password string
+		wantCode           int
+	}{
+		{name: "valid credentials", username: "jane", password: "correct horse", wantCode: http.StatusOK},
+		{name: "wrong password", username: "jane", password: "battery staple", wantCode: http.StatusUnauthorized},
+		{name: "unknown user", username: "john", password: "correct horse", wantCode: http.StatusUnauthorized},
+		{name: "no credentials", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			e := echo.New()
+			var got Principal
+			e.GET("/car", func(c echo.Context) error {
+				got, _ = PrincipalFrom(c)
+				return c.NoContent(http.StatusOK)
+			}, Authenticate(NewBasicAuthenticator(users)))
+
+			req := httptest.NewRequest(http.MethodGet, "/car", nil)
+			if tt.username != "" {
+				req.SetBasicAuth(tt.username, tt.password)
+			}
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			e.ServeHTTP(resp, req)
+			if resp.Code != tt.wantCode {
+				t.Errorf("got %d status code, want %d", resp.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusUnauthorized && resp.Header().Get(echo.HeaderWWWAuthenticate) == "" {
+				t.Errorf("got no %s header, want a challenge", echo.HeaderWWWAuthenticate)
+			}
+			if tt.wantCode == http.StatusOK && (got.Subject != "jane" || !got.HasScope(rental.ScopeCarsWrite)) {
+				t.Errorf("got principal %v, want jane with the agent scopes", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// refreshTokenBytes is the number of random bytes of refresh tokens.
+const refreshTokenBytes = 32
+
+var (
+	// ErrInvalidGrant is returned when the credentials or refresh token
+	// presented to the token service are invalid, expired or revoked.
+	ErrInvalidGrant = fmt.Errorf("Invalid grant")
+	// ErrInvalidScope is returned when the requested scopes exceed the
+	// scopes available to the user or refresh token.
+	ErrInvalidScope = fmt.Errorf("Invalid scope")
+)
+
+// accessTokenClaims are the claims of the access tokens issued by TokenService.
+type accessTokenClaims struct {
+	jwt.StandardClaims
+	Scope      string      `json:"scope"`
+	Role       rental.Role `json:"role"`
+	UserID     int         `json:"uid"`
+	CustomerID int         `json:"cid,omitempty"` // Customer bound to the user, if any
+	TenantID   int         `json:"tid"`           // Tenant of the user
+}
+
+// Tokens are the tokens issued by a grant.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+	Scopes       []rental.Scope
+}
+
+// Scope returns the granted scopes as a space-separated list.
+func (t Tokens) Scope() string {
+	return formatScopes(t.Scopes)
+}
+
+// TokenService issues signed JWT access tokens along with refresh tokens, and
+// verifies the access tokens it issued.
+type TokenService struct {
+	Keys            *KeySet
+	Issuer          string
+	Audience        string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	Users           rental.UserCRUDService
+	RefreshTokens   rental.RefreshTokenService
+}
+
+// PasswordGrant issues tokens to the user authenticated by username and
+// password. scope is the space-separated list of requested scopes, all the
+// scopes of the user's role are granted when empty.
+func (s *TokenService) PasswordGrant(ctx context.Context, username, password, scope string) (Tokens, error) {
+	user, err := s.Users.GetByUsername(ctx, username)
+	if err == rental.ErrUserNotFound {
+		dummyUser.CheckPassword(password)
+		return Tokens{}, ErrInvalidGrant
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+	if !user.CheckPassword(password) {
+		return Tokens{}, ErrInvalidGrant
+	}
+	scopes, err := grantScopes(scope, user.Role.Scopes())
+	if err != nil {
+		return Tokens{}, err
+	}
+	return s.issue(ctx, user, scopes)
+}
+
+// RefreshGrant exchanges a refresh token for new tokens. The refresh token is
+// revoked, and presenting a revoked refresh token again revokes all the
+// refresh tokens of its user, as the token has most likely been stolen.
+// Granted scopes are limited to the current scopes of the user's role.
+func (s *TokenService) RefreshGrant(ctx context.Context, refreshToken, scope string) (Tokens, error) {
+	token, err := s.RefreshTokens.GetByHash(ctx, hashRefreshToken(refreshToken))
+	if err == rental.ErrRefreshTokenNotFound {
+		return Tokens{}, ErrInvalidGrant
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+	ctx = rental.WithTenant(ctx, token.TenantID)
+	if token.RevokedAt.Valid {
+		return Tokens{}, s.revokeReused(ctx, token)
+	}
+	if !token.Valid(time.Now()) {
+		return Tokens{}, ErrInvalidGrant
+	}
+	user, err := s.Users.Get(ctx, token.UserID)
+	if err == rental.ErrUserNotFound {
+		return Tokens{}, ErrInvalidGrant
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	available := intersectScopes(parseScopes(token.Scope), user.Role.Scopes())
+	scopes, err := grantScopes(scope, available)
+	if err != nil {
+		return Tokens{}, err
+	}
+	// Revoking is what makes the token single use: of concurrent refreshes
+	// with the same token, only the first to revoke it gets new tokens
+	err = s.RefreshTokens.Revoke(ctx, token.ID)
+	if err == rental.ErrRefreshTokenRevoked {
+		return Tokens{}, s.revokeReused(ctx, token)
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+	return s.issue(ctx, user, scopes)
+}
+
+// revokeReused revokes all the refresh tokens of the user of a refresh token
+// used after being revoked, as it may have been stolen, and returns
+// ErrInvalidGrant.
+func (s *TokenService) revokeReused(ctx context.Context, token rental.RefreshToken) error {
+	if err := s.RefreshTokens.RevokeAllForUser(ctx, token.UserID); err != nil {
+		return err
+	}
+	return ErrInvalidGrant
+}
+
+// issue issues an access token and a refresh token granting scopes to user.
+func (s *TokenService) issue(ctx context.Context, user rental.User, scopes []rental.Scope) (Tokens, error) {
+	accessToken, err := s.signAccessToken(user, scopes)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	secret := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return Tokens{}, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(secret)
+	_, err = s.RefreshTokens.Create(rental.WithTenant(ctx, user.TenantID), rental.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashRefreshToken(refreshToken),
+		Scope:     formatScopes(scopes),
+		ExpiresAt: time.Now().Add(s.RefreshTokenTTL),
+	})
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresIn: s.AccessTokenTTL, Scopes: scopes}, nil
+}
+
+// signAccessToken returns an access token granting scopes to user, signed with the active key.
+func (s *TokenService) signAccessToken(user rental.User, scopes []rental.Scope) (string, error) {
+	now := time.Now()
+	claims := accessTokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    s.Issuer,
+			Audience:  s.Audience,
+			Subject:   user.Username,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(s.AccessTokenTTL).Unix(),
+		},
+		Scope:      formatScopes(scopes),
+		Role:       user.Role,
+		UserID:     user.ID,
+		CustomerID: int(user.CustomerID.ValueOrZero()),
+		TenantID:   user.TenantID,
+	}
+	key := s.Keys.Active()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Key)
+}
+
+// Verify returns the principal authenticated by an access token, or
+// ErrInvalidCredentials if the token isn't a valid token issued by the service.
+func (s *TokenService) Verify(accessToken string) (Principal, error) {
+	parser := jwt.Parser{ValidMethods: []string{jwt.SigningMethodRS256.Alg()}}
+	claims := &accessTokenClaims{}
+	_, err := parser.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (interface{}, error) {
+		id, _ := token.Header["kid"].(string)
+		key, ok := s.Keys.Get(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", id)
+		}
+		return &key.Key.PublicKey, nil
+	})
+	if err != nil {
+		return Principal{}, ErrInvalidCredentials
+	}
+	if !claims.VerifyIssuer(s.Issuer, true) || !claims.VerifyAudience(s.Audience, true) {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return Principal{
+		Subject:    claims.Subject,
+		UserID:     claims.UserID,
+		Role:       claims.Role,
+		Scopes:     parseScopes(claims.Scope),
+		CustomerID: claims.CustomerID,
+		TenantID:   claims.TenantID,
+	}, nil
+}
+
+// NewTokenService returns a new TokenService signing access tokens with keys.
+func NewTokenService(keys *KeySet, issuer, audience string, accessTokenTTL, refreshTokenTTL time.Duration, users rental.UserCRUDService, refreshTokens rental.RefreshTokenService) *TokenService {
+	return &TokenService{
+		Keys:            keys,
+		Issuer:          issuer,
+		Audience:        audience,
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+		Users:           users,
+		RefreshTokens:   refreshTokens,
+	}
+}
+
+// BearerAuthenticator authenticates requests with access tokens issued by a TokenService.
+type BearerAuthenticator struct {
+	Tokens *TokenService
+}
+
+// Authenticate authenticates the access token in the Authorization header.
+func (a *BearerAuthenticator) Authenticate(c echo.Context) (Principal, error) {
+	const prefix = "Bearer "
+	header := c.Request().Header.Get(echo.HeaderAuthorization)
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return Principal{}, ErrNoCredentials
+	}
+	return a.Tokens.Verify(header[len(prefix):])
+}
+
+// Challenge returns the Bearer Authentication challenge.
+func (a *BearerAuthenticator) Challenge() string {
+	return `Bearer realm="rental"`
+}
+
+// NewBearerAuthenticator returns a new BearerAuthenticator verifying access tokens with tokens.
+func NewBearerAuthenticator(tokens *TokenService) *BearerAuthenticator {
+	return &BearerAuthenticator{Tokens: tokens}
+}
+
+// hashRefreshToken returns the hash under which a refresh token is stored.
+func hashRefreshToken(refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(hash[:])
+}
+
+// grantScopes returns the scopes of the space-separated list scope, or
+// available if scope is empty. ErrInvalidScope is returned if a requested
+// scope isn't available.
+func grantScopes(scope string, available []rental.Scope) ([]rental.Scope, error) {
+	requested := parseScopes(scope)
+	if len(requested) == 0 {
+		return available, nil
+	}
+	if len(intersectScopes(requested, available)) != len(requested) {
+		return nil, ErrInvalidScope
+	}
+	return requested, nil
+}
+
+// intersectScopes returns the scopes of a that are also in b.
+func intersectScopes(a, b []rental.Scope) []rental.Scope {
+	scopes := []rental.Scope{}
+	for _, scope := range a {
+		for _, other := range b {
+			if scope == other {
+				scopes = append(scopes, scope)
+				break
+			}
+		}
+	}
+	return scopes
+}
+
+// parseScopes parses a space-separated list of scopes.
+func parseScopes(scope string) []rental.Scope {
+	fields := strings.Fields(scope)
+	scopes := make([]rental.Scope, 0, len(fields))
+	for _, field := range fields {
+		scopes = append(scopes, rental.Scope(field))
+	}
+	return scopes
+}
+
+// formatScopes formats scopes as a space-separated list.
+func formatScopes(scopes []rental.Scope) string {
+	fields := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		fields = append(fields, string(scope))
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// newTestTokenService returns a TokenService with a generated key and a single agent user, jane, of tenant 2.
+func newTestTokenService(t *testing.T) *TokenService {
+	keys, err := GenerateKeySet()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	users := mock.NewMockUserCRUDService()
+	user := rental.User{ID: 1, Username: "jane", Role: rental.RoleAgent}
+	if err := user.SetPassword("correct horse"); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if _, err := users.Create(rental.WithTenant(context.Background(), 2), user); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	return NewTokenService(keys, "rental", "rental-api", time.Minute, time.Hour, users, mock.NewMockRefreshTokenService())
+}
+
+// staleRefreshTokens revokes the refresh tokens right after they're read, as
+// a concurrent refresh with the same token would.
+type staleRefreshTokens struct {
+	*mock.MockRefreshTokenService
+}
+
+func (s staleRefreshTokens) GetByHash(ctx context.Context, tokenHash string) (rental.RefreshToken, error) {
+	token, err := s.MockRefreshTokenService.GetByHash(ctx, tokenHash)
+	if err == nil {
+		s.MockRefreshTokenService.Revoke(rental.WithTenant(ctx, token.TenantID), token.ID)
+	}
+	return token, err
+}
+
+func TestTokenService_PasswordGrant(t *testing.T) {
+	tests := []struct {
+		name               string
+		username, password string
+		scope              string
+		wantErr            error
+		wantScopes         []rental.Scope
+	}{
+		{name: "all role scopes", username: "jane", password: "correct horse", wantScopes: rental.RoleAgent.Scopes()},
+		{name: "requested scopes", username: "jane", password: "correct horse", scope: "cars:read", wantScopes: []rental.Scope{rental.ScopeCarsRead}},
+		{name: "scope beyond role", username: "jane", password: "correct horse", scope: "cars:read users:admin", wantErr: ErrInvalidScope},
+		{name: "wrong password", username: "jane", password: "battery staple", wantErr: ErrInvalidGrant},
+		{name: "unknown user", username: "john", password: "correct horse", wantErr: ErrInvalidGrant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := newTestTokenService(t)
+
+			got, err := tokens.PasswordGrant(context.Background(), tt.username, tt.password, tt.scope)
+			if err != tt.wantErr {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Scope() != formatScopes(tt.wantScopes) {
+				t.Errorf("got scope %q, want %q", got.Scope(), formatScopes(tt.wantScopes))
+			}
+
+			principal, err := tokens.Verify(got.AccessToken)
+			if err != nil {
+				t.Errorf("got error %v, want nil", err)
+			}
+			if principal.Subject != "jane" || principal.UserID != 1 || principal.TenantID != 2 || formatScopes(principal.Scopes) != formatScopes(tt.wantScopes) {
+				t.Errorf("got principal %v, want jane of tenant 2 with scopes %v", principal, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestTokenService_RefreshGrant(t *testing.T) {
+	t.Run("refresh tokens are rotated", func(t *testing.T) {
+		tokens := newTestTokenService(t)
+		issued, err := tokens.PasswordGrant(context.Background(), "jane", "correct horse", "")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+
+		refreshed, err := tokens.RefreshGrant(context.Background(), issued.RefreshToken, "cars:read")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if refreshed.RefreshToken == issued.RefreshToken {
+			t.Errorf("got the same refresh token, want a new one")
+		}
+		if refreshed.Scope() != "cars:read" {
+			t.Errorf("got scope %q, want %q", refreshed.Scope(), "cars:read")
+		}
+		if _, err := tokens.RefreshGrant(context.Background(), refreshed.RefreshToken, "users:admin"); err != ErrInvalidScope {
+			t.Errorf("got error %v, want %v", err, ErrInvalidScope)
+		}
+	})
+	t.Run("reusing a refresh token revokes all the user's tokens", func(t *testing.T) {
+		tokens := newTestTokenService(t)
+		issued, err := tokens.PasswordGrant(context.Background(), "jane", "correct horse", "")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		refreshed, err := tokens.RefreshGrant(context.Background(), issued.RefreshToken, "")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+
+		if _, err := tokens.RefreshGrant(context.Background(), issued.RefreshToken, ""); err != ErrInvalidGrant {
+			t.Errorf("got error %v, want %v", err, ErrInvalidGrant)
+		}
+		if _, err := tokens.RefreshGrant(context.Background(), refreshed.RefreshToken, ""); err != ErrInvalidGrant {
+			t.Errorf("got error %v, want %v", err, ErrInvalidGrant)
+		}
+	})
+	t.Run("refreshing concurrently with the same token revokes all the user's tokens", func(t *testing.T) {
+		tokens := newTestTokenService(t)
+		issued, err := tokens.PasswordGrant(context.Background(), "jane", "correct horse", "")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		other, err := tokens.PasswordGrant(context.Background(), "jane", "correct horse", "")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		refreshTokens := tokens.RefreshTokens.(*mock.MockRefreshTokenService)
+		tokens.RefreshTokens = staleRefreshTokens{refreshTokens}
+
+		if _, err := tokens.RefreshGrant(context.Background(), issued.RefreshToken, ""); err != ErrInvalidGrant {
+			t.Errorf("got error %v, want %v", err, ErrInvalidGrant)
+		}
+		tokens.RefreshTokens = refreshTokens
+		if _, err := tokens.RefreshGrant(context.Background(), other.RefreshToken, ""); err != ErrInvalidGrant {
+			t.Errorf("got error %v, want %v", err, ErrInvalidGrant)
+		}
+	})
+	t.Run("unknown refresh token", func(t *testing.T) {
+		tokens := newTestTokenService(t)
+		if _, err := tokens.RefreshGrant(context.Background(), "unknown", ""); err != ErrInvalidGrant {
+			t.Errorf("got error %v, want %v", err, ErrInvalidGrant)
+		}
+	})
+}
+
+func TestTokenService_Verify(t *testing.T) {
+	jane := rental.User{ID: 1, Username: "jane", Role: rental.RoleAgent}
+
+	t.Run("token signed by a rotated key", func(t *testing.T) {
+		tokens := newTestTokenService(t)
+		previousKey := tokens.Keys.Active()
+		accessToken, err := tokens.signAccessToken(jane, jane.Role.Scopes())
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+
+		rotated, err := GenerateKeySet()
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		tokens.Keys, err = NewKeySet(rotated.Active().ID, rotated.Active(), previousKey)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if _, err := tokens.Verify(accessToken); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+
+		tokens.Keys = rotated
+		if _, err := tokens.Verify(accessToken); err != ErrInvalidCredentials {
+			t.Errorf("got error %v, want %v", err, ErrInvalidCredentials)
+		}
+	})
+	t.Run("expired token", func(t *testing.T) {
+		tokens := newTestTokenService(t)
+		tokens.AccessTokenTTL = -time.Minute
+		accessToken, err := tokens.signAccessToken(jane, jane.Role.Scopes())
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if _, err := tokens.Verify(accessToken); err != ErrInvalidCredentials {
+			t.Errorf("got error %v, want %v", err, ErrInvalidCredentials)
+		}
+	})
+	t.Run("token for another audience", func(t *testing.T) {
+		tokens := newTestTokenService(t)
+		tokens.Audience = "billing-api"
+		accessToken, err := tokens.signAccessToken(jane, jane.Role.Scopes())
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		tokens.Audience = "rental-api"
+		if _, err := tokens.Verify(accessToken); err != ErrInvalidCredentials {
+			t.Errorf("got error %v, want %v", err, ErrInvalidCredentials)
+		}
+	})
+}
+
+func TestAuthenticate_Bearer(t *testing.T) {
+	tokens := newTestTokenService(t)
+	issued, err := tokens.PasswordGrant(context.Background(), "jane", "correct horse", "cars:read")
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantCode      int
+	}{
+		{name: "valid token", authorization: "Bearer " + issued.AccessToken, wantCode: http.StatusOK},
+		{name: "invalid token", authorization: "Bearer " + issued.RefreshToken, wantCode: http.StatusUnauthorized},
+		{name: "no credentials", wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			e := echo.New()
+			var got Principal
+			e.GET("/car", func(c echo.Context) error {
+				got, _ = PrincipalFrom(c)
+				return c.NoContent(http.StatusOK)
+			}, Authenticate(NewBearerAuthenticator(tokens)))
+
+			req := httptest.NewRequest(http.MethodGet, "/car", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			e.ServeHTTP(resp, req)
+			if resp.Code != tt.wantCode {
+				t.Errorf("got %d status code, want %d", resp.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusUnauthorized && resp.Header().Get(echo.HeaderWWWAuthenticate) != `Bearer realm="rental"` {
+				t.Errorf("got %q challenge, want the Bearer challenge", resp.Header().Get(echo.HeaderWWWAuthenticate))
+			}
+			if tt.wantCode == http.StatusOK && (got.Subject != "jane" || !got.HasScope(rental.ScopeCarsRead) || got.HasScope(rental.ScopeCarsWrite)) {
+				t.Errorf("got principal %v, want jane with the cars:read scope only", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// generatedKeyBits is the size of the RSA keys generated by GenerateKeySet.
+const generatedKeyBits = 2048
+
+// SigningKey is an RSA key used to sign and verify access tokens.
+type SigningKey struct {
+	ID  string
+	Key *rsa.PrivateKey
+}
+
+// KeySet holds the keys used to sign and verify access tokens. Tokens are
+// signed with the active key only, but are verified with any key of the set,
+// so that keys can be rotated without invalidating the tokens in flight:
+// first add a new key and make it active, then remove the previous key once
+// the tokens it signed have expired.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// Active returns the key used to sign new tokens.
+func (s *KeySet) Active() *SigningKey {
+	return s.active
+}
+
+// Get returns the key with the given ID.
+func (s *KeySet) Get(id string) (*SigningKey, bool) {
+	key, ok := s.keys[id]
+	return key, ok
+}
+
+// JSONWebKey is the public part of a signing key, in the JSON Web Key format (RFC 7517).
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKS returns the public keys of the set, which clients may use to verify access tokens.
+func (s *KeySet) JWKS() []JSONWebKey {
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	jwks := make([]JSONWebKey, 0, len(ids))
+	for _, id := range ids {
+		publicKey := s.keys[id].Key.PublicKey
+		jwks = append(jwks, JSONWebKey{
+			KeyType:   "RSA",
+			KeyID:     id,
+			Use:       "sig",
+			Algorithm: jwt.SigningMethodRS256.Alg(),
+			Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		})
+	}
+	return jwks
+}
+
+// NewKeySet returns a KeySet of keys, signing with the key whose ID is activeKeyID.
+func NewKeySet(activeKeyID string, keys ...*SigningKey) (*KeySet, error) {
+	set := &KeySet{keys: map[string]*SigningKey{}}
+	for _, key := range keys {
+		set.keys[key.ID] = key
+	}
+	active, ok := set.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found", activeKeyID)
+	}
+	set.active = active
+	return set, nil
+}
+
+// LoadKeySet loads the PEM encoded RSA private keys of dir, each named after
+// its key ID with a .pem extension, eg. 2022-06.pem. activeKeyID may be empty
+// when dir contains a single key.
+func LoadKeySet(dir string, activeKeyID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	var keys []*SigningKey
+	for _, path := range paths {
+		pemBytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing signing key %s: %w", path, err)
+		}
+		keys = append(keys, &SigningKey{ID: strings.TrimSuffix(filepath.Base(path), ".pem"), Key: key})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing key found in %s", dir)
+	}
+	if activeKeyID == "" {
+		if len(keys) > 1 {
+			return nil, fmt.Errorf("%d signing keys found in %s, the active key ID must be set", len(keys), dir)
+		}
+		activeKeyID = keys[0].ID
+	}
+	return NewKeySet(activeKeyID, keys...)
+}
+
+// GenerateKeySet returns a KeySet made of a single random key. Tokens signed
+// with it can't be verified by other instances of the service, nor after a
+// restart, so it is only suitable for local development.
+func GenerateKeySet() (*KeySet, error) {
+	key, err := rsa.GenerateKey(rand.Reader, generatedKeyBits)
+	if err != nil {
+		return nil, err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	id := "generated-" + hex.EncodeToString(suffix)
+	return NewKeySet(id, &SigningKey{ID: id, Key: key})
+}
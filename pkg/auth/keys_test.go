@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeKey writes a generated key to dir as <id>.pem.
+func writeKey(t *testing.T, dir, id string) {
+	keys, err := GenerateKeySet()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(keys.Active().Key)})
+	if err := os.WriteFile(filepath.Join(dir, id+".pem"), pemBytes, 0600); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "2022-05")
+
+	t.Run("single key is active", func(t *testing.T) {
+		keys, err := LoadKeySet(dir, "")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if keys.Active().ID != "2022-05" {
+			t.Errorf("got active key %s, want 2022-05", keys.Active().ID)
+		}
+	})
+
+	writeKey(t, dir, "2022-06")
+
+	t.Run("several keys without active key", func(t *testing.T) {
+		if _, err := LoadKeySet(dir, ""); err == nil {
+			t.Errorf("got nil, want error")
+		}
+	})
+	t.Run("several keys with active key", func(t *testing.T) {
+		keys, err := LoadKeySet(dir, "2022-06")
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if keys.Active().ID != "2022-06" {
+			t.Errorf("got active key %s, want 2022-06", keys.Active().ID)
+		}
+		jwks := keys.JWKS()
+		if len(jwks) != 2 || jwks[0].KeyID != "2022-05" || jwks[1].KeyID != "2022-06" {
+			t.Errorf("got %v, want the public keys of 2022-05 and 2022-06", jwks)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// HeaderTenant is the header carrying the slug of the tenant a request acts on.
+const HeaderTenant = "X-Tenant"
+
+// ResolveTenantConfig defines the config for the ResolveTenant middleware.
+type ResolveTenantConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper middleware.Skipper
+	// Tenants resolves tenant slugs.
+	Tenants rental.TenantService
+	// BaseDomain is the domain under which tenants are served by subdomain,
+	// eg. requests to lyon.rental.example.com act on the tenant lyon when
+	// BaseDomain is rental.example.com. Subdomains are ignored when empty.
+	BaseDomain string
+}
+
+// ResolveTenant returns a middleware scoping the context of authenticated
+// requests to a tenant. The tenant is the one whose slug is in the X-Tenant
+// header, or the subdomain of the request's host, defaulting to the tenant of
+// the principal. Principals may only act on another tenant than their own
+// with the tenants:admin scope.
+//
+// The middleware must be installed after Authenticate. Requests without a
+// principal, to public operations, aren't scoped to a tenant.
+func ResolveTenant(config ResolveTenantConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			principal, ok := PrincipalFrom(c)
+			if !ok {
+				return next(c)
+			}
+
+			req := c.Request()
+			tenantID := principal.TenantID
+			if slug := requestedTenant(req, config.BaseDomain); slug != "" {
+				tenant, err := config.Tenants.GetBySlug(req.Context(), slug)
+				if err == rental.ErrTenantNotFound {
+					return echo.NewHTTPError(http.StatusNotFound, err.Error())
+				}
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+				}
+				tenantID = tenant.ID
+			}
+			if tenantID != principal.TenantID && !principal.HasScope(rental.ScopeTenantsAdmin) {
+				return echo.NewHTTPError(http.StatusForbidden, "Insufficient permissions")
+			}
+
+			c.SetRequest(req.WithContext(rental.WithTenant(req.Context(), tenantID)))
+			return next(c)
+		}
+	}
+}
+
+// requestedTenant returns the slug of the tenant requested by the X-Tenant
+// header or the subdomain of baseDomain, empty if none.
+func requestedTenant(req *http.Request, baseDomain string) string {
+	if slug := req.Header.Get(HeaderTenant); slug != "" {
+		return slug
+	}
+	if baseDomain == "" {
+		return ""
+	}
+	host := strings.ToLower(req.Host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	subdomain := strings.TrimSuffix(host, "."+strings.ToLower(baseDomain))
+	if subdomain == host || strings.Contains(subdomain, ".") {
+		return ""
+	}
+	return subdomain
+}
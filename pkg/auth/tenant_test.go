@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestResolveTenant(t *testing.T) {
+	tenants := mock.NewMockTenantService()
+	defaultTenantID, _ := tenants.Create(context.Background(), rental.Tenant{Slug: "default", Name: "Default"})
+	lyonTenantID, _ := tenants.Create(context.Background(), rental.Tenant{Slug: "lyon", Name: "Rental Lyon"})
+
+	tests := []struct {
+		name         string
+		anonymous    bool
+		role         rental.Role
+		host         string
+		header       string
+		wantCode     int
+		wantTenantID int
+	}{
+		{name: "tenant of the principal", role: rental.RoleAdmin, wantCode: http.StatusOK, wantTenantID: defaultTenantID},
+		{name: "own tenant in header", role: rental.RoleAdmin, header: "default", wantCode: http.StatusOK, wantTenantID: defaultTenantID},
+		{name: "other tenant in header", role: rental.RoleAdmin, header: "lyon", wantCode: http.StatusForbidden},
+		{name: "other tenant in subdomain", role: rental.RoleAdmin, host: "lyon.rental.example.com", wantCode: http.StatusForbidden},
+		{name: "operator acting on another tenant in header", role: rental.RoleOperator, header: "lyon", wantCode: http.StatusOK, wantTenantID: lyonTenantID},
+		{name: "operator acting on another tenant in subdomain", role: rental.RoleOperator, host: "lyon.rental.example.com:9090", wantCode: http.StatusOK, wantTenantID: lyonTenantID},
+		{name: "unknown tenant", role: rental.RoleOperator, header: "paris", wantCode: http.StatusNotFound},
+		{name: "base domain", role: rental.RoleAdmin, host: "rental.example.com", wantCode: http.StatusOK, wantTenantID: defaultTenantID},
+		{name: "anonymous client", anonymous: true, wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			e := echo.New()
+			setPrincipal := func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					if !tt.anonymous {
+						SetPrincipal(c, Principal{Subject: "jane", Role: tt.role, Scopes: tt.role.Scopes(), TenantID: defaultTenantID})
+					}
+					return next(c)
+				}
+			}
+			var gotTenantID int
+			e.GET("/v1/car/:carId", func(c echo.Context) error {
+				gotTenantID, _ = rental.TenantFrom(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			}, setPrincipal, ResolveTenant(ResolveTenantConfig{Tenants: tenants, BaseDomain: "rental.example.com"}))
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/car/1", nil)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.header != "" {
+				req.Header.Set(HeaderTenant, tt.header)
+			}
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			e.ServeHTTP(resp, req)
+			if resp.Code != tt.wantCode {
+				t.Errorf("got %d status code, want %d", resp.Code, tt.wantCode)
+			}
+			if gotTenantID != tt.wantTenantID {
+				t.Errorf("got tenant %d, want %d", gotTenantID, tt.wantTenantID)
+			}
+		})
+	}
+}
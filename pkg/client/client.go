@@ -0,0 +1,326 @@
+// Package client is the Go client of version 1 of the rental API, for the
+// services calling it. It wraps the client generated from the API spec in
+// pkg/api/gen, converting the entities of the API to the entities of the
+// domain, and the error responses to the errors of the domain.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+// contentTypeMergePatch is the content type of JSON Merge Patches.
+const contentTypeMergePatch = "application/merge-patch+json"
+
+// Client calls version 1 of the rental API. Changes are based on the version
+// of the entities they are given, as read by the client, and fail with the
+// version mismatch error of the entity, eg. rental.ErrCarVersionMismatch, if
+// the entity was modified in the meantime.
+type Client struct {
+	api *gen.ClientWithResponses
+}
+
+// config is the configuration of a Client, set by options.
+type config struct {
+	doer       gen.HttpRequestDoer
+	editors    []gen.RequestEditorFn
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// Option configures a Client.
+type Option func(*config)
+
+// WithHTTPClient sends the requests with doer, http.DefaultClient by default.
+func WithHTTPClient(doer gen.HttpRequestDoer) Option {
+	return func(c *config) {
+		c.doer = doer
+	}
+}
+
+// WithBasicAuth authenticates the requests as the user username.
+func WithBasicAuth(username, password string) Option {
+	return withEditor(func(ctx context.Context, req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// WithBearerToken authenticates the requests with the access token token,
+// issued by the API.
+func WithBearerToken(token string) Option {
+	return withEditor(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// WithAPIKey authenticates the requests with the API key key.
+func WithAPIKey(key string) Option {
+	return withEditor(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set(auth.HeaderAPIKey, key)
+		return nil
+	})
+}
+
+// WithTenant acts on the tenant slug, on behalf of the tenant of the
+// credentials.
+func WithTenant(slug string) Option {
+	return withEditor(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set(auth.HeaderTenant, slug)
+		return nil
+	})
+}
+
+// WithRetries retries the requests failing with a server error, a 429 Too
+// Many Requests or a network error up to maxRetries times, after a random
+// delay up to backoff, doubled at each retry, and at most maxBackoff.
+// Requests are retried 3 times after up to 100ms by default, and at most 2s.
+func WithRetries(maxRetries int, backoff, maxBackoff time.Duration) Option {
+	return func(c *config) {
+		c.maxRetries, c.backoff, c.maxBackoff = maxRetries, backoff, maxBackoff
+	}
+}
+
+// withEditor edits the requests with editor before sending them.
+func withEditor(editor gen.RequestEditorFn) Option {
+	return func(c *config) {
+		c.editors = append(c.editors, editor)
+	}
+}
+
+// New returns a client of the API at server, eg. https://rental.mmess.dev/v1.
+func New(server string, opts ...Option) (*Client, error) {
+	c := config{
+		doer:       http.DefaultClient,
+		maxRetries: 3,
+		backoff:    100 * time.Millisecond,
+		maxBackoff: 2 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	doer := &retryDoer{doer: c.doer, maxRetries: c.maxRetries, backoff: c.backoff, maxBackoff: c.maxBackoff}
+	clientOpts := []gen.ClientOption{gen.WithHTTPClient(doer)}
+	for _, editor := range c.editors {
+		clientOpts = append(clientOpts, gen.WithRequestEditorFn(editor))
+	}
+	api, err := gen.NewClientWithResponses(server, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{api: api}, nil
+}
+
+// etag returns the entity tag of a version of an entity.
+func etag(version int) *string {
+	tag := `"` + strconv.Itoa(version) + `"`
+	return &tag
+}
+
+// versionOf returns the version of the entity of resp, from its ETag.
+func versionOf(resp *http.Response) int {
+	version, _ := strconv.Atoi(strings.Trim(resp.Header.Get("ETag"), `"`))
+	return version
+}
+
+// mergePatch returns the JSON encoding of patch.
+func mergePatch(patch interface{}) (*bytes.Reader, error) {
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(body), nil
+}
+
+// toCar converts a car of the API to a rental.Car of version version.
+func toCar(car gen.Car, version int) rental.Car {
+	var renterID null.Int
+	if car.RenterId != 0 {
+		renterID = null.IntFrom(int64(car.RenterId))
+	}
+	return rental.Car{
+		ID:         int(car.Id),
+		CustomerID: renterID,
+		Make:       car.Make,
+		Model:      car.Model,
+		Year:       car.Year,
+		Version:    version,
+	}
+}
+
+// toCustomer converts a customer of the API to a rental.Customer of version version.
+func toCustomer(customer gen.Customer, version int) rental.Customer {
+	return rental.Customer{ID: int(customer.Id), Name: customer.Name, Version: version}
+}
+
+// GetCar fetches the car id.
+func (c *Client) GetCar(ctx context.Context, id int) (rental.Car, error) {
+	resp, err := c.api.GetCarByIdWithResponse(ctx, int64(id))
+	if err != nil {
+		return rental.Car{}, err
+	}
+	if err := check(resp.HTTPResponse, resp.Body, http.StatusOK, carErrors); err != nil {
+		return rental.Car{}, err
+	}
+	return toCar(*resp.JSON200, versionOf(resp.HTTPResponse)), nil
+}
+
+// CreateCar creates a car and returns it.
+func (c *Client) CreateCar(ctx context.Context, car rental.Car) (rental.Car, error) {
+	resp, err := c.api.CreateCarWithResponse(ctx, gen.CreateUpdateCarRequest{Make: car.Make, Model: car.Model, Year: car.Year})
+	if err != nil {
+		return rental.Car{}, err
+	}
+	if err := check(resp.HTTPResponse, resp.Body, http.StatusCreated, carErrors); err != nil {
+		return rental.Car{}, err
+	}
+	return toCar(*resp.JSON201, 0), nil
+}
+
+// UpdateCar updates the details of car, and returns it.
+func (c *Client) UpdateCar(ctx context.Context, car rental.Car) (rental.Car, error) {
+	body := gen.Car{Id: int64(car.ID), Make: car.Make, Model: car.Model, Year: car.Year, RenterId: car.RenterID()}
+	resp, err := c.api.UpdateCarWithResponse(ctx, int64(car.ID), &gen.UpdateCarParams{IfMatch: etag(car.Version)}, body)
+	if err != nil {
+		return rental.Car{}, err
+	}
+	if err := check(resp.HTTPResponse, resp.Body, http.StatusOK, carErrors); err != nil {
+		return rental.Car{}, err
+	}
+	return toCar(*resp.JSON200, versionOf(resp.HTTPResponse)), nil
+}
+
+// PatchCar applies patch to the version version of the car id, and returns it.
+func (c *Client) PatchCar(ctx context.Context, id int, version int, patch rental.CarPatch) (rental.Car, error) {
+	body, err := mergePatch(gen.CarPatch{Make: patch.Make, Model: patch.Model, Year: patch.Year})
+	if err != nil {
+		return rental.Car{}, err
+	}
+	resp, err := c.api.PatchCarWithBodyWithResponse(ctx, int64(id), &gen.PatchCarParams{IfMatch: etag(version)}, contentTypeMergePatch, body)
+	if err != nil {
+		return rental.Car{}, err
+	}
+	if err := check(resp.HTTPResponse, resp.Body, http.StatusOK, carErrors); err != nil {
+		return rental.Car{}, err
+	}
+	return toCar(*resp.JSON200, versionOf(resp.HTTPResponse)), nil
+}
+
+// DeleteCar deletes the version version of the car id.
+func (c *Client) DeleteCar(ctx context.Context, id int, version int) error {
+	resp, err := c.api.DeleteCarWithResponse(ctx, int64(id), &gen.DeleteCarParams{IfMatch: etag(version)})
+	if err != nil {
+		return err
+	}
+	return check(resp.HTTPResponse, resp.Body, http.StatusNoContent, carErrors)
+}
+
+// RentCar rents the car carID to the customer customerID.
+func (c *Client) RentCar(ctx context.Context, carID int, customerID int) error {
+	customer := int64(customerID)
+	resp, err := c.api.RentCarWithResponse(ctx, int64(carID), &gen.RentCarParams{CustomerId: &customer})
+	if err != nil {
+		return err
+	}
+	return check(resp.HTTPResponse, resp.Body, http.StatusNoContent, carErrors)
+}
+
+// ReturnCar returns the car carID.
+func (c *Client) ReturnCar(ctx context.Context, carID int) error {
+	resp, err := c.api.ReturnCarWithResponse(ctx, int64(carID))
+	if err != nil {
+		return err
+	}
+	return check(resp.HTTPResponse, resp.Body, http.StatusNoContent, carErrors)
+}
+
+// GetCustomer fetches the customer id.
+func (c *Client) GetCustomer(ctx context.Context, id int) (rental.Customer, error) {
+	resp, err := c.api.GetCustomerByIdWithResponse(ctx, int64(id))
+	if err != nil {
+		return rental.Customer{}, err
+	}
+	if err := check(resp.HTTPResponse, resp.Body, http.StatusOK, customerErrors); err != nil {
+		return rental.Customer{}, err
+	}
+	return toCustomer(*resp.JSON200, versionOf(resp.HTTPResponse)), nil
+}
+
+// CreateCustomer creates a customer and returns it.
+func (c *Client) CreateCustomer(ctx context.Context, customer rental.Customer) (rental.Customer, error) {
+	resp, err := c.api.CreateCustomerWithResponse(ctx, gen.CreateUpdateCustomerRequest{Name: customer.Name})
+	if err != nil {
+		return rental.Customer{}, err
+	}
+	if err := check(resp.HTTPResponse, resp.Body, http.StatusCreated, customerErrors); err != nil {
+		return rental.Customer{}, err
+	}
+	return toCustomer(*resp.JSON201, 0), nil
+}
+
+// UpdateCustomer updates the details of customer, and returns it.
+func (c *Client) UpdateCustomer(ctx context.Context, customer rental.Customer) (rental.Customer, error) {
+	body := gen.CreateUpdateCustomerRequest{Name: customer.Name}
+	resp, err := c.api.UpdateCustomerWithResponse(ctx, int64(customer.ID), &gen.UpdateCustomerParams{IfMatch: etag(customer.Version)}, body)
+	if err != nil {
+		return rental.Customer{}, err
+	}
+	if err := check(resp.HTTPResponse, resp.Body, http.StatusOK, customerErrors); err != nil {
+		return rental.Customer{}, err
+	}
+	return toCustomer(*resp.JSON200, versionOf(resp.HTTPResponse)), nil
+}
+
+// PatchCustomer applies patch to the version version of the customer id, and returns it.
+func (c *Client) PatchCustomer(ctx context.Context, id int, version int, patch rental.CustomerPatch) (rental.Customer, error) {
+	body, err := mergePatch(gen.CustomerPatch{Name: patch.Name})
+	if err != nil {
+		return rental.Customer{}, err
+	}
+	resp, err := c.api.PatchCustomerWithBodyWithResponse(ctx, int64(id), &gen.PatchCustomerParams{IfMatch: etag(version)}, contentTypeMergePatch, body)
+	if err != nil {
+		return rental.Customer{}, err
+	}
+	if err := check(resp.HTTPResponse, resp.Body, http.StatusOK, customerErrors); err != nil {
+		return rental.Customer{}, err
+	}
+	return toCustomer(*resp.JSON200, versionOf(resp.HTTPResponse)), nil
+}
+
+// DeleteCustomer deletes the version version of the customer id.
+func (c *Client) DeleteCustomer(ctx context.Context, id int, version int) error {
+	resp, err := c.api.DeleteCustomerWithResponse(ctx, int64(id), &gen.DeleteCustomerParams{IfMatch: etag(version)})
+	if err != nil {
+		return err
+	}
+	return check(resp.HTTPResponse, resp.Body, http.StatusNoContent, customerErrors)
+}
+
+// ListCustomerRentals returns the cars rented by the customer id, without
+// their version.
+func (c *Client) ListCustomerRentals(ctx context.Context, id int) ([]rental.Car, error) {
+	resp, err := c.api.ListCustomerRentalsWithResponse(ctx, int64(id))
+	if err != nil {
+		return nil, err
+	}
+	if err := check(resp.HTTPResponse, resp.Body, http.StatusOK, customerErrors); err != nil {
+		return nil, err
+	}
+	cars := make([]rental.Car, len(*resp.JSON200))
+	for i, car := range *resp.JSON200 {
+		cars[i] = toCar(car, 0)
+	}
+	return cars, nil
+}
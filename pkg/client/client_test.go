@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/api"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/idempotency"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"github.com/shidenkai0/rental/pkg/versioning"
+)
+
+// flakyHandler fails the first failures requests with status, and records the
+// idempotency keys of the requests it serves.
+type flakyHandler struct {
+	handler  http.Handler
+	status   int
+	failures int
+
+	mu   sync.Mutex
+	keys []string
+}
+
+func (h *flakyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.keys = append(h.keys, r.Header.Get(idempotency.HeaderIdempotencyKey))
+	fail := len(h.keys) <= h.failures
+	h.mu.Unlock()
+	if fail {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(h.status)
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// setupAPI serves the API over mock services, with an operator user
+// support:hunter22, the car 1 and the customer 1 in the default tenant,
+// behind a flakyHandler.
+func setupAPI(t *testing.T) (*httptest.Server, *flakyHandler) {
+	ctx := rental.WithTenant(context.Background(), rental.DefaultTenantID)
+	users := mock.NewMockUserCRUDService()
+	operator := rental.User{Username: "support", Role: rental.RoleOperator}
+	if err := operator.SetPassword("hunter22"); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	users.Create(ctx, operator)
+	cars := mock.NewMockCarCRUDService()
+	cars.Create(ctx, rental.Car{ID: 1, Make: "Renault", Model: "Clio", Year: 2020})
+	customers := mock.NewMockCustomerCRUDService()
+	customers.Create(ctx, rental.Customer{ID: 1, Name: "Ada"})
+	server := api.NewServer(cars, customers, mock.NewMockRentalService(), users, mock.NewMockAPIKeyService(), mock.NewMockAuditLogService(), mock.NewMockTenantService(), mock.NewMockTransactor())
+
+	versions, err := api.Versions(server, time.Time{})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	e := echo.New()
+	versions.Mount(e, func(v versioning.Version) []echo.MiddlewareFunc {
+		return []echo.MiddlewareFunc{
+			auth.AuthenticateWithConfig(auth.AuthenticateConfig{
+				Skipper:        auth.PublicSkipper(v.Spec, v.BaseURL()),
+				Authenticators: []auth.Authenticator{auth.NewBasicAuthenticator(users)},
+			}),
+			auth.ResolveTenant(auth.ResolveTenantConfig{Tenants: server.TenantService}),
+			auth.Authorize(v.Spec, v.BaseURL()),
+		}
+	})
+	handler := &flakyHandler{handler: e}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts, handler
+}
+
+func TestClient(t *testing.T) {
+	// Setup
+
+	ts, _ := setupAPI(t)
+	c, err := New(ts.URL+"/v1", WithBasicAuth("support", "hunter22"))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	ctx := context.Background()
+
+	// Test
+
+	car, err := c.GetCar(ctx, 1)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	year := 2021
+	car, err = c.PatchCar(ctx, car.ID, car.Version, rental.CarPatch{Year: &year})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if car.Year != 2021 || car.Version != 1 {
+		t.Errorf("got car %+v, want year 2021 at version 1", car)
+	}
+	if _, err := c.PatchCar(ctx, car.ID, 0, rental.CarPatch{Year: &year}); !errors.Is(err, rental.ErrCarVersionMismatch) {
+		t.Errorf("got error %v patching a stale car, want %v", err, rental.ErrCarVersionMismatch)
+	}
+
+	if err := c.RentCar(ctx, 1, 1); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if err := c.RentCar(ctx, 1, 1); !errors.Is(err, rental.ErrCarAlreadyRented) {
+		t.Errorf("got error %v renting a rented car, want %v", err, rental.ErrCarAlreadyRented)
+	}
+	cars, err := c.ListCustomerRentals(ctx, 1)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if len(cars) != 1 || cars[0].RenterID() != 1 {
+		t.Errorf("got rentals %+v, want car 1 rented by customer 1", cars)
+	}
+	if err := c.ReturnCar(ctx, 1); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if err := c.ReturnCar(ctx, 1); !errors.Is(err, rental.ErrCarNotRented) {
+		t.Errorf("got error %v returning a returned car, want %v", err, rental.ErrCarNotRented)
+	}
+
+	if _, err := c.GetCar(ctx, 2); !errors.Is(err, rental.ErrCarNotFound) {
+		t.Errorf("got error %v, want %v", err, rental.ErrCarNotFound)
+	}
+	if _, err := c.GetCustomer(ctx, 2); !errors.Is(err, rental.ErrCustomerNotFound) {
+		t.Errorf("got error %v, want %v", err, rental.ErrCustomerNotFound)
+	}
+	var apiErr *Error
+	if err := c.DeleteCustomer(ctx, 2, 0); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("got error %v, want 404 error", err)
+	}
+}
+
+func TestClient_Unauthorized(t *testing.T) {
+	ts, _ := setupAPI(t)
+	c, err := New(ts.URL+"/v1", WithBasicAuth("support", "wrong"))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	if _, err := c.GetCar(context.Background(), 1); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("got error %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		failures   int
+		maxRetries int
+		wantErr    error
+	}{
+		{name: "Server error", status: http.StatusServiceUnavailable, failures: 2, maxRetries: 2},
+		{name: "Rate limited", status: http.StatusTooManyRequests, failures: 1, maxRetries: 2},
+		{name: "Retries exhausted", status: http.StatusTooManyRequests, failures: 3, maxRetries: 2, wantErr: ErrRateLimited},
+		{name: "Not retried", status: http.StatusServiceUnavailable, failures: 1, maxRetries: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, handler := setupAPI(t)
+			handler.status, handler.failures = tt.status, tt.failures
+			c, err := New(ts.URL+"/v1", WithBasicAuth("support", "hunter22"), WithRetries(tt.maxRetries, time.Millisecond, 10*time.Millisecond))
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+
+			_, err = c.CreateCustomer(context.Background(), rental.Customer{Name: "Grace"})
+			if tt.maxRetries == 0 {
+				var apiErr *Error
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+					t.Errorf("got error %v, want %d error", err, tt.status)
+				}
+			} else if !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if want := min(tt.failures, tt.maxRetries) + 1; len(handler.keys) != want {
+				t.Errorf("got %d attempts, want %d", len(handler.keys), want)
+			}
+			for _, key := range handler.keys {
+				if key == "" || key != handler.keys[0] {
+					t.Errorf("got idempotency keys %q, want the same key for each attempt", handler.keys)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestClient_ContextCanceled(t *testing.T) {
+	ts, handler := setupAPI(t)
+	handler.status, handler.failures = http.StatusServiceUnavailable, 10
+	c, err := New(ts.URL+"/v1", WithBasicAuth("support", "hunter22"), WithRetries(10, time.Hour, time.Hour))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.GetCar(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+var (
+	ErrUnauthorized = fmt.Errorf("Unauthorized")
+	ErrForbidden    = fmt.Errorf("Insufficient permissions")
+	ErrRateLimited  = fmt.Errorf("Too many requests")
+)
+
+// sentinels are the errors of the domain reported by the API, whose
+// messages are the messages of the error responses.
+var sentinels = []error{
+	rental.ErrCarNotFound,
+	rental.ErrCarNotRented,
+	rental.ErrCarAlreadyRented,
+	rental.ErrCarAlreadyExists,
+	rental.ErrCarVersionMismatch,
+	rental.ErrCarHasRentals,
+	rental.ErrCarMakeEmpty,
+	rental.ErrCarModelEmpty,
+	rental.ErrInvalidCarYear,
+	rental.ErrCustomerNotFound,
+	rental.ErrCustomerAlreadyExists,
+	rental.ErrCustomerVersionMismatch,
+	rental.ErrCustomerHasRentals,
+	rental.ErrCustomerNameEmpty,
+	rental.ErrRentalNotFound,
+	rental.ErrRentalAlreadyReturned,
+}
+
+// entityErrors are the errors of an entity the API reports by status only.
+type entityErrors struct {
+	notFound        error
+	versionMismatch error
+}
+
+var (
+	carErrors      = entityErrors{notFound: rental.ErrCarNotFound, versionMismatch: rental.ErrCarVersionMismatch}
+	customerErrors = entityErrors{notFound: rental.ErrCustomerNotFound, versionMismatch: rental.ErrCustomerVersionMismatch}
+)
+
+// Error is an error response of the API. It wraps the error of the domain
+// it reports, eg. rental.ErrCarNotFound, or ErrUnauthorized, ErrForbidden or
+// ErrRateLimited, so that callers can match it with errors.Is.
+type Error struct {
+	StatusCode int
+	Message    string
+	err        error
+}
+
+func (err *Error) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("%d %s", err.StatusCode, http.StatusText(err.StatusCode))
+	}
+	return fmt.Sprintf("%d %s: %s", err.StatusCode, http.StatusText(err.StatusCode), err.Message)
+}
+
+func (err *Error) Unwrap() error {
+	return err.err
+}
+
+// check returns nil if resp has the status want, the *Error of its body
+// otherwise. The errors of the entity the request acts on are reported by
+// status when the message doesn't match a domain error.
+func check(resp *http.Response, body []byte, want int, entity entityErrors) error {
+	if resp.StatusCode == want {
+		return nil
+	}
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	var errorResponse gen.ErrorResponse
+	if json.Unmarshal(body, &errorResponse) == nil {
+		apiErr.Message = errorResponse.Message
+	}
+	for _, sentinel := range sentinels {
+		if apiErr.Message == sentinel.Error() {
+			apiErr.err = sentinel
+			return apiErr
+		}
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		apiErr.err = entity.notFound
+	case http.StatusPreconditionFailed:
+		apiErr.err = entity.versionMismatch
+	case http.StatusUnauthorized:
+		apiErr.err = ErrUnauthorized
+	case http.StatusForbidden:
+		apiErr.err = ErrForbidden
+	case http.StatusTooManyRequests:
+		apiErr.err = ErrRateLimited
+	}
+	return apiErr
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	mathrand "math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/idempotency"
+)
+
+// retryDoer sends requests with doer, retrying the requests failing with a
+// server error, a 429 Too Many Requests or a network error.
+type retryDoer struct {
+	doer       gen.HttpRequestDoer
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// Do sends req, retrying it up to maxRetries times, after a random delay up
+// to backoff doubled at each retry and at most maxBackoff, or after the
+// Retry-After delay of a 429 response. Requests are sent with an
+// Idempotency-Key, so that the API doesn't apply retried changes twice.
+func (d *retryDoer) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get(idempotency.HeaderIdempotencyKey) == "" {
+		req.Header.Set(idempotency.HeaderIdempotencyKey, newIdempotencyKey())
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := d.doer.Do(req)
+		if attempt == d.maxRetries || req.Context().Err() != nil || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		delay, retry := d.delay(attempt, resp, err)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain the body, so that the connection is reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// delay returns how long to wait before retrying the attempt, which failed
+// with resp or err, and whether to retry it.
+func (d *retryDoer) delay(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if err == nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil {
+			retryAfter := time.Duration(seconds) * time.Second
+			// Waiting longer than maxBackoff would stall the caller
+			return retryAfter, retryAfter <= d.maxBackoff
+		}
+	}
+	// Full jitter, so that the clients failing together don't retry together
+	ceiling := d.backoff << attempt
+	if ceiling > d.maxBackoff || ceiling <= 0 {
+		ceiling = d.maxBackoff
+	}
+	return time.Duration(mathrand.Int63n(int64(ceiling) + 1)), true
+}
+
+// newIdempotencyKey returns a random idempotency key.
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,98 @@
+// Package database implements the database layer of the rental service.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// DatabaseAPIKeyService is a concrete implementation of the APIKeyService
+// interface using Postgres as a backend.
+type DatabaseAPIKeyService struct {
+	db *sqlx.DB
+}
+
+// Create stores an API key in the database, returns id.
+func (s *DatabaseAPIKeyService) Create(ctx context.Context, key rental.APIKey) (id int, err error) {
+	key.TenantID, err = tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	insertStatement := "INSERT INTO api_keys (tenant_id, name, prefix, key_hash, scope, created_by, expires_at) VALUES (:tenant_id, :name, :prefix, :key_hash, :scope, :created_by, :expires_at) RETURNING id"
+	rows, err := sqlx.NamedQueryContext(ctx, s.db, insertStatement, key)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		err = rows.Scan(&id)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return id, err
+}
+
+// Get fetches an API key from the database.
+func (s *DatabaseAPIKeyService) Get(ctx context.Context, id int) (rental.APIKey, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return rental.APIKey{}, err
+	}
+	var key rental.APIKey
+	err = sqlx.GetContext(ctx, s.db, &key, "SELECT * FROM api_keys WHERE id = $1 AND tenant_id = $2", id, tenantID)
+	if err == sql.ErrNoRows {
+		return rental.APIKey{}, rental.ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+// GetByHash fetches an API key from the database by hash, whatever its tenant.
+func (s *DatabaseAPIKeyService) GetByHash(ctx context.Context, keyHash string) (rental.APIKey, error) {
+	var key rental.APIKey
+	err := sqlx.GetContext(ctx, s.db, &key, "SELECT * FROM api_keys WHERE key_hash = $1 LIMIT 1", keyHash)
+	if err == sql.ErrNoRows {
+		return rental.APIKey{}, rental.ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+// List fetches all API keys from the database.
+func (s *DatabaseAPIKeyService) List(ctx context.Context) ([]rental.APIKey, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := []rental.APIKey{}
+	err = sqlx.SelectContext(ctx, s.db, &keys, "SELECT * FROM api_keys WHERE tenant_id = $1 ORDER BY id", tenantID)
+	return keys, err
+}
+
+// Revoke revokes an API key.
+func (s *DatabaseAPIKeyService) Revoke(ctx context.Context, id int) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL", id, tenantID)
+	return err
+}
+
+// Touch records the last use of an API key.
+func (s *DatabaseAPIKeyService) Touch(ctx context.Context, id int, usedAt time.Time) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = $3 WHERE id = $1 AND tenant_id = $2 AND (last_used_at IS NULL OR last_used_at < $3)", id, tenantID, usedAt)
+	return err
+}
+
+// NewDatabaseAPIKeyService returns a new DatabaseAPIKeyService with the provided database as SQL backend.
+func NewDatabaseAPIKeyService(db *sqlx.DB) *DatabaseAPIKeyService {
+	return &DatabaseAPIKeyService{db: db}
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestDatabaseAPIKeyService_GetByHash(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	apiKeyService := NewDatabaseAPIKeyService(sqlx.MustConnect("postgres", testDatabaseURL))
+	key, apiKey, err := rental.NewAPIKey("billing", []rental.Scope{rental.ScopeCarsRead}, null.Time{})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	apiKey.CreatedBy = "rental"
+	id, err := apiKeyService.Create(testCtx, apiKey)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	t.Run("get an API key", func(t *testing.T) {
+		got, err := apiKeyService.GetByHash(testCtx, rental.HashAPIKey(key))
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got.ID != id || got.Name != apiKey.Name || got.Scope != apiKey.Scope || !got.Valid(time.Now()) {
+			t.Errorf("got %v, want valid API key %d", got, id)
+		}
+	})
+	t.Run("get a non-existent API key", func(t *testing.T) {
+		_, err := apiKeyService.GetByHash(testCtx, rental.HashAPIKey("rk_unknown"))
+		if err != rental.ErrAPIKeyNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrAPIKeyNotFound)
+		}
+	})
+}
+
+func TestDatabaseAPIKeyService_RevokeTouch(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	apiKeyService := NewDatabaseAPIKeyService(sqlx.MustConnect("postgres", testDatabaseURL))
+	_, apiKey, err := rental.NewAPIKey("billing", []rental.Scope{rental.ScopeCarsRead}, null.Time{})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	apiKey.CreatedBy = "rental"
+	id, err := apiKeyService.Create(testCtx, apiKey)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	if err := apiKeyService.Touch(testCtx, id, time.Now()); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if err := apiKeyService.Revoke(testCtx, id); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	got, err := apiKeyService.Get(testCtx, id)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if !got.LastUsedAt.Valid {
+		t.Errorf("got no last use, want last use recorded")
+	}
+	if got.Valid(time.Now()) {
+		t.Errorf("got valid API key, want revoked API key")
+	}
+}
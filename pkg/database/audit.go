@@ -0,0 +1,158 @@
+// Package database implements the database layer of the rental service.
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+const (
+	defaultAuditListLimit = 100
+	maxAuditListLimit     = 1000
+)
+
+// DatabaseAuditLogService is a concrete implementation of the AuditLogService
+// interface using Postgres as a backend.
+type DatabaseAuditLogService struct {
+	db *sqlx.DB
+}
+
+// auditEntryRow is the database representation of a rental.AuditEntry, JSON columns are
+// scanned as plain byte slices as json.RawMessage does not support NULL values.
+type auditEntryRow struct {
+	rental.AuditEntry
+	Before []byte `db:"before"`
+	After  []byte `db:"after"`
+	Diff   []byte `db:"diff"`
+}
+
+// nullableJSON returns a JSON value suitable as a query argument, nil values are stored as NULL.
+func nullableJSON(value json.RawMessage) interface{} {
+	if value == nil {
+		return nil
+	}
+	return []byte(value)
+}
+
+// copiedJSON returns a JSON value suitable as a COPY argument, which encodes
+// byte slices as bytea, nil values are stored as NULL.
+func copiedJSON(value json.RawMessage) interface{} {
+	if value == nil {
+		return nil
+	}
+	return string(value)
+}
+
+// auditEntryColumns are the columns of the audit_log table mapped to auditEntryRow.
+const auditEntryColumns = "id, actor, action, entity_type, entity_id, before, after, diff, created_at"
+
+// Record appends an entry to the audit log, returns id.
+func (s *DatabaseAuditLogService) Record(ctx context.Context, entry rental.AuditEntry) (id int, err error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	insertStatement := `INSERT INTO audit_log (tenant_id, actor, action, entity_type, entity_id, before, after, diff)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	err = s.db.QueryRowContext(ctx, insertStatement, tenantID,
+		entry.Actor, entry.Action, entry.EntityType, entry.EntityID,
+		nullableJSON(entry.Before), nullableJSON(entry.After), nullableJSON(entry.Diff),
+	).Scan(&id)
+	return id, err
+}
+
+// RecordMany appends entries to the audit log in a single transaction,
+// copying them rather than inserting them one by one.
+func (s *DatabaseAuditLogService) RecordMany(ctx context.Context, entries []rental.AuditEntry) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	return withinTransaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, pq.CopyIn("audit_log", "tenant_id", "actor", "action", "entity_type", "entity_id", "before", "after", "diff"))
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for _, entry := range entries {
+			if _, err := stmt.ExecContext(ctx, tenantID,
+				entry.Actor, entry.Action, entry.EntityType, entry.EntityID,
+				copiedJSON(entry.Before), copiedJSON(entry.After), copiedJSON(entry.Diff),
+			); err != nil {
+				return err
+			}
+		}
+		// Flush the copied rows
+		_, err = stmt.ExecContext(ctx)
+		return err
+	})
+}
+
+// List fetches the audit entries matching filter from the database, most recent first.
+func (s *DatabaseAuditLogService) List(ctx context.Context, filter rental.AuditFilter) ([]rental.AuditEntry, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	where("tenant_id = $%d", tenantID)
+	if filter.Actor != "" {
+		where("actor = $%d", filter.Actor)
+	}
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+	if filter.EntityType != "" {
+		where("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		where("entity_id = $%d", filter.EntityID)
+	}
+	if !filter.Since.IsZero() {
+		where("created_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		where("created_at < $%d", filter.Until)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditListLimit
+	}
+	if limit > maxAuditListLimit {
+		limit = maxAuditListLimit
+	}
+	args = append(args, limit)
+
+	query := "SELECT " + auditEntryColumns + " FROM audit_log WHERE " + strings.Join(conditions, " AND ")
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	var rows []auditEntryRow
+	if err := sqlx.SelectContext(ctx, s.db, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	entries := make([]rental.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := row.AuditEntry
+		entry.Before, entry.After, entry.Diff = row.Before, row.After, row.Diff
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// NewDatabaseAuditLogService returns a new DatabaseAuditLogService with the provided database as SQL backend.
+func NewDatabaseAuditLogService(db *sqlx.DB) *DatabaseAuditLogService {
+	return &DatabaseAuditLogService{db: db}
+}
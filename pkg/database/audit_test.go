@@ -0,0 +1,103 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestDatabaseAuditLogService_Record(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	auditLogService := NewDatabaseAuditLogService(db)
+	entry, err := rental.NewAuditEntry("rental", rental.AuditActionCreate, rental.AuditEntityCar, 1, nil, rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	id, err := auditLogService.Record(testCtx, entry)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	got, err := auditLogService.List(testCtx, rental.AuditFilter{})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 1 || got[0].ID != id || got[0].Actor != entry.Actor || got[0].Before != nil || got[0].CreatedAt.IsZero() {
+		t.Errorf("got %v, want a single entry %d recorded by %s", got, id, entry.Actor)
+	}
+}
+
+func TestDatabaseAuditLogService_RecordMany(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	auditLogService := NewDatabaseAuditLogService(db)
+	var entries []rental.AuditEntry
+	for _, car := range []rental.Car{{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}, {ID: 2, Make: "Ford", Model: "Fiesta", Year: 2016}} {
+		entry, err := rental.NewAuditEntry("rental", rental.AuditActionCreate, rental.AuditEntityCar, car.ID, nil, car)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := auditLogService.RecordMany(testCtx, entries); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	got, err := auditLogService.List(testCtx, rental.AuditFilter{})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 2 || got[0].Before != nil || got[0].After == nil || got[0].Diff == nil {
+		t.Errorf("got %v, want 2 entries recording the cars created", got)
+	}
+}
+
+func TestDatabaseAuditLogService_List(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	auditLogService := NewDatabaseAuditLogService(db)
+	for _, entityID := range []int{1, 2, 2} {
+		entry, err := rental.NewAuditEntry("rental", rental.AuditActionUpdate, rental.AuditEntityCar, entityID, rental.Car{ID: entityID}, rental.Car{ID: entityID, Year: 2015})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if _, err := auditLogService.Record(testCtx, entry); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	}
+
+	t.Run("filter by entity", func(t *testing.T) {
+		got, err := auditLogService.List(testCtx, rental.AuditFilter{EntityType: rental.AuditEntityCar, EntityID: 2})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(got) != 2 {
+			t.Errorf("got %d entries, want 2", len(got))
+		}
+	})
+	t.Run("limit results", func(t *testing.T) {
+		got, err := auditLogService.List(testCtx, rental.AuditFilter{Limit: 1})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(got) != 1 || got[0].ID != 3 {
+			t.Errorf("got %v, want the most recent entry", got)
+		}
+	})
+	t.Run("entries can't be modified", func(t *testing.T) {
+		if _, err := db.Exec("UPDATE audit_log SET actor = 'someone else'"); err == nil {
+			t.Errorf("got nil, want error")
+		}
+		if _, err := db.Exec("DELETE FROM audit_log"); err == nil {
+			t.Errorf("got nil, want error")
+		}
+	})
+}
@@ -2,12 +2,17 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/shidenkai0/rental/pkg/rental"
 )
 
+// carColumns are the columns of the cars table mapped to rental.Car.
+const carColumns = "id, customer_id, make, model, year, version"
+
 // DatabaseCarCRUDService is a concrete implementation of the CarCRUDService
 // interface using Postgres as a backend.
 type DatabaseCarCRUDService struct {
@@ -15,43 +20,164 @@ type DatabaseCarCRUDService struct {
 }
 
 // Create creates a car in the database, returns id.
-func (s *DatabaseCarCRUDService) Create(car rental.Car) (id int, err error) {
-	insertStatement := "INSERT INTO cars (make, model, year) VALUES (:make, :model, :year) RETURNING id"
-	rows, err := sqlx.NamedQuery(s.db, insertStatement, car)
+func (s *DatabaseCarCRUDService) Create(ctx context.Context, car rental.Car) (id int, err error) {
+	tenantID, err := tenantID(ctx)
 	if err != nil {
 		return 0, err
 	}
-	defer rows.Close()
-	if rows.Next() {
-		err = rows.Scan(&id)
+	insertStatement := "INSERT INTO cars (tenant_id, make, model, year) VALUES ($1, $2, $3, $4) RETURNING id"
+	err = conn(ctx, s.db).QueryRowxContext(ctx, insertStatement, tenantID, car.Make, car.Model, car.Year).Scan(&id)
+	return id, err
+}
+
+// CreateMany creates cars in the database in a single transaction, copying
+// them rather than inserting them one by one, returns their ids in the order
+// of cars.
+func (s *DatabaseCarCRUDService) CreateMany(ctx context.Context, cars []rental.Car) ([]int, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	err = withinTransaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		// COPY doesn't return the ids of the rows, so they are allocated beforehand
+		idsStatement := "SELECT nextval(pg_get_serial_sequence('cars', 'id')) FROM generate_series(1, $1) ORDER BY 1"
+		if err := tx.SelectContext(ctx, &ids, idsStatement, len(cars)); err != nil {
+			return err
+		}
+		stmt, err := tx.PrepareContext(ctx, pq.CopyIn("cars", "id", "tenant_id", "make", "model", "year"))
 		if err != nil {
-			return 0, err
+			return err
+		}
+		defer stmt.Close()
+		for i, car := range cars {
+			if _, err := stmt.ExecContext(ctx, ids[i], tenantID, car.Make, car.Model, car.Year); err != nil {
+				return err
+			}
 		}
+		// Flush the copied rows
+		_, err = stmt.ExecContext(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return id, err
+	return ids, nil
 }
 
 // Get fetches a car from the database.
-func (s *DatabaseCarCRUDService) Get(id int) (rental.Car, error) {
+func (s *DatabaseCarCRUDService) Get(ctx context.Context, id int) (rental.Car, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return rental.Car{}, err
+	}
 	var car rental.Car
-	err := sqlx.Get(s.db, &car, "SELECT * FROM cars WHERE id = $1 LIMIT 1", id)
+	err = sqlx.GetContext(ctx, conn(ctx, s.db), &car, "SELECT "+carColumns+" FROM cars WHERE id = $1 AND tenant_id = $2", id, tenantID)
 	if err == sql.ErrNoRows {
 		return rental.Car{}, rental.ErrCarNotFound
 	}
 	return car, err
 }
 
-// Update updates a car in the database.
-func (s *DatabaseCarCRUDService) Update(car rental.Car) error {
-	updateStatement := "UPDATE cars SET make = :make, model = :model, year = :year, customer_id = :customer_id WHERE id = :id"
-	_, err := sqlx.NamedExec(s.db, updateStatement, car)
-	return err
+// GetMany fetches the cars of ids from the database, ordered by id. Missing cars are skipped.
+func (s *DatabaseCarCRUDService) GetMany(ctx context.Context, ids []int) ([]rental.Car, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cars := []rental.Car{}
+	err = sqlx.SelectContext(ctx, conn(ctx, s.db), &cars, "SELECT "+carColumns+" FROM cars WHERE id = ANY($1) AND tenant_id = $2 ORDER BY id", pq.Array(ids), tenantID)
+	return cars, err
+}
+
+// ForEach fetches the cars from the database one by one, calling fn with
+// each of them, so that the fleet isn't loaded in memory at once.
+func (s *DatabaseCarCRUDService) ForEach(ctx context.Context, fn func(car rental.Car) error) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := conn(ctx, s.db).QueryxContext(ctx, "SELECT "+carColumns+" FROM cars WHERE tenant_id = $1 ORDER BY id", tenantID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var car rental.Car
+		if err := rows.StructScan(&car); err != nil {
+			return err
+		}
+		if err := fn(car); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
-// Delete deletes a car from the database.
-func (s *DatabaseCarCRUDService) Delete(carID int) error {
-	_, err := s.db.Exec("DELETE FROM cars WHERE id = $1", carID)
-	return err
+// ListRentedBy fetches the cars rented by a customer from the database.
+func (s *DatabaseCarCRUDService) ListRentedBy(ctx context.Context, customerID int) ([]rental.Car, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cars := []rental.Car{}
+	err = sqlx.SelectContext(ctx, conn(ctx, s.db), &cars, "SELECT "+carColumns+" FROM cars WHERE customer_id = $1 AND tenant_id = $2 ORDER BY id", customerID, tenantID)
+	return cars, err
+}
+
+// Update updates a car in the database if it is still at car.Version, and increments its version.
+func (s *DatabaseCarCRUDService) Update(ctx context.Context, car rental.Car) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	updateStatement := `UPDATE cars SET make = $1, model = $2, year = $3, customer_id = $4, version = version + 1
+		WHERE id = $5 AND tenant_id = $6 AND version = $7`
+	res, err := conn(ctx, s.db).ExecContext(ctx, updateStatement, car.Make, car.Model, car.Year, car.CustomerID, car.ID, tenantID, car.Version)
+	if err != nil {
+		return err
+	}
+	return checkVersion(ctx, conn(ctx, s.db), res, "cars", car.ID, tenantID, rental.ErrCarVersionMismatch, rental.ErrCarNotFound)
+}
+
+// Patch updates the changed details of a car in the database if it is still at version, and increments its version.
+func (s *DatabaseCarCRUDService) Patch(ctx context.Context, carID int, version int, patch rental.CarPatch) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	set := newAssignments(carID, tenantID, version)
+	if patch.Make != nil {
+		set.add("make", *patch.Make)
+	}
+	if patch.Model != nil {
+		set.add("model", *patch.Model)
+	}
+	if patch.Year != nil {
+		set.add("year", *patch.Year)
+	}
+	updateStatement := "UPDATE cars SET " + set.clause("version = version + 1") + " WHERE id = $1 AND tenant_id = $2 AND version = $3"
+	res, err := conn(ctx, s.db).ExecContext(ctx, updateStatement, set.args...)
+	if err != nil {
+		return err
+	}
+	return checkVersion(ctx, conn(ctx, s.db), res, "cars", carID, tenantID, rental.ErrCarVersionMismatch, rental.ErrCarNotFound)
+}
+
+// Delete deletes a car from the database if it is still at version and has no rentals.
+func (s *DatabaseCarCRUDService) Delete(ctx context.Context, carID int, version int) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := conn(ctx, s.db).ExecContext(ctx, "DELETE FROM cars WHERE id = $1 AND tenant_id = $2 AND version = $3", carID, tenantID, version)
+	if isForeignKeyViolation(err) {
+		return rental.ErrCarHasRentals
+	}
+	if err != nil {
+		return err
+	}
+	return checkVersion(ctx, conn(ctx, s.db), res, "cars", carID, tenantID, rental.ErrCarVersionMismatch, rental.ErrCarNotFound)
 }
 
 // NewDatabaseCarCRUDService returns a new DatabaseCarCRUDService with the provided database as SQL backend.
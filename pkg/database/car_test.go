@@ -16,12 +16,12 @@ func TestDatabaseCarCRUDService_Get(t *testing.T) {
 	db := sqlx.MustConnect("postgres", testDatabaseURL)
 	t.Run("get a car", func(t *testing.T) {
 		carCRUDService := NewDatabaseCarCRUDService(db)
-		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
-		_, err := carCRUDService.Create(car)
+		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015, Version: 1}
+		_, err := carCRUDService.Create(testCtx, car)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
-		got, err := carCRUDService.Get(1)
+		got, err := carCRUDService.Get(testCtx, 1)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
@@ -31,7 +31,7 @@ func TestDatabaseCarCRUDService_Get(t *testing.T) {
 	})
 	t.Run("get a non-existent car", func(t *testing.T) {
 		carCRUDService := NewDatabaseCarCRUDService(db)
-		_, err := carCRUDService.Get(100)
+		_, err := carCRUDService.Get(testCtx, 100)
 		if err != rental.ErrCarNotFound {
 			t.Errorf(fmt.Sprintf("got error %v, want %v", err, rental.ErrCarNotFound))
 		}
@@ -44,13 +44,13 @@ func TestDatabaseCarCRUDService_Create(t *testing.T) {
 
 	db := sqlx.MustConnect("postgres", testDatabaseURL)
 	carCRUDService := NewDatabaseCarCRUDService(db)
-	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
-	_, err := carCRUDService.Create(car)
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015, Version: 1}
+	_, err := carCRUDService.Create(testCtx, car)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
-	got, err := carCRUDService.Get(1)
+	got, err := carCRUDService.Get(testCtx, 1)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
@@ -68,27 +68,28 @@ func TestDatabaseCarCRUDService_Update(t *testing.T) {
 	// Create test customers
 
 	customerCRUDService := NewDatabaseCustomerCRUDService(db)
-	testCustomer := rental.Customer{ID: 1, Name: "John Doe"}
+	testCustomer := rental.Customer{ID: 1, Name: "John Doe", Version: 1}
 
-	_, err := customerCRUDService.Create(testCustomer)
+	_, err := customerCRUDService.Create(testCtx, testCustomer)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
 	carCRUDService := NewDatabaseCarCRUDService(db)
-	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
-	_, err = carCRUDService.Create(car)
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015, Version: 1}
+	_, err = carCRUDService.Create(testCtx, car)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
-	car = rental.Car{ID: 1, Make: "Ford", CustomerID: null.IntFrom(int64(testCustomer.ID)), Model: "Fiesta", Year: 2016}
-	err = carCRUDService.Update(car)
+	car = rental.Car{ID: 1, Make: "Ford", CustomerID: null.IntFrom(int64(testCustomer.ID)), Model: "Fiesta", Year: 2016, Version: 1}
+	err = carCRUDService.Update(testCtx, car)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
+	car.Version++
 
-	got, err := carCRUDService.Get(1)
+	got, err := carCRUDService.Get(testCtx, 1)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
@@ -97,25 +98,181 @@ func TestDatabaseCarCRUDService_Update(t *testing.T) {
 	}
 }
 
+func TestDatabaseCarCRUDService_ListRentedBy(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+
+	customerCRUDService := NewDatabaseCustomerCRUDService(db)
+	testCustomer := rental.Customer{ID: 1, Name: "John Doe", Version: 1}
+	_, err := customerCRUDService.Create(testCtx, testCustomer)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	carCRUDService := NewDatabaseCarCRUDService(db)
+	rented := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015, Version: 1}
+	available := rental.Car{ID: 2, Make: "Ford", Model: "Fiesta", Year: 2016, Version: 1}
+	for _, car := range []rental.Car{rented, available} {
+		if _, err := carCRUDService.Create(testCtx, car); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	}
+	rented.CustomerID = null.IntFrom(int64(testCustomer.ID))
+	if err := carCRUDService.Update(testCtx, rented); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	rented.Version++
+
+	got, err := carCRUDService.ListRentedBy(testCtx, testCustomer.ID)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 1 || got[0] != rented {
+		t.Errorf("got %v, want [%v]", got, rented)
+	}
+}
+
 func TestDatabaseCarCRUDService_Delete(t *testing.T) {
 	setupTestDatabase()
 	defer teardownTestDatabase()
 
 	db := sqlx.MustConnect("postgres", testDatabaseURL)
 	carCRUDService := NewDatabaseCarCRUDService(db)
-	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
-	_, err := carCRUDService.Create(car)
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015, Version: 1}
+	_, err := carCRUDService.Create(testCtx, car)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
-	err = carCRUDService.Delete(1)
+	err = carCRUDService.Delete(testCtx, 1, 1)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
-	car, err = carCRUDService.Get(1)
+	car, err = carCRUDService.Get(testCtx, 1)
 	if (err != rental.ErrCarNotFound || car != rental.Car{}) {
 		t.Errorf(fmt.Sprintf("got error %v, want %v", err, rental.ErrCarNotFound))
 	}
 }
+
+func TestDatabaseCarCRUDService_Update_VersionMismatch(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	carCRUDService := NewDatabaseCarCRUDService(db)
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015, Version: 1}
+	if _, err := carCRUDService.Create(testCtx, car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if err := carCRUDService.Update(testCtx, car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	// car is now stale
+	car.Make = "Ford"
+	if err := carCRUDService.Update(testCtx, car); err != rental.ErrCarVersionMismatch {
+		t.Errorf("got error %v, want %v", err, rental.ErrCarVersionMismatch)
+	}
+	if err := carCRUDService.Delete(testCtx, car.ID, car.Version); err != rental.ErrCarVersionMismatch {
+		t.Errorf("got error %v, want %v", err, rental.ErrCarVersionMismatch)
+	}
+
+	// car is now deleted
+	if err := carCRUDService.Delete(testCtx, car.ID, car.Version+1); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if err := carCRUDService.Update(testCtx, car); err != rental.ErrCarNotFound {
+		t.Errorf("got error %v, want %v", err, rental.ErrCarNotFound)
+	}
+}
+
+func TestDatabaseCarCRUDService_Patch(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	carCRUDService := NewDatabaseCarCRUDService(db)
+	car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015, Version: 1}
+	if _, err := carCRUDService.Create(testCtx, car); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	model := "Yaris"
+	if err := carCRUDService.Patch(testCtx, car.ID, car.Version, rental.CarPatch{Model: &model}); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	got, err := carCRUDService.Get(testCtx, car.ID)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	want := rental.Car{ID: 1, Make: "Toyota", Model: "Yaris", Year: 2015, Version: 2}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDatabaseCarCRUDService_CreateMany(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	carCRUDService := NewDatabaseCarCRUDService(db)
+	cars := []rental.Car{
+		{Make: "Toyota", Model: "Corolla", Year: 2015},
+		{Make: "Ford", Model: "Fiesta", Year: 2016},
+	}
+	ids, err := carCRUDService.CreateMany(testCtx, cars)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	var got []rental.Car
+	err = carCRUDService.ForEach(testCtx, func(car rental.Car) error {
+		got = append(got, car)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != len(cars) {
+		t.Fatalf("got %d cars, want %d", len(got), len(cars))
+	}
+	for i, car := range cars {
+		car.ID, car.Version = ids[i], 1
+		if got[i] != car {
+			t.Errorf("got %v, want %v", got[i], car)
+		}
+	}
+}
+
+func TestDatabaseCarCRUDService_GetMany(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	carCRUDService := NewDatabaseCarCRUDService(db)
+	var ids []int
+	for _, car := range []rental.Car{
+		{Make: "Toyota", Model: "Corolla", Year: 2015},
+		{Make: "Ford", Model: "Fiesta", Year: 2016},
+		{Make: "Renault", Model: "Clio", Year: 2020},
+	} {
+		id, err := carCRUDService.Create(testCtx, car)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		ids = append(ids, id)
+	}
+
+	got, err := carCRUDService.GetMany(testCtx, []int{ids[2], ids[0], 1000})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 2 || got[0].ID != ids[0] || got[1].ID != ids[2] {
+		t.Errorf("got %v, want cars %d and %d", got, ids[0], ids[2])
+	}
+}
@@ -2,9 +2,11 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/shidenkai0/rental/pkg/rental"
 )
 
@@ -15,43 +17,87 @@ type DatabaseCustomerCRUDService struct {
 }
 
 // Create creates a customer in the database, returns id.
-func (s *DatabaseCustomerCRUDService) Create(customer rental.Customer) (id int, err error) {
-	insertStatement := "INSERT INTO customers (name) VALUES (:name) RETURNING id"
-	rows, err := sqlx.NamedQuery(s.db, insertStatement, customer)
+func (s *DatabaseCustomerCRUDService) Create(ctx context.Context, customer rental.Customer) (id int, err error) {
+	tenantID, err := tenantID(ctx)
 	if err != nil {
 		return 0, err
 	}
-	defer rows.Close()
-	if rows.Next() {
-		err = rows.Scan(&id)
-		if err != nil {
-			return 0, err
-		}
-	}
+	insertStatement := "INSERT INTO customers (tenant_id, name) VALUES ($1, $2) RETURNING id"
+	err = conn(ctx, s.db).QueryRowxContext(ctx, insertStatement, tenantID, customer.Name).Scan(&id)
 	return id, err
 }
 
 // Get fetches a customer from the database.
-func (s *DatabaseCustomerCRUDService) Get(id int) (rental.Customer, error) {
+func (s *DatabaseCustomerCRUDService) Get(ctx context.Context, id int) (rental.Customer, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return rental.Customer{}, err
+	}
 	var customer rental.Customer
-	err := sqlx.Get(s.db, &customer, "SELECT * FROM customers WHERE id = $1 LIMIT 1", id)
+	err = sqlx.GetContext(ctx, conn(ctx, s.db), &customer, "SELECT id, name, version FROM customers WHERE id = $1 AND tenant_id = $2", id, tenantID)
 	if err == sql.ErrNoRows {
 		return rental.Customer{}, rental.ErrCustomerNotFound
 	}
 	return customer, err
 }
 
-// Update updates a customer in the database.
-func (s *DatabaseCustomerCRUDService) Update(customer rental.Customer) error {
-	updateStatement := "UPDATE customers SET name = :name WHERE id = :id"
-	_, err := sqlx.NamedExec(s.db, updateStatement, customer)
-	return err
+// GetMany fetches the customers of ids from the database, ordered by id. Missing customers are skipped.
+func (s *DatabaseCustomerCRUDService) GetMany(ctx context.Context, ids []int) ([]rental.Customer, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	customers := []rental.Customer{}
+	err = sqlx.SelectContext(ctx, conn(ctx, s.db), &customers, "SELECT id, name, version FROM customers WHERE id = ANY($1) AND tenant_id = $2 ORDER BY id", pq.Array(ids), tenantID)
+	return customers, err
+}
+
+// Update updates a customer in the database if it is still at customer.Version, and increments its version.
+func (s *DatabaseCustomerCRUDService) Update(ctx context.Context, customer rental.Customer) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	updateStatement := "UPDATE customers SET name = $1, version = version + 1 WHERE id = $2 AND tenant_id = $3 AND version = $4"
+	res, err := conn(ctx, s.db).ExecContext(ctx, updateStatement, customer.Name, customer.ID, tenantID, customer.Version)
+	if err != nil {
+		return err
+	}
+	return checkVersion(ctx, conn(ctx, s.db), res, "customers", customer.ID, tenantID, rental.ErrCustomerVersionMismatch, rental.ErrCustomerNotFound)
 }
 
-// Delete deletes a customer from the database.
-func (s *DatabaseCustomerCRUDService) Delete(customerID int) error {
-	_, err := s.db.Exec("DELETE FROM customers WHERE id = $1", customerID)
-	return err
+// Patch updates the changed details of a customer in the database if it is still at version, and increments its version.
+func (s *DatabaseCustomerCRUDService) Patch(ctx context.Context, customerID int, version int, patch rental.CustomerPatch) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	set := newAssignments(customerID, tenantID, version)
+	if patch.Name != nil {
+		set.add("name", *patch.Name)
+	}
+	updateStatement := "UPDATE customers SET " + set.clause("version = version + 1") + " WHERE id = $1 AND tenant_id = $2 AND version = $3"
+	res, err := conn(ctx, s.db).ExecContext(ctx, updateStatement, set.args...)
+	if err != nil {
+		return err
+	}
+	return checkVersion(ctx, conn(ctx, s.db), res, "customers", customerID, tenantID, rental.ErrCustomerVersionMismatch, rental.ErrCustomerNotFound)
+}
+
+// Delete deletes a customer from the database if it is still at version and has no rentals.
+func (s *DatabaseCustomerCRUDService) Delete(ctx context.Context, customerID int, version int) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := conn(ctx, s.db).ExecContext(ctx, "DELETE FROM customers WHERE id = $1 AND tenant_id = $2 AND version = $3", customerID, tenantID, version)
+	if isForeignKeyViolation(err) {
+		return rental.ErrCustomerHasRentals
+	}
+	if err != nil {
+		return err
+	}
+	return checkVersion(ctx, conn(ctx, s.db), res, "customers", customerID, tenantID, rental.ErrCustomerVersionMismatch, rental.ErrCustomerNotFound)
 }
 
 // NewDatabaseCustomerCRUDService returns a new DatabaseCustomerCRUDService with the provided database as SQL backend.
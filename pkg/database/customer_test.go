@@ -14,12 +14,12 @@ func TestDatabaseCustomerCRUD_ServiceGet(t *testing.T) {
 	db := sqlx.MustConnect("postgres", testDatabaseURL)
 	t.Run("get a customer", func(t *testing.T) {
 		customerCRUDService := NewDatabaseCustomerCRUDService(db)
-		customer := rental.Customer{ID: 1, Name: "John Doe"}
-		_, err := customerCRUDService.Create(customer)
+		customer := rental.Customer{ID: 1, Name: "John Doe", Version: 1}
+		_, err := customerCRUDService.Create(testCtx, customer)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
-		got, err := customerCRUDService.Get(1)
+		got, err := customerCRUDService.Get(testCtx, 1)
 		if err != nil {
 			t.Errorf("got error %v, want nil", err)
 		}
@@ -29,7 +29,7 @@ func TestDatabaseCustomerCRUD_ServiceGet(t *testing.T) {
 	})
 	t.Run("get a non-existent customer", func(t *testing.T) {
 		customerCRUDService := NewDatabaseCustomerCRUDService(db)
-		_, err := customerCRUDService.Get(100)
+		_, err := customerCRUDService.Get(testCtx, 100)
 		if err != rental.ErrCustomerNotFound {
 			t.Errorf(fmt.Sprintf("got error %v, want %v", err, rental.ErrCustomerNotFound))
 		}
@@ -42,13 +42,13 @@ func TestDatabaseCustomerCRUD_ServiceCreate(t *testing.T) {
 
 	db := sqlx.MustConnect("postgres", testDatabaseURL)
 	customerCRUDService := NewDatabaseCustomerCRUDService(db)
-	customer := rental.Customer{ID: 1, Name: "John Doe"}
-	_, err := customerCRUDService.Create(customer)
+	customer := rental.Customer{ID: 1, Name: "John Doe", Version: 1}
+	_, err := customerCRUDService.Create(testCtx, customer)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
-	got, err := customerCRUDService.Get(1)
+	got, err := customerCRUDService.Get(testCtx, 1)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
@@ -63,19 +63,20 @@ func TestDatabaseCustomerCRUD_ServiceUpdate(t *testing.T) {
 
 	db := sqlx.MustConnect("postgres", testDatabaseURL)
 	customerCRUDService := NewDatabaseCustomerCRUDService(db)
-	customer := rental.Customer{ID: 1, Name: "John Doe"}
-	_, err := customerCRUDService.Create(customer)
+	customer := rental.Customer{ID: 1, Name: "John Doe", Version: 1}
+	_, err := customerCRUDService.Create(testCtx, customer)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
 	customer.Name = "Jane Doe"
-	err = customerCRUDService.Update(customer)
+	err = customerCRUDService.Update(testCtx, customer)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
+	customer.Version++
 
-	got, err := customerCRUDService.Get(1)
+	got, err := customerCRUDService.Get(testCtx, 1)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
@@ -90,18 +91,18 @@ func TestDatabaseCustomerCRUD_ServiceDelete(t *testing.T) {
 
 	db := sqlx.MustConnect("postgres", testDatabaseURL)
 	customerCRUDService := NewDatabaseCustomerCRUDService(db)
-	customer := rental.Customer{ID: 1, Name: "John Doe"}
-	_, err := customerCRUDService.Create(customer)
+	customer := rental.Customer{ID: 1, Name: "John Doe", Version: 1}
+	_, err := customerCRUDService.Create(testCtx, customer)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
-	err = customerCRUDService.Delete(1)
+	err = customerCRUDService.Delete(testCtx, 1, 1)
 	if err != nil {
 		t.Errorf("got error %v, want nil", err)
 	}
 
-	_, err = customerCRUDService.Get(1)
+	_, err = customerCRUDService.Get(testCtx, 1)
 	if err != rental.ErrCustomerNotFound {
 		t.Errorf(fmt.Sprintf("got error %v, want %v", err, rental.ErrCustomerNotFound))
 	}
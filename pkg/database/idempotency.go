@@ -0,0 +1,87 @@
+// Package database implements the database layer of the rental service.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// DatabaseIdempotencyKeyService is a concrete implementation of the IdempotencyKeyService
+// interface using Postgres as a backend.
+type DatabaseIdempotencyKeyService struct {
+	db *sqlx.DB
+}
+
+// Create records an idempotency key in the database, returns id. An expired
+// key that hasn't been purged yet is replaced.
+func (s *DatabaseIdempotencyKeyService) Create(ctx context.Context, key rental.IdempotencyKey) (id int, err error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	insertStatement := `INSERT INTO idempotency_keys (tenant_id, client, key, fingerprint, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, client, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint, status_code = NULL, response_headers = NULL, response_body = NULL,
+			created_at = now(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= now()
+		RETURNING id`
+	err = s.db.QueryRowContext(ctx, insertStatement, tenantID, key.Client, key.Key, key.Fingerprint, key.ExpiresAt).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, rental.ErrIdempotencyKeyAlreadyExists
+	}
+	return id, err
+}
+
+// Get fetches the idempotency key of a client from the database.
+func (s *DatabaseIdempotencyKeyService) Get(ctx context.Context, client, key string) (rental.IdempotencyKey, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return rental.IdempotencyKey{}, err
+	}
+	var idempotencyKey rental.IdempotencyKey
+	err = sqlx.GetContext(ctx, s.db, &idempotencyKey, "SELECT * FROM idempotency_keys WHERE tenant_id = $1 AND client = $2 AND key = $3", tenantID, client, key)
+	if err == sql.ErrNoRows {
+		return rental.IdempotencyKey{}, rental.ErrIdempotencyKeyNotFound
+	}
+	return idempotencyKey, err
+}
+
+// Complete records the response of the request made with an idempotency key.
+func (s *DatabaseIdempotencyKeyService) Complete(ctx context.Context, key rental.IdempotencyKey) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	updateStatement := "UPDATE idempotency_keys SET status_code = $3, response_headers = $4, response_body = $5 WHERE id = $1 AND tenant_id = $2"
+	_, err = s.db.ExecContext(ctx, updateStatement, key.ID, tenantID, key.StatusCode, nullableJSON(key.ResponseHeaders), key.ResponseBody)
+	return err
+}
+
+// Delete deletes an idempotency key from the database.
+func (s *DatabaseIdempotencyKeyService) Delete(ctx context.Context, id int) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE id = $1 AND tenant_id = $2", id, tenantID)
+	return err
+}
+
+// Purge deletes the idempotency keys of all tenants that expired before now.
+func (s *DatabaseIdempotencyKeyService) Purge(ctx context.Context, now time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= $1", now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// NewDatabaseIdempotencyKeyService returns a new DatabaseIdempotencyKeyService with the provided database as SQL backend.
+func NewDatabaseIdempotencyKeyService(db *sqlx.DB) *DatabaseIdempotencyKeyService {
+	return &DatabaseIdempotencyKeyService{db: db}
+}
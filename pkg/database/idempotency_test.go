@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestDatabaseIdempotencyKeyService(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	service := NewDatabaseIdempotencyKeyService(db)
+
+	key := rental.IdempotencyKey{
+		Client:      "jane",
+		Key:         "key-1",
+		Fingerprint: "3c9b7c4e8a1f7bd6c7e1e2a0d1d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	id, err := service.Create(testCtx, key)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if _, err := service.Create(testCtx, key); err != rental.ErrIdempotencyKeyAlreadyExists {
+		t.Errorf("got error %v, want %v", err, rental.ErrIdempotencyKeyAlreadyExists)
+	}
+
+	key.ID = id
+	key.StatusCode = null.IntFrom(201)
+	key.ResponseHeaders = []byte(`{"Content-Type":["application/json"]}`)
+	key.ResponseBody = []byte(`{"id":1}`)
+	if err := service.Complete(testCtx, key); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	got, err := service.Get(testCtx, key.Client, key.Key)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if !got.Completed() || got.StatusCode.Int64 != 201 || string(got.ResponseBody) != `{"id":1}` {
+		t.Errorf("got %+v, want completed key with recorded response", got)
+	}
+
+	purged, err := service.Purge(testCtx, time.Now().Add(2*time.Hour))
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if purged != 1 {
+		t.Errorf("got %d purged keys, want 1", purged)
+	}
+	if _, err := service.Get(testCtx, key.Client, key.Key); err != rental.ErrIdempotencyKeyNotFound {
+		t.Errorf("got error %v, want %v", err, rental.ErrIdempotencyKeyNotFound)
+	}
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/shidenkai0/rental/db/migrations"
+)
+
+// migrateLockID is the key of the advisory lock held while migrating the
+// database on start, so that the replicas of the service starting together
+// don't race to apply the same migrations.
+const migrateLockID = 0x72656e74616c // "rental"
+
+// MigrationStatus is the state of the schema of a database.
+type MigrationStatus struct {
+	// Version is the version of the latest migration applied, 0 if none.
+	Version uint
+	// Dirty reports whether the migration Version failed midway, and must be
+	// fixed by hand then forced.
+	Dirty bool
+	// Latest is the version of the latest migration embedded in the binary.
+	Latest uint
+}
+
+// Migrator applies the migrations embedded in the binary to a database.
+type Migrator struct {
+	m    *migrate.Migrate
+	conn *sql.Conn
+}
+
+// NewMigrator returns a Migrator of db, using a connection of its own until
+// closed.
+func NewMigrator(ctx context.Context, db *sql.DB) (*Migrator, error) {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The driver of the connection, unlike postgres.WithInstance, leaves db
+	// open when closed
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return nil, err
+	}
+	return &Migrator{m: m, conn: conn}, nil
+}
+
+// Up applies the pending migrations.
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Down reverts the latest steps migrations applied.
+func (m *Migrator) Down(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("Number of migrations to revert must be positive")
+	}
+	return m.m.Steps(-steps)
+}
+
+// Force sets the version of the schema to version without applying any
+// migration, and marks it clean, once a failed migration was fixed by hand.
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+// Status returns the state of the schema.
+func (m *Migrator) Status() (MigrationStatus, error) {
+	status := MigrationStatus{Latest: SchemaVersion}
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return status, nil
+	}
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	status.Version, status.Dirty = version, dirty
+	return status, nil
+}
+
+// UpLocked applies the pending migrations like Up, once it holds the advisory
+// lock of the migrations on start, waiting for the replicas holding it.
+func (m *Migrator) UpLocked(ctx context.Context) error {
+	if _, err := m.conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrateLockID); err != nil {
+		return fmt.Errorf("Acquiring migration lock: %w", err)
+	}
+	defer m.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrateLockID)
+	return m.Up()
+}
+
+// Close closes the connection of m.
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.m.Close()
+	if sourceErr != nil {
+		return sourceErr
+	}
+	return databaseErr
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/db/migrations"
+)
+
+// TestMigrations ensures every migration embedded can be reverted.
+func TestMigrations(t *testing.T) {
+	files, err := migrations.FS.ReadDir(".")
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	names := map[string]bool{}
+	for _, file := range files {
+		names[file.Name()] = true
+	}
+	for name := range names {
+		if !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		if down := strings.TrimSuffix(name, ".up.sql") + ".down.sql"; !names[down] {
+			t.Errorf("got migration %s without %s, want both", name, down)
+		}
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	// Setup
+
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	defer db.Close()
+
+	m, err := NewMigrator(context.Background(), db.DB)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	defer m.Close()
+
+	// Test
+
+	status, err := m.Status()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if want := (MigrationStatus{Version: SchemaVersion, Latest: SchemaVersion}); status != want {
+		t.Errorf("got status %+v, want %+v", status, want)
+	}
+
+	if err := m.Down(1); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if status, _ := m.Status(); status.Version != SchemaVersion-1 {
+		t.Errorf("got version %d, want %d", status.Version, SchemaVersion-1)
+	}
+
+	if err := m.UpLocked(context.Background()); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if status, _ := m.Status(); status.Version != SchemaVersion {
+		t.Errorf("got version %d, want %d", status.Version, SchemaVersion)
+	}
+	// The lock is released once migrated
+	conn := db.MustBegin()
+	defer conn.Rollback()
+	var locked bool
+	if err := conn.Get(&locked, "SELECT pg_try_advisory_xact_lock($1)", migrateLockID); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if !locked {
+		t.Errorf("got migration lock held, want released")
+	}
+}
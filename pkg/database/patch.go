@@ -0,0 +1,31 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+)
+
+// assignments builds the SET clause of a partial update, so that only the
+// changed columns are written.
+type assignments struct {
+	set  []string
+	args []interface{}
+}
+
+// newAssignments returns assignments whose parameters are numbered after
+// args, the parameters of the WHERE clause.
+func newAssignments(args ...interface{}) *assignments {
+	return &assignments{args: args}
+}
+
+// add assigns value to column.
+func (a *assignments) add(column string, value interface{}) {
+	a.args = append(a.args, value)
+	a.set = append(a.set, column+" = $"+strconv.Itoa(len(a.args)))
+}
+
+// clause returns the assignments as a SET clause, followed by extra
+// assignments without parameters, eg. "version = version + 1".
+func (a *assignments) clause(extra ...string) string {
+	return strings.Join(append(a.set, extra...), ", ")
+}
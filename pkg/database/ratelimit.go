@@ -0,0 +1,66 @@
+// Package database implements the database layer of the rental service.
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/ratelimit"
+)
+
+// DatabaseRateLimitStore is a concrete implementation of the ratelimit.Store
+// interface using Postgres as a backend, it shares the budgets of the clients
+// between the instances of the service.
+type DatabaseRateLimitStore struct {
+	db *sqlx.DB
+}
+
+// Take takes a token from the bucket identified by key. The bucket row is
+// locked for the duration of the transaction, so that concurrent requests of
+// a client take tokens one after the other.
+func (s *DatabaseRateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (result ratelimit.Result, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	bucket := ratelimit.NewBucket(limit, now)
+	insertStatement := "INSERT INTO rate_limit_buckets (key, tokens, updated_at, full_at) VALUES ($1, $2, $3, $3) ON CONFLICT (key) DO NOTHING"
+	if _, err = tx.ExecContext(ctx, insertStatement, key, bucket.Tokens, bucket.UpdatedAt); err != nil {
+		return ratelimit.Result{}, err
+	}
+	err = tx.QueryRowxContext(ctx, "SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE", key).Scan(&bucket.Tokens, &bucket.UpdatedAt)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	bucket, result = bucket.Take(limit, now)
+	updateStatement := "UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3, full_at = $4 WHERE key = $1"
+	if _, err = tx.ExecContext(ctx, updateStatement, key, bucket.Tokens, bucket.UpdatedAt, now.Add(result.Reset)); err != nil {
+		return ratelimit.Result{}, err
+	}
+	return result, nil
+}
+
+// Purge deletes the buckets that are full at now, as they are no different
+// from new buckets, returns the number of deleted buckets.
+func (s *DatabaseRateLimitStore) Purge(ctx context.Context, now time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM rate_limit_buckets WHERE full_at <= $1", now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// NewDatabaseRateLimitStore returns a new DatabaseRateLimitStore with the provided database as SQL backend.
+func NewDatabaseRateLimitStore(db *sqlx.DB) *DatabaseRateLimitStore {
+	return &DatabaseRateLimitStore{db: db}
+}
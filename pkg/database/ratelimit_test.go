@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/ratelimit"
+)
+
+func TestDatabaseRateLimitStore_Take(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	store := NewDatabaseRateLimitStore(db)
+	limit := ratelimit.Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+
+	for i, wantAllowed := range []bool{true, true, false} {
+		got, err := store.Take(context.Background(), "read:principal:jane", limit, now)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got.Allowed != wantAllowed {
+			t.Errorf("request %d: got allowed %t, want %t", i, got.Allowed, wantAllowed)
+		}
+	}
+
+	purged, err := store.Purge(context.Background(), now.Add(time.Hour))
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if purged != 1 {
+		t.Errorf("got %d purged buckets, want 1", purged)
+	}
+}
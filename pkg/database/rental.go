@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// rentalColumns are the columns of the rentals table mapped to rental.Rental.
+const rentalColumns = "id, car_id, customer_id, started_at, returned_at"
+
+// DatabaseRentalService is a concrete implementation of the RentalService
+// interface using Postgres as a backend.
+type DatabaseRentalService struct {
+	db *sqlx.DB
+}
+
+// Create records a rental in the database, returns id.
+func (s *DatabaseRentalService) Create(ctx context.Context, r rental.Rental) (id int, err error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	insertStatement := "INSERT INTO rentals (tenant_id, car_id, customer_id, started_at) VALUES ($1, $2, $3, $4) RETURNING id"
+	err = conn(ctx, s.db).QueryRowxContext(ctx, insertStatement, tenantID, r.CarID, r.CustomerID, r.StartedAt).Scan(&id)
+	return id, err
+}
+
+// Get fetches a rental from the database.
+func (s *DatabaseRentalService) Get(ctx context.Context, id int) (rental.Rental, error) {
+	return s.get(ctx, "id = $1", id)
+}
+
+// GetActiveByCar fetches the active rental of a car from the database.
+func (s *DatabaseRentalService) GetActiveByCar(ctx context.Context, carID int) (rental.Rental, error) {
+	return s.get(ctx, "car_id = $1 AND returned_at IS NULL", carID)
+}
+
+// get fetches the rental of the tenant of ctx matching condition, whose parameter is arg.
+func (s *DatabaseRentalService) get(ctx context.Context, condition string, arg interface{}) (rental.Rental, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return rental.Rental{}, err
+	}
+	var r rental.Rental
+	err = sqlx.GetContext(ctx, conn(ctx, s.db), &r, "SELECT "+rentalColumns+" FROM rentals WHERE "+condition+" AND tenant_id = $2", arg, tenantID)
+	if err == sql.ErrNoRows {
+		return rental.Rental{}, rental.ErrRentalNotFound
+	}
+	return r, err
+}
+
+// End records the return of the car of an active rental in the database.
+func (s *DatabaseRentalService) End(ctx context.Context, id int, returnedAt time.Time) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := conn(ctx, s.db).ExecContext(ctx, "UPDATE rentals SET returned_at = $1 WHERE id = $2 AND tenant_id = $3 AND returned_at IS NULL", returnedAt, id, tenantID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return rental.ErrRentalAlreadyReturned
+}
+
+// NewDatabaseRentalService returns a new DatabaseRentalService with the provided database as SQL backend.
+func NewDatabaseRentalService(db *sqlx.DB) *DatabaseRentalService {
+	return &DatabaseRentalService{db: db}
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestDatabaseRentalService(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+
+	// Setup
+
+	customerID, err := NewDatabaseCustomerCRUDService(db).Create(testCtx, rental.Customer{Name: "John Doe"})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	carID, err := NewDatabaseCarCRUDService(db).Create(testCtx, rental.Car{Make: "Toyota", Model: "Corolla", Year: 2015})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	rentalService := NewDatabaseRentalService(db)
+	rentalID, err := rentalService.Create(testCtx, rental.Rental{CarID: carID, CustomerID: customerID, StartedAt: time.Now()})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	// Test
+
+	t.Run("get active rental of a car", func(t *testing.T) {
+		got, err := rentalService.GetActiveByCar(testCtx, carID)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got.ID != rentalID || got.CustomerID != customerID || !got.Active() {
+			t.Errorf("got %v, want active rental %d", got, rentalID)
+		}
+	})
+	t.Run("a car has a single active rental", func(t *testing.T) {
+		if _, err := rentalService.Create(testCtx, rental.Rental{CarID: carID, CustomerID: customerID, StartedAt: time.Now()}); err == nil {
+			t.Errorf("got nil, want unique violation")
+		}
+	})
+	t.Run("end rental", func(t *testing.T) {
+		if err := rentalService.End(testCtx, rentalID, time.Now()); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if _, err := rentalService.GetActiveByCar(testCtx, carID); err != rental.ErrRentalNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrRentalNotFound)
+		}
+		if err := rentalService.End(testCtx, rentalID, time.Now()); err != rental.ErrRentalAlreadyReturned {
+			t.Errorf("got error %v, want %v", err, rental.ErrRentalAlreadyReturned)
+		}
+	})
+	t.Run("cars and customers with returned rentals are kept", func(t *testing.T) {
+		if err := NewDatabaseCarCRUDService(db).Delete(testCtx, carID, 1); err != rental.ErrCarHasRentals {
+			t.Errorf("got error %v, want %v", err, rental.ErrCarHasRentals)
+		}
+		if err := NewDatabaseCustomerCRUDService(db).Delete(testCtx, customerID, 1); err != rental.ErrCustomerHasRentals {
+			t.Errorf("got error %v, want %v", err, rental.ErrCustomerHasRentals)
+		}
+	})
+	t.Run("end non-existent rental", func(t *testing.T) {
+		if err := rentalService.End(testCtx, rentalID+1, time.Now()); err != rental.ErrRentalNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrRentalNotFound)
+		}
+	})
+}
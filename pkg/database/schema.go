@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SchemaVersion is the version of the database schema the service expects,
+// the version of the latest migration of db/migrations.
+const SchemaVersion = 12
+
+// CheckSchemaVersion returns an error if the schema of db is older than
+// SchemaVersion, or if a migration failed midway. Newer schemas are
+// accepted, as migrations are applied before the service is deployed and
+// must be compatible with the version running.
+func CheckSchemaVersion(ctx context.Context, db *sqlx.DB) error {
+	var version uint
+	var dirty bool
+	err := db.QueryRowxContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("Database schema migration %d failed", version)
+	}
+	if version < SchemaVersion {
+		return fmt.Errorf("Database schema version %d is older than version %d", version, SchemaVersion)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/db/migrations"
+)
+
+// TestSchemaVersion ensures SchemaVersion is bumped along with the migrations.
+func TestSchemaVersion(t *testing.T) {
+	files, err := migrations.FS.ReadDir(".")
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	var latest int
+	for _, file := range files {
+		version, err := strconv.Atoi(strings.SplitN(file.Name(), "_", 2)[0])
+		if err != nil {
+			t.Fatalf("got migration %s, want a numbered migration", file.Name())
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	if latest != SchemaVersion {
+		t.Errorf("got SchemaVersion %d, want %d, the version of the latest migration", SchemaVersion, latest)
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+
+	if err := CheckSchemaVersion(testCtx, db); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	db.MustExec("UPDATE schema_migrations SET version = $1", SchemaVersion-1)
+	defer db.MustExec("UPDATE schema_migrations SET version = $1", SchemaVersion)
+	if err := CheckSchemaVersion(testCtx, db); err == nil {
+		t.Errorf("got nil error for an older schema, want error")
+	}
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// serialTables are the tables whose id is generated by a sequence.
+var serialTables = []string{
+	"tenants",
+	"customers",
+	"cars",
+	"rentals",
+	"users",
+	"api_keys",
+	"refresh_tokens",
+	"audit_log",
+	"idempotency_keys",
+}
+
+// ResetSequences sets the sequence of the id of each table past its largest
+// id, so that rows inserted with explicit ids, eg. by hand or by a restore,
+// don't make the next inserts collide with them.
+func ResetSequences(ctx context.Context, db *sqlx.DB) error {
+	return withinTransaction(ctx, db, func(ctx context.Context, tx *sqlx.Tx) error {
+		for _, table := range serialTables {
+			// The next id is the largest id + 1, or 1 if the table is empty
+			query := "SELECT setval(pg_get_serial_sequence('" + table + "', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM " + table
+			if _, err := tx.ExecContext(ctx, query); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestResetSequences(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	carCRUDService := NewDatabaseCarCRUDService(db)
+
+	// Setup
+
+	db.MustExec("INSERT INTO cars (id, tenant_id, make, model, year) VALUES (1, $1, 'Toyota', 'Corolla', 2000), (2, $1, 'Ford', 'Focus', 2005)", rental.DefaultTenantID)
+
+	// Test
+
+	if err := ResetSequences(testCtx, db); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	id, err := carCRUDService.Create(testCtx, rental.Car{Make: "Honda", Model: "Civic", Year: 2010})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if id != 3 {
+		t.Errorf("got id %d, want 3", id)
+	}
+}
@@ -0,0 +1,68 @@
+// Package database implements the database layer of the rental service.
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// tenantID returns the tenant ctx is scoped to. Every query of tenant-scoped
+// services is restricted to this tenant.
+func tenantID(ctx context.Context) (int, error) {
+	tenantID, ok := rental.TenantFrom(ctx)
+	if !ok {
+		return 0, rental.ErrNoTenant
+	}
+	return tenantID, nil
+}
+
+// DatabaseTenantService is a concrete implementation of the TenantService
+// interface using Postgres as a backend.
+type DatabaseTenantService struct {
+	db *sqlx.DB
+}
+
+// Create creates a tenant in the database, returns id.
+func (s *DatabaseTenantService) Create(ctx context.Context, tenant rental.Tenant) (id int, err error) {
+	insertStatement := "INSERT INTO tenants (slug, name) VALUES ($1, $2) RETURNING id"
+	err = s.db.QueryRowContext(ctx, insertStatement, tenant.Slug, tenant.Name).Scan(&id)
+	if isUniqueViolation(err) {
+		return 0, rental.ErrTenantAlreadyExists
+	}
+	return id, err
+}
+
+// Get fetches a tenant from the database.
+func (s *DatabaseTenantService) Get(ctx context.Context, id int) (rental.Tenant, error) {
+	var tenant rental.Tenant
+	err := sqlx.GetContext(ctx, s.db, &tenant, "SELECT id, slug, name, created_at FROM tenants WHERE id = $1", id)
+	if err == sql.ErrNoRows {
+		return rental.Tenant{}, rental.ErrTenantNotFound
+	}
+	return tenant, err
+}
+
+// GetBySlug fetches a tenant from the database by slug.
+func (s *DatabaseTenantService) GetBySlug(ctx context.Context, slug string) (rental.Tenant, error) {
+	var tenant rental.Tenant
+	err := sqlx.GetContext(ctx, s.db, &tenant, "SELECT id, slug, name, created_at FROM tenants WHERE slug = $1", slug)
+	if err == sql.ErrNoRows {
+		return rental.Tenant{}, rental.ErrTenantNotFound
+	}
+	return tenant, err
+}
+
+// List fetches all tenants from the database.
+func (s *DatabaseTenantService) List(ctx context.Context) ([]rental.Tenant, error) {
+	tenants := []rental.Tenant{}
+	err := sqlx.SelectContext(ctx, s.db, &tenants, "SELECT id, slug, name, created_at FROM tenants ORDER BY id")
+	return tenants, err
+}
+
+// NewDatabaseTenantService returns a new DatabaseTenantService with the provided database as SQL backend.
+func NewDatabaseTenantService(db *sqlx.DB) *DatabaseTenantService {
+	return &DatabaseTenantService{db: db}
+}
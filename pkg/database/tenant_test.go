@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestDatabaseTenantService_Create(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	tenantService := NewDatabaseTenantService(db)
+
+	t.Run("create tenant", func(t *testing.T) {
+		id, err := tenantService.Create(context.Background(), rental.Tenant{Slug: "lyon", Name: "Rental Lyon"})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		got, err := tenantService.GetBySlug(context.Background(), "lyon")
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got.ID != id || got.Name != "Rental Lyon" {
+			t.Errorf("got %v, want tenant %d", got, id)
+		}
+	})
+	t.Run("create tenant with a duplicate slug", func(t *testing.T) {
+		_, err := tenantService.Create(context.Background(), rental.Tenant{Slug: "default", Name: "Default"})
+		if err != rental.ErrTenantAlreadyExists {
+			t.Errorf("got error %v, want %v", err, rental.ErrTenantAlreadyExists)
+		}
+	})
+}
+
+func TestDatabaseTenantIsolation(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+
+	// Setup
+
+	otherTenantID, err := NewDatabaseTenantService(db).Create(context.Background(), rental.Tenant{Slug: "lyon", Name: "Rental Lyon"})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	otherCtx := rental.WithTenant(context.Background(), otherTenantID)
+
+	customerCRUDService := NewDatabaseCustomerCRUDService(db)
+	customerID, err := customerCRUDService.Create(testCtx, rental.Customer{Name: "John Doe"})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	carCRUDService := NewDatabaseCarCRUDService(db)
+	carID, err := carCRUDService.Create(testCtx, rental.Car{Make: "Toyota", Model: "Corolla", Year: 2015})
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	// Test
+
+	t.Run("other tenants don't see the data", func(t *testing.T) {
+		if _, err := carCRUDService.Get(otherCtx, carID); err != rental.ErrCarNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrCarNotFound)
+		}
+		if _, err := customerCRUDService.Get(otherCtx, customerID); err != rental.ErrCustomerNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrCustomerNotFound)
+		}
+	})
+	t.Run("other tenants can't modify the data", func(t *testing.T) {
+		if err := carCRUDService.Delete(otherCtx, carID, 1); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if _, err := carCRUDService.Get(testCtx, carID); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	})
+	t.Run("cars can't be rented to the customers of other tenants", func(t *testing.T) {
+		otherCarID, err := carCRUDService.Create(otherCtx, rental.Car{Make: "Ford", Model: "Fiesta", Year: 2016})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		car, err := carCRUDService.Get(otherCtx, otherCarID)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if err := car.Rent(customerID); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if err := carCRUDService.Update(otherCtx, car); err == nil {
+			t.Errorf("got nil, want foreign key violation")
+		}
+	})
+	t.Run("services require a tenant", func(t *testing.T) {
+		if _, err := carCRUDService.Get(context.Background(), carID); err != rental.ErrNoTenant {
+			t.Errorf("got error %v, want %v", err, rental.ErrNoTenant)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// Package database implements the database layer of the rental service.
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// DatabaseRefreshTokenService is a concrete implementation of the RefreshTokenService
+// interface using Postgres as a backend.
+type DatabaseRefreshTokenService struct {
+	db *sqlx.DB
+}
+
+// Create stores a refresh token in the database, returns id.
+func (s *DatabaseRefreshTokenService) Create(ctx context.Context, token rental.RefreshToken) (id int, err error) {
+	token.TenantID, err = tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	insertStatement := "INSERT INTO refresh_tokens (tenant_id, user_id, token_hash, scope, expires_at) VALUES (:tenant_id, :user_id, :token_hash, :scope, :expires_at) RETURNING id"
+	rows, err := sqlx.NamedQueryContext(ctx, s.db, insertStatement, token)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		err = rows.Scan(&id)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return id, err
+}
+
+// GetByHash fetches a refresh token from the database by hash, whatever its tenant.
+func (s *DatabaseRefreshTokenService) GetByHash(ctx context.Context, tokenHash string) (rental.RefreshToken, error) {
+	var token rental.RefreshToken
+	err := sqlx.GetContext(ctx, s.db, &token, "SELECT * FROM refresh_tokens WHERE token_hash = $1 LIMIT 1", tokenHash)
+	if err == sql.ErrNoRows {
+		return rental.RefreshToken{}, rental.ErrRefreshTokenNotFound
+	}
+	return token, err
+}
+
+// Revoke revokes a refresh token, or fails with rental.ErrRefreshTokenRevoked if
+// it's already revoked, eg. by a concurrent refresh with the same token.
+func (s *DatabaseRefreshTokenService) Revoke(ctx context.Context, tokenID int) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = now() WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL", tokenID, tenantID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return rental.ErrRefreshTokenRevoked
+	}
+	return nil
+}
+
+// RevokeAllForUser revokes all the refresh tokens of a user.
+func (s *DatabaseRefreshTokenService) RevokeAllForUser(ctx context.Context, userID int) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, "UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND tenant_id = $2 AND revoked_at IS NULL", userID, tenantID)
+	return err
+}
+
+// NewDatabaseRefreshTokenService returns a new DatabaseRefreshTokenService with the provided database as SQL backend.
+func NewDatabaseRefreshTokenService(db *sqlx.DB) *DatabaseRefreshTokenService {
+	return &DatabaseRefreshTokenService{db: db}
+}
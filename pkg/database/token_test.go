@@ -0,0 +1,101 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestDatabaseRefreshTokenService_GetByHash(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	if _, err := NewDatabaseUserCRUDService(db).Create(testCtx, rental.User{Username: "jane", PasswordHash: "hash", Role: rental.RoleAgent}); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	refreshTokenService := NewDatabaseRefreshTokenService(db)
+	token := rental.RefreshToken{UserID: 1, TokenHash: "a3f1c2", Scope: "cars:read", ExpiresAt: time.Now().Add(time.Hour)}
+	id, err := refreshTokenService.Create(testCtx, token)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	t.Run("get a refresh token", func(t *testing.T) {
+		got, err := refreshTokenService.GetByHash(testCtx, token.TokenHash)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got.ID != id || got.UserID != token.UserID || got.Scope != token.Scope || !got.Valid(time.Now()) {
+			t.Errorf("got %v, want valid token %d", got, id)
+		}
+	})
+	t.Run("get a non-existent refresh token", func(t *testing.T) {
+		_, err := refreshTokenService.GetByHash(testCtx, "unknown")
+		if err != rental.ErrRefreshTokenNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrRefreshTokenNotFound)
+		}
+	})
+}
+
+func TestDatabaseRefreshTokenService_Revoke(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	if _, err := NewDatabaseUserCRUDService(db).Create(testCtx, rental.User{Username: "jane", PasswordHash: "hash", Role: rental.RoleAgent}); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	refreshTokenService := NewDatabaseRefreshTokenService(db)
+	token := rental.RefreshToken{UserID: 1, TokenHash: "a3f1c2", Scope: "cars:read", ExpiresAt: time.Now().Add(time.Hour)}
+	id, err := refreshTokenService.Create(testCtx, token)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	if err := refreshTokenService.Revoke(testCtx, id); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	got, err := refreshTokenService.GetByHash(testCtx, token.TokenHash)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if got.Valid(time.Now()) {
+		t.Errorf("got valid token, want revoked token")
+	}
+	if err := refreshTokenService.Revoke(testCtx, id); err != rental.ErrRefreshTokenRevoked {
+		t.Errorf("got error %v, want %v", err, rental.ErrRefreshTokenRevoked)
+	}
+}
+
+func TestDatabaseRefreshTokenService_RevokeAllForUser(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	if _, err := NewDatabaseUserCRUDService(db).Create(testCtx, rental.User{Username: "jane", PasswordHash: "hash", Role: rental.RoleAgent}); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	refreshTokenService := NewDatabaseRefreshTokenService(db)
+	token := rental.RefreshToken{UserID: 1, TokenHash: "a3f1c2", Scope: "cars:read", ExpiresAt: time.Now().Add(time.Hour)}
+	if _, err := refreshTokenService.Create(testCtx, token); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	if err := refreshTokenService.RevokeAllForUser(testCtx, 1); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	got, err := refreshTokenService.GetByHash(testCtx, token.TokenHash)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if got.Valid(time.Now()) {
+		t.Errorf("got valid token, want revoked token")
+	}
+}
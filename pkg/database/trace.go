@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log/slog"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shidenkai0/rental/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Connect connects to the Postgres database at url, tracing every query in a
+// client span, child of the span of the context of the query. Failed queries
+// are logged with the context of the query, so that they can be correlated
+// with the request they were made for.
+func Connect(url string) (*sqlx.DB, error) {
+	connector, err := pq.NewConnector(url)
+	if err != nil {
+		return nil, err
+	}
+	db := sqlx.NewDb(sql.OpenDB(&tracedConnector{connector: connector}), "postgres")
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// tracedConnector opens connections tracing their queries and transactions.
+type tracedConnector struct {
+	connector driver.Connector
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn}, nil
+}
+
+func (c *tracedConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// tracedConn traces the queries and transactions of a connection. Statements
+// prepared, eg. to copy rows, are traced when prepared only.
+type tracedConn struct {
+	driver.Conn
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startSpan(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	endSpan(ctx, span, query, err)
+	return rows, err
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startSpan(ctx, query)
+	res, err := execer.ExecContext(ctx, query, args)
+	endSpan(ctx, span, query, err)
+	return res, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	ctx, span := startSpan(ctx, query)
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	endSpan(ctx, span, query, err)
+	return stmt, err
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	beginner, ok := c.Conn.(driver.ConnBeginTx)
+	if !ok {
+		return c.Conn.Begin()
+	}
+	spanCtx, span := startSpan(ctx, "BEGIN")
+	tx, err := beginner.BeginTx(ctx, opts)
+	endSpan(spanCtx, span, "BEGIN", err)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedTx{Tx: tx, ctx: ctx}, nil
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// tracedTx traces the commit or rollback of a transaction, as a child of the
+// span of the context the transaction began with.
+type tracedTx struct {
+	driver.Tx
+	ctx context.Context
+}
+
+func (tx *tracedTx) Commit() error {
+	ctx, span := startSpan(tx.ctx, "COMMIT")
+	err := tx.Tx.Commit()
+	endSpan(ctx, span, "COMMIT", err)
+	return err
+}
+
+func (tx *tracedTx) Rollback() error {
+	ctx, span := startSpan(tx.ctx, "ROLLBACK")
+	err := tx.Tx.Rollback()
+	endSpan(ctx, span, "ROLLBACK", err)
+	return err
+}
+
+// startSpan starts the client span of query, named after its operation, eg. SELECT.
+func startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := query
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	return otel.Tracer(tracing.InstrumentationName).Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(query),
+		),
+	)
+}
+
+// endSpan ends the span of query, recording and logging err unless it is
+// driver.ErrSkip. Unique and foreign key violations are reported to clients
+// as conflicts, eg. rental.ErrTenantAlreadyExists or rental.ErrCarHasRentals,
+// so they are logged as warnings only.
+func endSpan(ctx context.Context, span trace.Span, query string, err error) {
+	if err != nil && err != driver.ErrSkip {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		level := slog.LevelError
+		if isUniqueViolation(err) || isForeignKeyViolation(err) {
+			level = slog.LevelWarn
+		}
+		slog.Log(ctx, level, "database query failed", "query", query, "error", err)
+	}
+	span.End()
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestConnect_Tracing(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db, err := Connect(testDatabaseURL)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	defer db.Close()
+	transactor := NewDatabaseTransactor(db)
+	carCRUDService := NewDatabaseCarCRUDService(db)
+
+	// The spans of the queries are started by the global tracer provider
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	ctx, parent := provider.Tracer("test").Start(testCtx, "parent")
+
+	// Test
+
+	err = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := carCRUDService.Create(ctx, rental.Car{Make: "Toyota", Model: "Corolla", Year: 2015})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	parent.End()
+
+	var got []string
+	for _, span := range recorder.Ended() {
+		if span.Name() == "parent" {
+			continue
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("got span %s child of %s, want child of the parent span", span.Name(), span.Parent().SpanID())
+		}
+		got = append(got, span.Name())
+	}
+	want := []string{"BEGIN", "INSERT", "COMMIT"}
+	if len(got) != len(want) {
+		t.Fatalf("got spans %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got spans %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// txKey is the context key of the transaction a call takes part in.
+type txKey struct{}
+
+// DatabaseTransactor is a concrete implementation of the Transactor
+// interface using Postgres transactions.
+type DatabaseTransactor struct {
+	db *sqlx.DB
+}
+
+// WithinTransaction runs fn in a transaction, which is committed if fn
+// returns nil and rolled back otherwise. Calls within a transaction join it.
+func (t *DatabaseTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTransaction(ctx, t.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		return fn(ctx)
+	})
+}
+
+// withinTransaction runs fn in the transaction of ctx, or in a new
+// transaction of db, which is committed if fn returns nil and rolled back
+// otherwise.
+func withinTransaction(ctx context.Context, db *sqlx.DB, fn func(ctx context.Context, tx *sqlx.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx, tx)
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(context.WithValue(ctx, txKey{}, tx), tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// conn returns the transaction of ctx if the call takes part in one, db otherwise.
+func conn(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// NewDatabaseTransactor returns a new DatabaseTransactor with the provided database as SQL backend.
+func NewDatabaseTransactor(db *sqlx.DB) *DatabaseTransactor {
+	return &DatabaseTransactor{db: db}
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestDatabaseTransactor_WithinTransaction(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	transactor := NewDatabaseTransactor(db)
+	carCRUDService := NewDatabaseCarCRUDService(db)
+	customerCRUDService := NewDatabaseCustomerCRUDService(db)
+
+	t.Run("commit", func(t *testing.T) {
+		var carID, customerID int
+		err := transactor.WithinTransaction(testCtx, func(ctx context.Context) error {
+			var err error
+			if carID, err = carCRUDService.Create(ctx, rental.Car{Make: "Toyota", Model: "Corolla", Year: 2015}); err != nil {
+				return err
+			}
+			customerID, err = customerCRUDService.Create(ctx, rental.Customer{Name: "John Doe"})
+			return err
+		})
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if _, err := carCRUDService.Get(testCtx, carID); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if _, err := customerCRUDService.Get(testCtx, customerID); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	})
+	t.Run("rollback", func(t *testing.T) {
+		errFailed := fmt.Errorf("failed")
+		var carID int
+		err := transactor.WithinTransaction(testCtx, func(ctx context.Context) error {
+			var err error
+			if carID, err = carCRUDService.Create(ctx, rental.Car{Make: "Ford", Model: "Fiesta", Year: 2016}); err != nil {
+				return err
+			}
+			if _, err := carCRUDService.Get(ctx, carID); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		if err != errFailed {
+			t.Errorf("got error %v, want %v", err, errFailed)
+		}
+		if _, err := carCRUDService.Get(testCtx, carID); err != rental.ErrCarNotFound {
+			t.Errorf("got error %v, want %v", err, rental.ErrCarNotFound)
+		}
+	})
+}
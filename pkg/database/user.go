@@ -0,0 +1,124 @@
+// Package database implements the database layer of the rental service.
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// uniqueViolation is the Postgres error code raised when a unique constraint is violated.
+const uniqueViolation = "23505"
+
+// foreignKeyViolation is the Postgres error code raised when a foreign key constraint is violated.
+const foreignKeyViolation = "23503"
+
+// isUniqueViolation returns true if err was caused by a unique constraint violation.
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == uniqueViolation
+}
+
+// isForeignKeyViolation returns true if err was caused by a foreign key
+// constraint violation, eg. deleting a row still referenced.
+func isForeignKeyViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == foreignKeyViolation
+}
+
+// DatabaseUserCRUDService is a concrete implementation of the UserCRUDService
+// interface using Postgres as a backend.
+type DatabaseUserCRUDService struct {
+	db *sqlx.DB
+}
+
+// Create creates a user in the database, returns id.
+func (s *DatabaseUserCRUDService) Create(ctx context.Context, user rental.User) (id int, err error) {
+	user.TenantID, err = tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	insertStatement := "INSERT INTO users (tenant_id, username, password_hash, role, customer_id) VALUES (:tenant_id, :username, :password_hash, :role, :customer_id) RETURNING id"
+	rows, err := sqlx.NamedQueryContext(ctx, s.db, insertStatement, user)
+	if isUniqueViolation(err) {
+		return 0, rental.ErrUserAlreadyExists
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		err = rows.Scan(&id)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return id, err
+}
+
+// Get fetches a user from the database.
+func (s *DatabaseUserCRUDService) Get(ctx context.Context, id int) (rental.User, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return rental.User{}, err
+	}
+	var user rental.User
+	err = sqlx.GetContext(ctx, s.db, &user, "SELECT * FROM users WHERE id = $1 AND tenant_id = $2", id, tenantID)
+	if err == sql.ErrNoRows {
+		return rental.User{}, rental.ErrUserNotFound
+	}
+	return user, err
+}
+
+// GetByUsername fetches a user from the database by username, whatever its tenant.
+func (s *DatabaseUserCRUDService) GetByUsername(ctx context.Context, username string) (rental.User, error) {
+	var user rental.User
+	err := sqlx.GetContext(ctx, s.db, &user, "SELECT * FROM users WHERE username = $1 LIMIT 1", username)
+	if err == sql.ErrNoRows {
+		return rental.User{}, rental.ErrUserNotFound
+	}
+	return user, err
+}
+
+// List fetches all users from the database.
+func (s *DatabaseUserCRUDService) List(ctx context.Context) ([]rental.User, error) {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users := []rental.User{}
+	err = sqlx.SelectContext(ctx, s.db, &users, "SELECT * FROM users WHERE tenant_id = $1 ORDER BY id", tenantID)
+	return users, err
+}
+
+// Update updates a user in the database.
+func (s *DatabaseUserCRUDService) Update(ctx context.Context, user rental.User) (err error) {
+	user.TenantID, err = tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	updateStatement := "UPDATE users SET username = :username, password_hash = :password_hash, role = :role, customer_id = :customer_id WHERE id = :id AND tenant_id = :tenant_id"
+	_, err = sqlx.NamedExecContext(ctx, s.db, updateStatement, user)
+	if isUniqueViolation(err) {
+		return rental.ErrUserAlreadyExists
+	}
+	return err
+}
+
+// Delete deletes a user from the database.
+func (s *DatabaseUserCRUDService) Delete(ctx context.Context, userID int) error {
+	tenantID, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, "DELETE FROM users WHERE id = $1 AND tenant_id = $2", userID, tenantID)
+	return err
+}
+
+// NewDatabaseUserCRUDService returns a new DatabaseUserCRUDService with the provided database as SQL backend.
+func NewDatabaseUserCRUDService(db *sqlx.DB) *DatabaseUserCRUDService {
+	return &DatabaseUserCRUDService{db: db}
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestDatabaseUserCRUDService_Get(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	t.Run("get a user", func(t *testing.T) {
+		userCRUDService := NewDatabaseUserCRUDService(db)
+		user := rental.User{ID: 1, TenantID: rental.DefaultTenantID, Username: "jane", PasswordHash: "hash", Role: rental.RoleAgent}
+		_, err := userCRUDService.Create(testCtx, user)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		got, err := userCRUDService.Get(testCtx, 1)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got != user {
+			t.Errorf("got %v, want %v", got, user)
+		}
+		got, err = userCRUDService.GetByUsername(testCtx, "jane")
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if got != user {
+			t.Errorf("got %v, want %v", got, user)
+		}
+	})
+	t.Run("get a non-existent user", func(t *testing.T) {
+		userCRUDService := NewDatabaseUserCRUDService(db)
+		_, err := userCRUDService.GetByUsername(testCtx, "john")
+		if err != rental.ErrUserNotFound {
+			t.Errorf(fmt.Sprintf("got error %v, want %v", err, rental.ErrUserNotFound))
+		}
+	})
+}
+
+func TestDatabaseUserCRUDService_Create(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	userCRUDService := NewDatabaseUserCRUDService(db)
+	user := rental.User{ID: 1, TenantID: rental.DefaultTenantID, Username: "jane", PasswordHash: "hash", Role: rental.RoleAgent}
+	_, err := userCRUDService.Create(testCtx, user)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	_, err = userCRUDService.Create(testCtx, user)
+	if err != rental.ErrUserAlreadyExists {
+		t.Errorf("got error %v, want %v", err, rental.ErrUserAlreadyExists)
+	}
+
+	got, err := userCRUDService.List(testCtx)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if len(got) != 1 || got[0] != user {
+		t.Errorf("got %v, want [%v]", got, user)
+	}
+}
+
+func TestDatabaseUserCRUDService_Update(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	userCRUDService := NewDatabaseUserCRUDService(db)
+	user := rental.User{ID: 1, TenantID: rental.DefaultTenantID, Username: "jane", PasswordHash: "hash", Role: rental.RoleAgent}
+	_, err := userCRUDService.Create(testCtx, user)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	user.Role = rental.RoleAdmin
+	err = userCRUDService.Update(testCtx, user)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	got, err := userCRUDService.Get(testCtx, 1)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if got != user {
+		t.Errorf("got %v, want %v", got, user)
+	}
+}
+
+func TestDatabaseUserCRUDService_Delete(t *testing.T) {
+	setupTestDatabase()
+	defer teardownTestDatabase()
+
+	db := sqlx.MustConnect("postgres", testDatabaseURL)
+	userCRUDService := NewDatabaseUserCRUDService(db)
+	user := rental.User{ID: 1, TenantID: rental.DefaultTenantID, Username: "jane", PasswordHash: "hash", Role: rental.RoleAgent}
+	_, err := userCRUDService.Create(testCtx, user)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	err = userCRUDService.Delete(testCtx, 1)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+
+	_, err = userCRUDService.Get(testCtx, 1)
+	if err != rental.ErrUserNotFound {
+		t.Errorf(fmt.Sprintf("got error %v, want %v", err, rental.ErrUserNotFound))
+	}
+}
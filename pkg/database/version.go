@@ -0,0 +1,28 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// checkVersion returns errMismatch if the versioned update or delete of the row
+// id of table in a tenant didn't affect any row although the row exists, ie.
+// the row was modified since the version was read, or errNotFound if the row
+// doesn't exist, ie. it was deleted since it was read.
+func checkVersion(ctx context.Context, db sqlx.QueryerContext, res sql.Result, table string, id, tenantID int, errMismatch, errNotFound error) error {
+	affected, err := res.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+	var exists bool
+	err = db.QueryRowxContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1 AND tenant_id = $2)", id, tenantID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errMismatch
+	}
+	return errNotFound
+}
@@ -0,0 +1,177 @@
+// Package events implements an in-memory broker fanning out car status
+// changes to the subscribers of the event stream.
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// Type is the kind of change described by an Event.
+type Type string
+
+const (
+	CarCreated  Type = "car.created"
+	CarDeleted  Type = "car.deleted"
+	CarRented   Type = "car.rented"
+	CarReturned Type = "car.returned"
+	// CarsImported summarizes the cars created by a bulk import, rather than
+	// flooding subscribers with an event per car.
+	CarsImported Type = "cars.imported"
+)
+
+// Event represents a change in the status of a car.
+type Event struct {
+	ID       uint64
+	TenantID int
+	Type     Type
+	Car      rental.Car
+	// CarIDs are the cars created by a CarsImported event, which has no Car.
+	CarIDs []int
+	Time   time.Time
+}
+
+var (
+	ErrBrokerClosed = fmt.Errorf("Event broker closed")
+	// ErrHistoryExpired is returned when resuming after an event followed by
+	// events no longer held in history, the subscriber must resync its state.
+	ErrHistoryExpired = fmt.Errorf("Events since the last event ID expired")
+)
+
+// Broker dispatches published events to its subscribers, and keeps the most
+// recent ones in memory so that reconnecting clients can resume from the last
+// event they received.
+type Broker struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Event
+	historySize int
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// Subscription is a stream of events delivered to a single subscriber.
+type Subscription struct {
+	events chan Event
+	broker *Broker
+}
+
+// Events returns the channel on which events are delivered. The channel is
+// closed when the subscription is closed, either by the subscriber, by the
+// broker shutting down, or because the subscriber fell too far behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close unsubscribes from the broker.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.unsubscribe(s)
+}
+
+// Publish records an event of the given type for a car of the tenant tenantID
+// and dispatches it to all subscribers, which filter the events of their tenant. Publish never blocks: subscribers whose buffer is full are
+// disconnected, and are expected to reconnect with the ID of the last event
+// they received.
+func (b *Broker) Publish(tenantID int, eventType Type, car rental.Car) {
+	b.publish(Event{TenantID: tenantID, Type: eventType, Car: car})
+}
+
+// PublishImported records a CarsImported event for the cars carIDs of the
+// tenant tenantID and dispatches it to all subscribers, as Publish does.
+func (b *Broker) PublishImported(tenantID int, carIDs []int) {
+	b.publish(Event{TenantID: tenantID, Type: CarsImported, CarIDs: carIDs})
+}
+
+// publish numbers and timestamps event, then records and dispatches it.
+func (b *Broker) publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	b.lastID++
+	event.ID, event.Time = b.lastID, time.Now().UTC()
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for subscription := range b.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			b.unsubscribe(subscription)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber and returns the events published after
+// lastEventID, or ErrHistoryExpired if some of them are no longer held in
+// history. A lastEventID of 0 means the subscriber is only interested in new
+// events.
+func (b *Broker) Subscribe(lastEventID uint64) (*Subscription, []Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, nil, ErrBrokerClosed
+	}
+	oldestID := b.lastID + 1
+	if len(b.history) > 0 {
+		oldestID = b.history[0].ID
+	}
+	if lastEventID != 0 && lastEventID <= b.lastID && lastEventID+1 < oldestID {
+		return nil, nil, ErrHistoryExpired
+	}
+
+	var backlog []Event
+	if lastEventID != 0 {
+		for _, event := range b.history {
+			// An ID we never issued comes from a previous run of the service,
+			// in which case the whole history is unknown to the subscriber.
+			if event.ID > lastEventID || lastEventID > b.lastID {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	subscription := &Subscription{events: make(chan Event, b.bufferSize), broker: b}
+	b.subscribers[subscription] = struct{}{}
+	return subscription, backlog, nil
+}
+
+// Close disconnects all subscribers and stops accepting new ones.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for subscription := range b.subscribers {
+		b.unsubscribe(subscription)
+	}
+}
+
+// unsubscribe removes a subscription and closes its channel, b.mu must be held.
+func (b *Broker) unsubscribe(subscription *Subscription) {
+	if _, ok := b.subscribers[subscription]; !ok {
+		return
+	}
+	delete(b.subscribers, subscription)
+	close(subscription.events)
+}
+
+// NewBroker returns a new Broker keeping the last historySize events, and
+// buffering up to bufferSize events per subscriber.
+func NewBroker(historySize, bufferSize int) *Broker {
+	return &Broker{
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: map[*Subscription]struct{}{},
+	}
+}
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+func TestBroker_Publish(t *testing.T) {
+	t.Run("deliver an event to subscribers", func(t *testing.T) {
+		broker := NewBroker(10, 10)
+		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+		subscription, backlog, err := broker.Subscribe(0)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(backlog) != 0 {
+			t.Errorf("got %d backlog events, want 0", len(backlog))
+		}
+
+		broker.Publish(1, CarRented, car)
+
+		event := <-subscription.Events()
+		if event.ID != 1 || event.Type != CarRented || event.Car != car {
+			t.Errorf("got %v, want event 1 of type %s for car %v", event, CarRented, car)
+		}
+	})
+	t.Run("deliver a summary of imported cars", func(t *testing.T) {
+		broker := NewBroker(10, 10)
+		subscription, _, err := broker.Subscribe(0)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+
+		broker.PublishImported(1, []int{1, 2})
+
+		event := <-subscription.Events()
+		if event.ID != 1 || event.Type != CarsImported || len(event.CarIDs) != 2 {
+			t.Errorf("got %v, want event 1 of type %s for cars 1 and 2", event, CarsImported)
+		}
+	})
+	t.Run("disconnect a slow subscriber", func(t *testing.T) {
+		broker := NewBroker(10, 1)
+		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+		subscription, _, err := broker.Subscribe(0)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+
+		broker.Publish(1, CarRented, car)
+		broker.Publish(1, CarReturned, car)
+
+		if _, ok := <-subscription.Events(); !ok {
+			t.Errorf("got closed channel, want buffered event")
+		}
+		if _, ok := <-subscription.Events(); ok {
+			t.Errorf("got event, want closed channel")
+		}
+	})
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	t.Run("resume from the last event ID", func(t *testing.T) {
+		broker := NewBroker(10, 10)
+		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+		broker.Publish(1, CarCreated, car)
+		broker.Publish(1, CarRented, car)
+		broker.Publish(1, CarReturned, car)
+
+		_, backlog, err := broker.Subscribe(1)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(backlog) != 2 || backlog[0].ID != 2 || backlog[1].ID != 3 {
+			t.Errorf("got %v, want events 2 and 3", backlog)
+		}
+	})
+	t.Run("resume from the event before the oldest in history", func(t *testing.T) {
+		broker := NewBroker(2, 10)
+		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+		broker.Publish(1, CarCreated, car)
+		broker.Publish(1, CarRented, car)
+		broker.Publish(1, CarReturned, car)
+		broker.Publish(1, CarDeleted, car)
+
+		_, backlog, err := broker.Subscribe(2)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(backlog) != 2 || backlog[0].ID != 3 || backlog[1].ID != 4 {
+			t.Errorf("got %v, want events 3 and 4", backlog)
+		}
+	})
+	t.Run("resume from an event that fell out of history", func(t *testing.T) {
+		broker := NewBroker(2, 10)
+		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+		broker.Publish(1, CarCreated, car)
+		broker.Publish(1, CarRented, car)
+		broker.Publish(1, CarReturned, car)
+		broker.Publish(1, CarDeleted, car)
+
+		// Event 2 was trimmed from history, it would be lost
+		_, _, err := broker.Subscribe(1)
+		if err != ErrHistoryExpired {
+			t.Errorf("got error %v, want %v", err, ErrHistoryExpired)
+		}
+	})
+	t.Run("resume from an unknown event ID", func(t *testing.T) {
+		broker := NewBroker(10, 10)
+		car := rental.Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+		broker.Publish(1, CarCreated, car)
+
+		_, backlog, err := broker.Subscribe(100)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if len(backlog) != 1 {
+			t.Errorf("got %d backlog events, want 1", len(backlog))
+		}
+	})
+	t.Run("subscribe to a closed broker", func(t *testing.T) {
+		broker := NewBroker(10, 10)
+		broker.Close()
+		_, _, err := broker.Subscribe(0)
+		if err != ErrBrokerClosed {
+			t.Errorf("got error %v, want %v", err, ErrBrokerClosed)
+		}
+	})
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := NewBroker(10, 10)
+	subscription, _, err := broker.Subscribe(0)
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	broker.Close()
+	if _, ok := <-subscription.Events(); ok {
+		t.Errorf("got event, want closed channel")
+	}
+	// Closing a subscription after the broker is closed must not panic.
+	subscription.Close()
+}
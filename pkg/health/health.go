@@ -0,0 +1,154 @@
+// Package health reports the liveness and readiness of the rental service to
+// Kubernetes probes, readiness depending on the health of its dependencies.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Statuses of the service and of its checks.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Checker checks the health of a dependency of the service.
+type Checker interface {
+	// Check returns an error if the dependency is unhealthy. It must return
+	// once ctx is done.
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc is a function implementing Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f.
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult is the result of a check, in the readiness report.
+type CheckResult struct {
+	Status string `json:"status"`
+	// Duration is the duration of the check, in milliseconds.
+	Duration int64  `json:"duration_ms"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the readiness report of the service.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// shutdownCheck is the check failing once the service is shutting down.
+const shutdownCheck = "shutdown"
+
+// Health runs the checks of the dependencies of the service.
+type Health struct {
+	timeout  time.Duration
+	draining atomic.Bool
+
+	mu       sync.RWMutex
+	checkers map[string]Checker
+}
+
+// New returns a Health without checks, each check registered timing out
+// after timeout.
+func New(timeout time.Duration) *Health {
+	return &Health{timeout: timeout, checkers: map[string]Checker{}}
+}
+
+// Register registers the check name of a dependency the service needs to
+// serve requests.
+func (h *Health) Register(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers[name] = checker
+}
+
+// Drain makes the service unready, so that it stops receiving requests
+// before shutting down.
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// Check runs the checks concurrently and returns their report, whose status
+// is StatusOK if every check passed and the service isn't shutting down.
+func (h *Health) Check(ctx context.Context) Report {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	report := Report{Status: StatusOK, Checks: map[string]CheckResult{}}
+	if h.draining.Load() {
+		report.Status = StatusUnavailable
+		report.Checks[shutdownCheck] = CheckResult{Status: StatusUnavailable, Error: "Shutting down"}
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, checker := range h.checkers {
+		wg.Add(1)
+		go func(name string, checker Checker) {
+			defer wg.Done()
+			start := time.Now()
+			err := check(ctx, checker)
+			result := CheckResult{Status: StatusOK, Duration: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status, result.Error = StatusUnavailable, err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if err != nil {
+				report.Status = StatusUnavailable
+			}
+		}(name, checker)
+	}
+	wg.Wait()
+	return report
+}
+
+// check runs checker, returning an error once ctx is done even if checker
+// ignores it.
+func check(ctx context.Context, checker Checker) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- checker.Check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("Check timed out: %w", ctx.Err())
+	}
+}
+
+// Livez reports that the process is alive, regardless of its dependencies,
+// so that Kubernetes restarts it only if it is stuck.
+// (GET /livez)
+func (h *Health) Livez(c echo.Context) error {
+	return c.JSON(http.StatusOK, Report{Status: StatusOK, Checks: map[string]CheckResult{}})
+}
+
+// Readyz reports whether the service can serve requests, with the report of
+// its checks, so that Kubernetes stops routing requests to it otherwise.
+// (GET /readyz)
+func (h *Health) Readyz(c echo.Context) error {
+	report := h.Check(c.Request().Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, report)
+}
@@ -0,0 +1,167 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestHealth_Check(t *testing.T) {
+	ok := CheckerFunc(func(ctx context.Context) error { return nil })
+	failing := CheckerFunc(func(ctx context.Context) error { return fmt.Errorf("connection refused") })
+	stuck := CheckerFunc(func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	tests := []struct {
+		name       string
+		checkers   map[string]Checker
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{name: "no checks", checkers: map[string]Checker{}, wantStatus: StatusOK, wantChecks: map[string]string{}},
+		{
+			name:       "healthy",
+			checkers:   map[string]Checker{"database": ok, "migrations": ok},
+			wantStatus: StatusOK,
+			wantChecks: map[string]string{"database": StatusOK, "migrations": StatusOK},
+		},
+		{
+			name:       "failing check",
+			checkers:   map[string]Checker{"database": failing, "migrations": ok},
+			wantStatus: StatusUnavailable,
+			wantChecks: map[string]string{"database": StatusUnavailable, "migrations": StatusOK},
+		},
+		{
+			name:       "check timing out",
+			checkers:   map[string]Checker{"database": stuck},
+			wantStatus: StatusUnavailable,
+			wantChecks: map[string]string{"database": StatusUnavailable},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(50 * time.Millisecond)
+			for name, checker := range tt.checkers {
+				h.Register(name, checker)
+			}
+
+			report := h.Check(context.Background())
+			if report.Status != tt.wantStatus {
+				t.Errorf("got status %s, want %s", report.Status, tt.wantStatus)
+			}
+			if len(report.Checks) != len(tt.wantChecks) {
+				t.Errorf("got checks %v, want %v", report.Checks, tt.wantChecks)
+			}
+			for name, want := range tt.wantChecks {
+				result := report.Checks[name]
+				if result.Status != want {
+					t.Errorf("got check %s status %s, want %s", name, result.Status, want)
+				}
+				if (result.Error != "") != (want != StatusOK) {
+					t.Errorf("got check %s error %q, want error %t", name, result.Error, want != StatusOK)
+				}
+			}
+		})
+	}
+}
+
+func TestHealth_Readyz(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "ready", wantCode: http.StatusOK},
+		{name: "not ready", err: fmt.Errorf("connection refused"), wantCode: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			h := New(time.Second)
+			h.Register("database", CheckerFunc(func(ctx context.Context) error { return tt.err }))
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			if err := h.Readyz(echo.New().NewContext(req, resp)); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("got %d status code, want %d", resp.Code, tt.wantCode)
+			}
+			var report Report
+			if err := json.Unmarshal(resp.Body.Bytes(), &report); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			if _, ok := report.Checks["database"]; !ok {
+				t.Errorf("got checks %v, want database check", report.Checks)
+			}
+		})
+	}
+}
+
+func TestHealth_Drain(t *testing.T) {
+	h := New(time.Second)
+	h.Register("database", CheckerFunc(func(ctx context.Context) error { return nil }))
+
+	h.Drain()
+	report := h.Check(context.Background())
+	if report.Status != StatusUnavailable {
+		t.Errorf("got status %s while draining, want %s", report.Status, StatusUnavailable)
+	}
+	if got := report.Checks[shutdownCheck].Status; got != StatusUnavailable {
+		t.Errorf("got check %s status %q, want %s", shutdownCheck, got, StatusUnavailable)
+	}
+	if got := report.Checks["database"].Status; got != StatusOK {
+		t.Errorf("got check database status %q, want %s", got, StatusOK)
+	}
+}
+
+func TestHealth_Livez(t *testing.T) {
+	h := New(time.Second)
+	h.Register("database", CheckerFunc(func(ctx context.Context) error { return fmt.Errorf("connection refused") }))
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	resp := httptest.NewRecorder()
+
+	if err := h.Livez(echo.New().NewContext(req, resp)); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if resp.Code != http.StatusOK {
+		t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+	}
+}
+
+func TestHTTPChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "healthy", status: http.StatusOK},
+		{name: "client error", status: http.StatusNotFound},
+		{name: "server error", status: http.StatusBadGateway, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := HTTPChecker(server.Client(), server.URL).Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
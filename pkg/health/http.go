@@ -0,0 +1,27 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// HTTPChecker returns a Checker of an outbound HTTP dependency, healthy if
+// it responds to a GET of url without a server error.
+func HTTPChecker(client *http.Client, url string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("GET %s responded %s", url, resp.Status)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,198 @@
+// Package idempotency implements idempotency keys, which let clients safely
+// retry the mutating requests of the rental API.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/shidenkai0/rental/pkg/auth"
+	"github.com/shidenkai0/rental/pkg/rental"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	// HeaderIdempotencyKey is the header carrying the idempotency key chosen by the client.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on the responses replayed for a retried request.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	// maxKeyLength is the maximum length of idempotency keys.
+	maxKeyLength = 255
+)
+
+// Config defines the config for the Idempotency middleware.
+type Config struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper middleware.Skipper
+	// Keys stores the idempotency keys and the recorded responses.
+	Keys rental.IdempotencyKeyService
+	// TTL is the time during which a key is remembered, retries after that
+	// perform the operation again.
+	TTL time.Duration
+	// IsMutating returns true if the request performs a mutating operation,
+	// it defaults to requests with an unsafe method, eg. POST.
+	IsMutating func(c echo.Context) bool
+}
+
+// Idempotency returns a middleware recording the response of mutating
+// requests carrying an Idempotency-Key header, and replaying it when the
+// client retries the request with the same key. Keys are scoped to the
+// principal, and reusing a key for a different request is rejected with a
+// 422 Unprocessable Entity. Retries arriving while the request is still
+// being processed are rejected with a 409 Conflict.
+//
+// Server errors aren't recorded, so that the request can be retried. The
+// middleware must be installed after Authenticate and ResolveTenant, requests
+// without a principal are served without idempotency.
+func Idempotency(config Config) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+	if config.IsMutating == nil {
+		config.IsMutating = func(c echo.Context) bool {
+			method := c.Request().Method
+			return method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			idempotencyKey := req.Header.Get(HeaderIdempotencyKey)
+			if config.Skipper(c) || idempotencyKey == "" || !config.IsMutating(c) {
+				return next(c)
+			}
+			principal, ok := auth.PrincipalFrom(c)
+			if !ok {
+				return next(c)
+			}
+			if len(idempotencyKey) > maxKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, "Idempotency-Key must be at most 255 characters long")
+			}
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			// The outcome is recorded even if the client goes away in the
+			// meantime, as it is about to retry. The context keeps the tenant,
+			// request ID and trace of the request.
+			ctx := context.WithoutCancel(req.Context())
+
+			key := rental.IdempotencyKey{
+				Client:      principal.Subject,
+				Key:         idempotencyKey,
+				Fingerprint: fingerprint(req, body),
+				ExpiresAt:   time.Now().Add(config.TTL),
+			}
+			key.ID, err = config.Keys.Create(ctx, key)
+			if err == rental.ErrIdempotencyKeyAlreadyExists {
+				return retry(c, config.Keys, key)
+			}
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+
+			res := c.Response()
+			headersBefore := res.Header().Clone()
+			recorder := &bodyRecorder{ResponseWriter: res.Writer}
+			res.Writer = recorder
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			res.Writer = recorder.ResponseWriter
+
+			if res.Status >= http.StatusInternalServerError {
+				if err := config.Keys.Delete(ctx, key.ID); err != nil {
+					slog.ErrorContext(ctx, "deleting idempotency key", "idempotency_key_id", key.ID, "error", err)
+				}
+				return nil
+			}
+			key.StatusCode = null.IntFrom(int64(res.Status))
+			key.ResponseHeaders, err = json.Marshal(headersSet(headersBefore, res.Header()))
+			if err == nil {
+				key.ResponseBody = recorder.body.Bytes()
+				err = config.Keys.Complete(ctx, key)
+			}
+			if err != nil {
+				slog.ErrorContext(ctx, "recording response of idempotency key", "idempotency_key_id", key.ID, "error", err)
+			}
+			return nil
+		}
+	}
+}
+
+// retry handles a request whose idempotency key was already used by the
+// client, replaying the recorded response if the request is the same.
+func retry(c echo.Context, keys rental.IdempotencyKeyService, key rental.IdempotencyKey) error {
+	existing, err := keys.Get(c.Request().Context(), key.Client, key.Key)
+	if err == rental.ErrIdempotencyKeyNotFound {
+		// The key was deleted after a server error in the meantime
+		return echo.NewHTTPError(http.StatusConflict, "A request with this Idempotency-Key is being processed")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if existing.Fingerprint != key.Fingerprint {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "Idempotency-Key was used for a different request")
+	}
+	if !existing.Completed() {
+		return echo.NewHTTPError(http.StatusConflict, "A request with this Idempotency-Key is being processed")
+	}
+
+	var headers http.Header
+	if err := json.Unmarshal(existing.ResponseHeaders, &headers); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	res := c.Response()
+	for name, values := range headers {
+		res.Header()[name] = values
+	}
+	res.Header().Set(HeaderIdempotentReplayed, "true")
+	res.WriteHeader(int(existing.StatusCode.Int64))
+	_, err = res.Write(existing.ResponseBody)
+	return err
+}
+
+// fingerprint returns a hash identifying a request by method, URI and body.
+func fingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, req.Method+" "+req.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// headersSet returns the headers of after that are not in before, ie. the
+// headers set while handling the request rather than by earlier middleware.
+func headersSet(before, after http.Header) http.Header {
+	headers := http.Header{}
+	for name, values := range after {
+		if !reflect.DeepEqual(before[name], values) {
+			headers[name] = values
+		}
+	}
+	return headers
+}
+
+// bodyRecorder copies the response body as it is written.
+type bodyRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
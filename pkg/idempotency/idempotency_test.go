@@ -0,0 +1,123 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/auth"
+)
+
+func TestIdempotency(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	calls := 0
+	failing := false
+	handler := func(c echo.Context) error {
+		calls++
+		if failing {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failure")
+		}
+		c.Response().Header().Set("X-Call", strconv.Itoa(calls))
+		return c.String(http.StatusCreated, "call "+strconv.Itoa(calls))
+	}
+	setPrincipal := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if subject := c.Request().Header.Get("X-Subject"); subject != "" {
+				auth.SetPrincipal(c, auth.Principal{Subject: subject})
+			}
+			return next(c)
+		}
+	}
+	e.POST("/car", handler, setPrincipal, Idempotency(Config{
+		Keys: mock.NewMockIdempotencyKeyService(),
+		TTL:  time.Hour,
+	}))
+
+	request := func(subject, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/car", strings.NewReader(body))
+		if subject != "" {
+			req.Header.Set("X-Subject", subject)
+		}
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Test
+
+	t.Run("retry replays response", func(t *testing.T) {
+		first := request("jane", "key-1", `{"model":"Clio"}`)
+		if first.Code != http.StatusCreated {
+			t.Fatalf("got %d status code, want %d", first.Code, http.StatusCreated)
+		}
+		callsBefore := calls
+		resp := request("jane", "key-1", `{"model":"Clio"}`)
+		if calls != callsBefore {
+			t.Errorf("handler called on retry")
+		}
+		if resp.Code != http.StatusCreated {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusCreated)
+		}
+		if resp.Body.String() != first.Body.String() {
+			t.Errorf("got body %q, want %q", resp.Body.String(), first.Body.String())
+		}
+		if got, want := resp.Header().Get("X-Call"), first.Header().Get("X-Call"); got != want {
+			t.Errorf("got header %s, want %s", got, want)
+		}
+		if got := resp.Header().Get(HeaderIdempotentReplayed); got != "true" {
+			t.Errorf("got %s header %q, want true", HeaderIdempotentReplayed, got)
+		}
+	})
+	t.Run("key reused for a different request", func(t *testing.T) {
+		request("jane", "key-2", `{"model":"Clio"}`)
+		resp := request("jane", "key-2", `{"model":"Twingo"}`)
+		if resp.Code != http.StatusUnprocessableEntity {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusUnprocessableEntity)
+		}
+	})
+	t.Run("keys are scoped to the principal", func(t *testing.T) {
+		request("jane", "key-3", `{}`)
+		callsBefore := calls
+		resp := request("john", "key-3", `{}`)
+		if calls != callsBefore+1 {
+			t.Errorf("handler not called for another principal")
+		}
+		if got := resp.Header().Get(HeaderIdempotentReplayed); got != "" {
+			t.Errorf("got %s header %q, want none", HeaderIdempotentReplayed, got)
+		}
+	})
+	t.Run("server errors are not recorded", func(t *testing.T) {
+		failing = true
+		if resp := request("jane", "key-4", `{}`); resp.Code != http.StatusInternalServerError {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusInternalServerError)
+		}
+		failing = false
+		if resp := request("jane", "key-4", `{}`); resp.Code != http.StatusCreated {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusCreated)
+		}
+	})
+	t.Run("requests without key are not recorded", func(t *testing.T) {
+		callsBefore := calls
+		request("jane", "", `{}`)
+		request("jane", "", `{}`)
+		if calls != callsBefore+2 {
+			t.Errorf("got %d handler calls, want 2", calls-callsBefore)
+		}
+	})
+	t.Run("key too long", func(t *testing.T) {
+		resp := request("jane", strings.Repeat("k", maxKeyLength+1), `{}`)
+		if resp.Code != http.StatusBadRequest {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		wantID string
+	}{
+		{name: "ID of the client", header: "4f1c9a", wantID: "4f1c9a"},
+		{name: "no ID", header: ""},
+		{name: "invalid ID", header: "line\nbreak"},
+		{name: "ID too long", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			var contextID string
+			e := echo.New()
+			e.GET("/v1/car", func(c echo.Context) error {
+				contextID = RequestID(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			}, RequestIDMiddleware())
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/car", nil)
+			req.Header.Set(echo.HeaderXRequestID, tt.header)
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			e.ServeHTTP(resp, req)
+			got := resp.Header().Get(echo.HeaderXRequestID)
+			if tt.wantID != "" && got != tt.wantID {
+				t.Errorf("got request ID %q, want %q", got, tt.wantID)
+			}
+			if tt.wantID == "" && (got == "" || got == tt.header) {
+				t.Errorf("got request ID %q, want a generated ID", got)
+			}
+			if contextID != got {
+				t.Errorf("got request ID %q in the context, want %q", contextID, got)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	// Setup
+
+	var buf bytes.Buffer
+	e := echo.New()
+	e.GET("/v1/car/:carId", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database unreachable")
+	}, RequestIDMiddleware(), Middleware(New(&buf, slog.LevelInfo)))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/car/1", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+	resp := httptest.NewRecorder()
+
+	// Test
+
+	e.ServeHTTP(resp, req)
+	if resp.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want %d", resp.Code, http.StatusInternalServerError)
+	}
+	line := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("got error %v, want JSON line", err)
+	}
+	want := map[string]interface{}{
+		"level":      "ERROR",
+		"msg":        "request",
+		"request_id": "req-1",
+		"method":     http.MethodGet,
+		"route":      "/v1/car/:carId",
+		"path":       "/v1/car/1",
+		"status":     float64(http.StatusInternalServerError),
+	}
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("got %s %v, want %v", key, line[key], value)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package metrics exposes Prometheus metrics of the HTTP requests and of the
+// domain operations of the rental service.
+package metrics
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace prefixes the names of the metrics of the rental service.
+const namespace = "rental"
+
+// unknownOperation labels the requests to routes missing from the API spec.
+const unknownOperation = "unknown"
+
+// Metrics holds the collectors of the rental service. A nil *Metrics is
+// valid and records nothing, so that metrics are optional.
+type Metrics struct {
+	requestDuration *prometheus.HistogramVec
+	requests        *prometheus.CounterVec
+	rentalsStarted  prometheus.Counter
+	rentalsReturned prometheus.Counter
+	rentConflicts   prometheus.Counter
+}
+
+// New returns Metrics whose collectors are registered with registerer.
+func New(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of the API requests, by version and operation.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"version", "operation"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of API requests, by version, operation and status code.",
+		}, []string{"version", "operation", "code"}),
+		rentalsStarted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rentals_started_total",
+			Help:      "Number of cars rented.",
+		}),
+		rentalsReturned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rentals_returned_total",
+			Help:      "Number of rented cars returned.",
+		}),
+		rentConflicts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rent_conflicts_total",
+			Help:      "Number of rentals refused because the car was already rented or modified concurrently.",
+		}),
+	}
+	registerer.MustRegister(m.requestDuration, m.requests, m.rentalsStarted, m.rentalsReturned, m.rentConflicts)
+	return m
+}
+
+// RentalStarted counts a car rented.
+func (m *Metrics) RentalStarted() {
+	if m != nil {
+		m.rentalsStarted.Inc()
+	}
+}
+
+// RentalReturned counts a rented car returned.
+func (m *Metrics) RentalReturned() {
+	if m != nil {
+		m.rentalsReturned.Inc()
+	}
+}
+
+// RentConflict counts a rental refused because the car was already rented or modified concurrently.
+func (m *Metrics) RentConflict() {
+	if m != nil {
+		m.rentConflicts.Inc()
+	}
+}
+
+// Middleware returns a middleware measuring the latency and counting the
+// status codes of the requests to the operations of spec, labeled with their
+// operation ID, whose routes are served under baseURL as version. It must be
+// installed before the middleware rejecting requests, eg. authentication, so
+// that rejected requests are measured too.
+func (m *Metrics) Middleware(spec *openapi3.T, baseURL, version string) echo.MiddlewareFunc {
+	operations := map[string]string{}
+	for path, item := range spec.Paths {
+		route := baseURL + pathParameter.ReplaceAllString(path, ":$1")
+		for method, operation := range item.Operations() {
+			operations[method+" "+route] = operation.OperationID
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if m == nil {
+			return next
+		}
+		return func(c echo.Context) error {
+			start := time.Now()
+			// Handle the error here, so that the status code is known
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			operation, ok := operations[c.Request().Method+" "+c.Path()]
+			if !ok {
+				operation = unknownOperation
+			}
+			m.requestDuration.WithLabelValues(version, operation).Observe(time.Since(start).Seconds())
+			m.requests.WithLabelValues(version, operation, strconv.Itoa(c.Response().Status)).Inc()
+			return nil
+		}
+	}
+}
+
+// pathParameter matches the parameters of OpenAPI paths, eg. {carId}.
+var pathParameter = regexp.MustCompile(`\{([^}]+)\}`)
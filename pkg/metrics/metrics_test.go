@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+)
+
+func TestMetrics_Middleware(t *testing.T) {
+	swagger, err := gen.GetSwagger()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		routePath     string
+		handler       echo.HandlerFunc
+		wantOperation string
+		wantCode      string
+	}{
+		{
+			name:          "successful request",
+			method:        http.MethodGet,
+			routePath:     "/v1/car/:carId",
+			handler:       func(c echo.Context) error { return c.NoContent(http.StatusOK) },
+			wantOperation: "GetCarById",
+			wantCode:      "200",
+		},
+		{
+			name:          "failed request",
+			method:        http.MethodGet,
+			routePath:     "/v1/car/:carId/rent",
+			handler:       func(c echo.Context) error { return echo.NewHTTPError(http.StatusConflict, "Car already rented") },
+			wantOperation: "RentCar",
+			wantCode:      "409",
+		},
+		{
+			name:          "route missing from the spec",
+			method:        http.MethodGet,
+			routePath:     "/v1/unknown",
+			handler:       func(c echo.Context) error { return c.NoContent(http.StatusOK) },
+			wantOperation: unknownOperation,
+			wantCode:      "200",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			m := New(prometheus.NewRegistry())
+			e := echo.New()
+			e.Add(tt.method, tt.routePath, tt.handler, m.Middleware(swagger, "/v1", "v1"))
+
+			req := httptest.NewRequest(tt.method, tt.routePath, nil)
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			e.ServeHTTP(resp, req)
+			if got := testutil.ToFloat64(m.requests.WithLabelValues("v1", tt.wantOperation, tt.wantCode)); got != 1 {
+				t.Errorf("got %g requests of %s with code %s, want 1", got, tt.wantOperation, tt.wantCode)
+			}
+			if got := testutil.CollectAndCount(m.requestDuration); got != 1 {
+				t.Errorf("got %d latency histograms, want 1", got)
+			}
+		})
+	}
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+
+	// Test
+
+	m.RentalStarted()
+	m.RentalReturned()
+	m.RentConflict()
+
+	e := echo.New()
+	e.GET("/v1/car/:carId", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, m.Middleware(&openapi3.T{}, "/v1", "v1"))
+	resp := httptest.NewRecorder()
+	e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/car/1", nil))
+	if resp.Code != http.StatusOK {
+		t.Errorf("got status %d, want %d", resp.Code, http.StatusOK)
+	}
+}
+
+func TestMetrics_Rentals(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+
+	// Test
+
+	m.RentalStarted()
+	m.RentalStarted()
+	m.RentalReturned()
+	m.RentConflict()
+
+	if got := testutil.ToFloat64(m.rentalsStarted); got != 2 {
+		t.Errorf("got %g rentals started, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.rentalsReturned); got != 1 {
+		t.Errorf("got %g rentals returned, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.rentConflicts); got != 1 {
+		t.Errorf("got %g rent conflicts, want 1", got)
+	}
+}
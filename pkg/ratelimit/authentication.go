@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/shidenkai0/rental/pkg/auth"
+)
+
+// AuthenticationConfig defines the config for the ThrottleAuthentication middleware.
+type AuthenticationConfig struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper middleware.Skipper
+	// Failures is the budget of failed authentications of each IP address, a
+	// disabled limit doesn't restrict them.
+	Failures Limit
+}
+
+// ThrottleAuthentication returns a middleware limiting the rate of the failed
+// authentications of each client IP address, so that credentials can't be
+// brute forced. Once an address has exhausted its budget, its requests get a
+// 429 Too Many Requests response with a Retry-After header before being
+// authenticated, sparing the lookup and the hashing of their credentials.
+//
+// The middleware must be installed before Authenticate, every request
+// failing with 401 Unauthorized, or marked with auth.SetFailed, counting as a
+// failure. Failures are counted
+// in memory by each instance of the service, as the budget is checked before
+// every authentication.
+func ThrottleAuthentication(config AuthenticationConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+	store := NewMemoryStore()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) || !config.Failures.Enabled() {
+				return next(c)
+			}
+
+			key := "auth:ip:" + c.RealIP()
+			if result := store.Peek(key, config.Failures, time.Now()); !result.Allowed {
+				c.Response().Header().Set(echo.HeaderRetryAfter, seconds(result.RetryAfter))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "Too many failed authentications")
+			}
+
+			err := next(c)
+			status := c.Response().Status
+			if he, ok := err.(*echo.HTTPError); ok {
+				status = he.Code
+			}
+			if status == http.StatusUnauthorized || auth.Failed(c) {
+				store.Take(c.Request().Context(), key, config.Failures, time.Now())
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/auth"
+)
+
+func TestThrottleAuthentication(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	authenticated := 0
+	authenticate := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authenticated++
+			if c.Request().Header.Get("X-Password") != "correct horse" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid credentials")
+			}
+			return next(c)
+		}
+	}
+	e.GET("/car/:carId", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, ThrottleAuthentication(AuthenticationConfig{Failures: Limit{Rate: 1, Burst: 2}}), authenticate)
+
+	request := func(ip, password string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/car/1", nil)
+		req.Header.Set(echo.HeaderXRealIP, ip)
+		req.Header.Set("X-Password", password)
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Test
+
+	t.Run("successful authentications aren't counted", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			if resp := request("10.0.0.1", "correct horse"); resp.Code != http.StatusOK {
+				t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+			}
+		}
+	})
+	t.Run("failures beyond budget", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if resp := request("10.0.0.2", "hunter2"); resp.Code != http.StatusUnauthorized {
+				t.Errorf("got %d status code, want %d", resp.Code, http.StatusUnauthorized)
+			}
+		}
+		authenticated = 0
+		resp := request("10.0.0.2", "correct horse")
+		if resp.Code != http.StatusTooManyRequests {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusTooManyRequests)
+		}
+		if got := resp.Header().Get(echo.HeaderRetryAfter); got != "1" {
+			t.Errorf("got retry after %s, want 1", got)
+		}
+		if authenticated != 0 {
+			t.Errorf("got %d authentications, want none once throttled", authenticated)
+		}
+	})
+	t.Run("addresses have their own budget", func(t *testing.T) {
+		if resp := request("10.0.0.3", "hunter2"); resp.Code != http.StatusUnauthorized {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusUnauthorized)
+		}
+	})
+	t.Run("failures marked by handlers", func(t *testing.T) {
+		e.POST("/token", func(c echo.Context) error {
+			auth.SetFailed(c)
+			return c.NoContent(http.StatusBadRequest)
+		}, ThrottleAuthentication(AuthenticationConfig{Failures: Limit{Rate: 1, Burst: 2}}))
+
+		var codes []int
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest(http.MethodPost, "/token", nil)
+			req.Header.Set(echo.HeaderXRealIP, "10.0.0.4")
+			resp := httptest.NewRecorder()
+			e.ServeHTTP(resp, req)
+			codes = append(codes, resp.Code)
+		}
+		if codes[0] != http.StatusBadRequest || codes[1] != http.StatusBadRequest || codes[2] != http.StatusTooManyRequests {
+			t.Errorf("got %v status codes, want 2 failures then %d", codes, http.StatusTooManyRequests)
+		}
+	})
+}
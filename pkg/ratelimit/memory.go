@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the interval at which MemoryStore drops the buckets that are full again.
+const sweepInterval = time.Minute
+
+// memoryBucket is a bucket held by MemoryStore.
+type memoryBucket struct {
+	Bucket
+	fullAt time.Time
+}
+
+// MemoryStore holds buckets in memory. Each instance of the service limits
+// clients on its own, so the effective budget of a client is multiplied by
+// the number of instances.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]memoryBucket
+	lastSweep time.Time
+}
+
+// Take takes a token from the bucket identified by key.
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket.Bucket = NewBucket(limit, now)
+	}
+	var result Result
+	bucket.Bucket, result = bucket.Take(limit, now)
+	bucket.fullAt = now.Add(result.Reset)
+	s.buckets[key] = bucket
+	return result, nil
+}
+
+// Peek returns the result of taking a token from the bucket identified by
+// key, without taking it.
+func (s *MemoryStore) Peek(key string, limit Limit, now time.Time) Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket.Bucket = NewBucket(limit, now)
+	}
+	_, result := bucket.Take(limit, now)
+	return result
+}
+
+// sweep drops the buckets that are full at now, as they are no different from
+// new buckets. s.mu must be held.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, bucket := range s.buckets {
+		if !bucket.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// NewMemoryStore returns a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]memoryBucket{}}
+}
@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/shidenkai0/rental/pkg/auth"
+)
+
+// Rate limit headers, as defined by the IETF RateLimit header fields draft.
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// classExtension is the OpenAPI operation extension overriding the class of
+// an operation, read or write, eg. for GET operations changing state.
+const classExtension = "x-rate-limit-class"
+
+// Config defines the config for the RateLimit middleware.
+type Config struct {
+	// Skipper defines a function to skip the middleware.
+	Skipper middleware.Skipper
+	// Store holds the buckets of the clients.
+	Store Store
+	// Read and Write are the budgets of each client for read and write
+	// operations, a disabled limit doesn't restrict the operations.
+	Read, Write Limit
+	// Limits overrides Read and Write if set, so that the budgets can be
+	// changed while serving.
+	Limits *Limits
+	// IsWrite returns true if the request performs a write operation, it
+	// defaults to requests with an unsafe method, eg. POST.
+	IsWrite func(c echo.Context) bool
+}
+
+// RateLimit returns a middleware limiting the rate of the requests of each
+// client, identified by its principal when authenticated or by its IP address
+// otherwise. Read and write operations have separate budgets. Clients exceeding
+// their budget get a 429 Too Many Requests response with a Retry-After header.
+//
+// The middleware must be installed after Authenticate. Store failures are
+// logged and let requests through, an unavailable store doesn't take the API
+// down.
+func RateLimit(config Config) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+	if config.IsWrite == nil {
+		config.IsWrite = func(c echo.Context) bool {
+			return !isSafeMethod(c.Request().Method)
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			read, write := config.Read, config.Write
+			if config.Limits != nil {
+				read, write = config.Limits.Get()
+			}
+			class, limit := "read", read
+			if config.IsWrite(c) {
+				class, limit = "write", write
+			}
+			if !limit.Enabled() {
+				return next(c)
+			}
+
+			result, err := config.Store.Take(c.Request().Context(), class+":"+clientKey(c), limit, time.Now())
+			if err != nil {
+				slog.ErrorContext(c.Request().Context(), "rate limiting request", "error", err)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(HeaderRateLimitLimit, strconv.Itoa(limit.Burst))
+			header.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+			header.Set(HeaderRateLimitReset, seconds(result.Reset))
+			header.Set(HeaderRateLimitPolicy, strconv.Itoa(limit.Burst)+";w="+seconds(limit.fillTime(float64(limit.Burst))))
+			if !result.Allowed {
+				header.Set(echo.HeaderRetryAfter, seconds(result.RetryAfter))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "Rate limit exceeded")
+			}
+			return next(c)
+		}
+	}
+}
+
+// WriteOperations returns an IsWrite function classifying the operations of
+// spec, whose routes are served under baseURL. Operations are classified by
+// method, unless overridden by the x-rate-limit-class extension.
+func WriteOperations(spec *openapi3.T, baseURL string) func(c echo.Context) bool {
+	writes := map[string]bool{}
+	for path, item := range spec.Paths {
+		route := baseURL + pathParameter.ReplaceAllString(path, ":$1")
+		for method, operation := range item.Operations() {
+			isWrite := !isSafeMethod(method)
+			if raw, ok := operation.Extensions[classExtension].(json.RawMessage); ok {
+				var class string
+				if err := json.Unmarshal(raw, &class); err == nil {
+					isWrite = class == "write"
+				}
+			}
+			writes[method+" "+route] = isWrite
+		}
+	}
+	return func(c echo.Context) bool {
+		isWrite, ok := writes[c.Request().Method+" "+c.Path()]
+		if !ok {
+			return !isSafeMethod(c.Request().Method)
+		}
+		return isWrite
+	}
+}
+
+// pathParameter matches the parameters of OpenAPI paths, eg. {carId}.
+var pathParameter = regexp.MustCompile(`\{([^}]+)\}`)
+
+// clientKey identifies the client performing the request.
+func clientKey(c echo.Context) string {
+	if principal, ok := auth.PrincipalFrom(c); ok {
+		return "principal:" + principal.Subject
+	}
+	return "ip:" + c.RealIP()
+}
+
+// isSafeMethod returns true if method doesn't change state.
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// seconds formats d as a number of seconds, rounded up.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
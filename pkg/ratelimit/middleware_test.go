@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+	"github.com/shidenkai0/rental/pkg/auth"
+)
+
+func TestRateLimit(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	limiter := RateLimit(Config{
+		Store: NewMemoryStore(),
+		Read:  Limit{Rate: 1, Burst: 2},
+		Write: Limit{Rate: 1, Burst: 1},
+	})
+	ok := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	setPrincipal := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if subject := c.Request().Header.Get("X-Subject"); subject != "" {
+				auth.SetPrincipal(c, auth.Principal{Subject: subject})
+			}
+			return next(c)
+		}
+	}
+	e.GET("/car/:carId", ok, setPrincipal, limiter)
+	e.PUT("/car/:carId", ok, setPrincipal, limiter)
+
+	request := func(method, subject string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/car/1", nil)
+		if subject != "" {
+			req.Header.Set("X-Subject", subject)
+		}
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Test
+
+	t.Run("requests within budget", func(t *testing.T) {
+		resp := request(http.MethodGet, "jane")
+		if resp.Code != http.StatusOK {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+		}
+		if got := resp.Header().Get(HeaderRateLimitLimit); got != "2" {
+			t.Errorf("got limit %s, want 2", got)
+		}
+		if got := resp.Header().Get(HeaderRateLimitRemaining); got != "1" {
+			t.Errorf("got remaining %s, want 1", got)
+		}
+		if got := resp.Header().Get(HeaderRateLimitPolicy); got != "2;w=2" {
+			t.Errorf("got policy %s, want 2;w=2", got)
+		}
+	})
+	t.Run("requests beyond budget", func(t *testing.T) {
+		request(http.MethodGet, "jane")
+		resp := request(http.MethodGet, "jane")
+		if resp.Code != http.StatusTooManyRequests {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusTooManyRequests)
+		}
+		if got := resp.Header().Get(echo.HeaderRetryAfter); got != "1" {
+			t.Errorf("got retry after %s, want 1", got)
+		}
+	})
+	t.Run("write budget is separate", func(t *testing.T) {
+		if resp := request(http.MethodPut, "jane"); resp.Code != http.StatusOK {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+		}
+		if resp := request(http.MethodPut, "jane"); resp.Code != http.StatusTooManyRequests {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusTooManyRequests)
+		}
+	})
+	t.Run("clients have their own budget", func(t *testing.T) {
+		if resp := request(http.MethodGet, "john"); resp.Code != http.StatusOK {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+		}
+		if resp := request(http.MethodGet, ""); resp.Code != http.StatusOK {
+			t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+		}
+	})
+}
+
+func TestRateLimit_Limits(t *testing.T) {
+	// Setup
+
+	e := echo.New()
+	limits := NewLimits(Limit{Rate: 1, Burst: 1}, Limit{})
+	e.GET("/car/:carId", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, RateLimit(Config{Store: NewMemoryStore(), Limits: limits}))
+	request := func() *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/car/1", nil))
+		return resp
+	}
+
+	// Test
+
+	request()
+	if resp := request(); resp.Code != http.StatusTooManyRequests {
+		t.Errorf("got %d status code, want %d", resp.Code, http.StatusTooManyRequests)
+	}
+	limits.Set(Limit{}, Limit{})
+	if resp := request(); resp.Code != http.StatusOK {
+		t.Errorf("got %d status code once rate limiting is disabled, want %d", resp.Code, http.StatusOK)
+	}
+	limits.Set(Limit{Rate: 1, Burst: 5}, Limit{})
+	resp := request()
+	if got := resp.Header().Get(HeaderRateLimitLimit); got != "5" {
+		t.Errorf("got limit %s, want the new burst 5", got)
+	}
+}
+
+func TestWriteOperations(t *testing.T) {
+	swagger, err := gen.GetSwagger()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	isWrite := WriteOperations(swagger, "/v1")
+
+	tests := []struct {
+		name      string
+		method    string
+		routePath string
+		want      bool
+	}{
+		{name: "get a car", method: http.MethodGet, routePath: "/v1/car/:carId", want: false},
+		{name: "update a car", method: http.MethodPut, routePath: "/v1/car/:carId", want: true},
+		{name: "rent a car", method: http.MethodGet, routePath: "/v1/car/:carId/rent", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(tt.method, tt.routePath, nil), httptest.NewRecorder())
+			c.SetPath(tt.routePath)
+			if got := isWrite(c); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Package ratelimit implements token bucket rate limiting of the clients of
+// the rental API.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Limit is the budget of a client: its bucket holds up to Burst tokens and is
+// refilled at Rate tokens per second, each request takes a token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled returns true if the limit restricts anything, a zero rate disables it.
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// fillTime returns the time it takes to refill tokens.
+func (l Limit) fillTime(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / l.Rate * float64(time.Second)))
+}
+
+// Limits are the read and write budgets of the clients, which can be changed
+// while serving, eg. when the configuration is reloaded. Buckets are refilled
+// at the new rate and capped at the new burst from their next request.
+type Limits struct {
+	limits atomic.Pointer[[2]Limit]
+}
+
+// NewLimits returns the budgets read and write.
+func NewLimits(read, write Limit) *Limits {
+	l := &Limits{}
+	l.Set(read, write)
+	return l
+}
+
+// Get returns the read and write budgets.
+func (l *Limits) Get() (read, write Limit) {
+	limits := l.limits.Load()
+	return limits[0], limits[1]
+}
+
+// Set changes the read and write budgets.
+func (l *Limits) Set(read, write Limit) {
+	l.limits.Store(&[2]Limit{read, write})
+}
+
+// Bucket is the state of the token bucket of a client.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	// Allowed is true if a token was available.
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket.
+	Remaining int
+	// RetryAfter is the time until a token is available, 0 if Allowed.
+	RetryAfter time.Duration
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+}
+
+// NewBucket returns a full bucket for limit.
+func NewBucket(limit Limit, now time.Time) Bucket {
+	return Bucket{Tokens: float64(limit.Burst), UpdatedAt: now}
+}
+
+// Take refills the bucket for the time elapsed since its last update and takes
+// a token if one is available. It returns the new state of the bucket.
+func (b Bucket) Take(limit Limit, now time.Time) (Bucket, Result) {
+	burst := float64(limit.Burst)
+	if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(burst, b.Tokens+elapsed.Seconds()*limit.Rate)
+		b.UpdatedAt = now
+	}
+
+	var result Result
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = limit.fillTime(1 - b.Tokens)
+	}
+	result.Remaining = int(b.Tokens)
+	result.Reset = limit.fillTime(burst - b.Tokens)
+	return b, result
+}
+
+// Store holds the buckets of the clients. Implementations must take tokens
+// atomically, as concurrent requests of a client share its bucket.
+type Store interface {
+	// Take takes a token from the bucket identified by key, creating a
+	// full bucket for limit if none exists.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBucket_Take(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+	bucket := NewBucket(limit, now)
+
+	tests := []struct {
+		name          string
+		elapsed       time.Duration
+		wantAllowed   bool
+		wantRemaining int
+		wantRetry     time.Duration
+	}{
+		{name: "first token", wantAllowed: true, wantRemaining: 1},
+		{name: "last token", wantAllowed: true, wantRemaining: 0},
+		{name: "empty bucket", wantAllowed: false, wantRemaining: 0, wantRetry: time.Second},
+		{name: "half refilled bucket", elapsed: 500 * time.Millisecond, wantAllowed: false, wantRemaining: 0, wantRetry: 500 * time.Millisecond},
+		{name: "refilled token", elapsed: 500 * time.Millisecond, wantAllowed: true, wantRemaining: 0},
+		{name: "bucket refilled beyond burst", elapsed: time.Hour, wantAllowed: true, wantRemaining: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.elapsed)
+			var got Result
+			bucket, got = bucket.Take(limit, now)
+			if got.Allowed != tt.wantAllowed || got.Remaining != tt.wantRemaining || got.RetryAfter != tt.wantRetry {
+				t.Errorf("got %+v, want allowed %t, %d remaining, retry after %s", got, tt.wantAllowed, tt.wantRemaining, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 1}
+	now := time.Now()
+	store := NewMemoryStore()
+
+	t.Run("buckets are per key", func(t *testing.T) {
+		for _, key := range []string{"jane", "john"} {
+			got, err := store.Take(context.Background(), key, limit, now)
+			if err != nil || !got.Allowed {
+				t.Errorf("got %+v, %v, want allowed", got, err)
+			}
+		}
+		got, err := store.Take(context.Background(), "jane", limit, now)
+		if err != nil || got.Allowed {
+			t.Errorf("got %+v, %v, want denied", got, err)
+		}
+	})
+	t.Run("full buckets are swept", func(t *testing.T) {
+		if _, err := store.Take(context.Background(), "jane", limit, now.Add(time.Hour)); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if _, ok := store.buckets["john"]; ok {
+			t.Errorf("got bucket john, want it swept")
+		}
+	})
+}
@@ -0,0 +1,104 @@
+// Package rental provides the domain model of the rental service.
+package rental
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	// apiKeyPrefix starts every API key, so that leaked keys are easy to spot.
+	apiKeyPrefix = "rk_"
+	// apiKeyBytes is the number of random bytes of API keys.
+	apiKeyBytes = 32
+	// apiKeyDisplayLength is the length of the beginning of a key kept in clear to identify it.
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+)
+
+// APIKey is a credential granting a fixed set of scopes to a machine client.
+// Only a hash of the key is stored, the key itself is only shown once, when created.
+type APIKey struct {
+	ID         int       `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	Prefix     string    `json:"prefix" db:"prefix"`
+	KeyHash    string    `json:"-" db:"key_hash"`
+	Scope      string    `json:"scope" db:"scope"` // Space-separated list of scopes granted to the key
+	TenantID   int       `json:"tenant_id" db:"tenant_id"`
+	CreatedBy  string    `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt  null.Time `json:"expires_at" db:"expires_at"`
+	LastUsedAt null.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  null.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// NewAPIKey returns a new random key and the APIKey it authenticates.
+func NewAPIKey(name string, scopes []Scope, expiresAt null.Time) (string, APIKey, error) {
+	if name == "" {
+		return "", APIKey{}, ErrAPIKeyNameEmpty
+	}
+	fields := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return "", APIKey{}, fmt.Errorf("Invalid scope %q", scope)
+		}
+		fields = append(fields, string(scope))
+	}
+
+	secret := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", APIKey{}, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, APIKey{
+		Name:      name,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   HashAPIKey(key),
+		Scope:     strings.Join(fields, " "),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// HashAPIKey returns the hash under which an API key is stored.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// Scopes returns the scopes granted to the key.
+func (key *APIKey) Scopes() []Scope {
+	fields := strings.Fields(key.Scope)
+	scopes := make([]Scope, 0, len(fields))
+	for _, field := range fields {
+		scopes = append(scopes, Scope(field))
+	}
+	return scopes
+}
+
+// Valid returns true if the key is neither expired nor revoked.
+func (key *APIKey) Valid(now time.Time) bool {
+	return !key.RevokedAt.Valid && (!key.ExpiresAt.Valid || now.Before(key.ExpiresAt.Time))
+}
+
+type APIKeyService interface {
+	Create(ctx context.Context, key APIKey) (int, error)
+	Get(ctx context.Context, id int) (APIKey, error)
+	// GetByHash isn't scoped to a tenant, the key determines the tenant.
+	GetByHash(ctx context.Context, keyHash string) (APIKey, error)
+	List(ctx context.Context) ([]APIKey, error)
+	Revoke(ctx context.Context, id int) error
+	// Touch records that the key was used at the given time.
+	Touch(ctx context.Context, id int, usedAt time.Time) error
+}
+
+var (
+	ErrAPIKeyNotFound  = fmt.Errorf("API key not found")
+	ErrAPIKeyNameEmpty = fmt.Errorf("API key name must not be empty")
+)
@@ -0,0 +1,42 @@
+package rental
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	t.Run("new API key", func(t *testing.T) {
+		key, apiKey, err := NewAPIKey("billing", []Scope{ScopeCarsRead, ScopeRentalsWrite}, null.Time{})
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if !strings.HasPrefix(key, apiKey.Prefix) || apiKey.KeyHash != HashAPIKey(key) {
+			t.Errorf("got %v, want an API key matching %s", apiKey, key)
+		}
+		if apiKey.Scope != "cars:read rentals:write" {
+			t.Errorf("got scope %q, want %q", apiKey.Scope, "cars:read rentals:write")
+		}
+		if !apiKey.Valid(time.Now()) {
+			t.Errorf("got invalid key, want valid key")
+		}
+	})
+	t.Run("new API key with an invalid scope", func(t *testing.T) {
+		_, _, err := NewAPIKey("billing", []Scope{"cars:drive"}, null.Time{})
+		if err == nil {
+			t.Errorf("got nil, want error")
+		}
+	})
+	t.Run("expired API key", func(t *testing.T) {
+		_, apiKey, err := NewAPIKey("billing", []Scope{ScopeCarsRead}, null.TimeFrom(time.Now().Add(-time.Minute)))
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		if apiKey.Valid(time.Now()) {
+			t.Errorf("got valid key, want expired key")
+		}
+	})
+}
@@ -0,0 +1,121 @@
+// Package rental provides the domain model of the rental service.
+package rental
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Audited actions.
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	AuditActionRent   = "rent"
+	AuditActionReturn = "return"
+	AuditActionRevoke = "revoke"
+)
+
+// Audited entity types.
+const (
+	AuditEntityCar      = "car"
+	AuditEntityCustomer = "customer"
+	AuditEntityUser     = "user"
+	AuditEntityAPIKey   = "api_key"
+	AuditEntityTenant   = "tenant"
+)
+
+// AuditEntry records an administrative action performed on an entity.
+type AuditEntry struct {
+	ID         int             `json:"id" db:"id"`
+	Actor      string          `json:"actor" db:"actor"`
+	Action     string          `json:"action" db:"action"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityID   int             `json:"entity_id" db:"entity_id"`
+	Before     json.RawMessage `json:"before" db:"before"` // nil when the entity was created
+	After      json.RawMessage `json:"after" db:"after"`   // nil when the entity was deleted
+	Diff       json.RawMessage `json:"diff" db:"diff"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditChange describes how a single field changed between two states of an entity.
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// NewAuditEntry returns an AuditEntry for an action performed by actor on an entity, with
+// the before and after states of the entity serialized to JSON and diffed. A nil state
+// means the entity did not exist before or after the action.
+func NewAuditEntry(actor, action, entityType string, entityID int, before, after interface{}) (AuditEntry, error) {
+	entry := AuditEntry{Actor: actor, Action: action, EntityType: entityType, EntityID: entityID}
+
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return AuditEntry{}, err
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return AuditEntry{}, err
+		}
+	}
+	entry.Diff, err = AuditDiff(entry.Before, entry.After)
+	if err != nil {
+		return AuditEntry{}, err
+	}
+	return entry, nil
+}
+
+// AuditDiff returns a JSON object mapping each top-level field that differs between
+// two JSON objects to an AuditChange. A nil object is treated as having no fields.
+func AuditDiff(before, after json.RawMessage) (json.RawMessage, error) {
+	beforeFields, afterFields := map[string]interface{}{}, map[string]interface{}{}
+	if before != nil {
+		if err := json.Unmarshal(before, &beforeFields); err != nil {
+			return nil, fmt.Errorf("decoding before state: %w", err)
+		}
+	}
+	if after != nil {
+		if err := json.Unmarshal(after, &afterFields); err != nil {
+			return nil, fmt.Errorf("decoding after state: %w", err)
+		}
+	}
+
+	diff := map[string]AuditChange{}
+	for field, from := range beforeFields {
+		if to := afterFields[field]; !reflect.DeepEqual(from, to) {
+			diff[field] = AuditChange{From: from, To: to}
+		}
+	}
+	for field, to := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			diff[field] = AuditChange{From: nil, To: to}
+		}
+	}
+	return json.Marshal(diff)
+}
+
+// AuditFilter restricts the audit entries returned by AuditLogService.List,
+// zero-valued fields do not filter anything.
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   int
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+}
+
+// AuditLogService stores audit entries. Entries can only be appended, never
+// modified. RecordMany appends either all the entries or none of them.
+type AuditLogService interface {
+	Record(ctx context.Context, entry AuditEntry) (int, error)
+	RecordMany(ctx context.Context, entries []AuditEntry) error
+	List(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+}
@@ -0,0 +1,57 @@
+package rental
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	t.Run("audit an update", func(t *testing.T) {
+		before := Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2015}
+		after := Car{ID: 1, Make: "Toyota", Model: "Corolla", Year: 2016}
+		entry, err := NewAuditEntry("rental", AuditActionUpdate, AuditEntityCar, 1, before, after)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		want := `{"year":{"from":2015,"to":2016}}`
+		if string(entry.Diff) != want {
+			t.Errorf("got %s, want %s", entry.Diff, want)
+		}
+	})
+	t.Run("audit a creation", func(t *testing.T) {
+		after := Customer{ID: 1, Name: "John Doe"}
+		entry, err := NewAuditEntry("rental", AuditActionCreate, AuditEntityCustomer, 1, nil, after)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if entry.Before != nil {
+			t.Errorf("got %s, want nil before state", entry.Before)
+		}
+		want := `{"id":{"from":null,"to":1},"name":{"from":null,"to":"John Doe"}}`
+		if string(entry.Diff) != want {
+			t.Errorf("got %s, want %s", entry.Diff, want)
+		}
+	})
+}
+
+func TestAuditDiff(t *testing.T) {
+	t.Run("diff a deletion", func(t *testing.T) {
+		diff, err := AuditDiff(json.RawMessage(`{"name":"John Doe"}`), nil)
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		want := `{"name":{"from":"John Doe","to":null}}`
+		if string(diff) != want {
+			t.Errorf("got %s, want %s", diff, want)
+		}
+	})
+	t.Run("diff identical states", func(t *testing.T) {
+		diff, err := AuditDiff(json.RawMessage(`{"name":"John Doe"}`), json.RawMessage(`{"name":"John Doe"}`))
+		if err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if string(diff) != "{}" {
+			t.Errorf("got %s, want {}", diff)
+		}
+	})
+}
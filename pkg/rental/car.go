@@ -2,6 +2,7 @@
 package rental
 
 import (
+	"context"
 	"fmt"
 
 	"gopkg.in/guregu/null.v4"
@@ -14,6 +15,7 @@ type Car struct {
 	Make       string   `json:"make" db:"make"`
 	Model      string   `json:"model" db:"model"`
 	Year       int      `json:"year" db:"year"`
+	Version    int      `json:"-" db:"version"` // Incremented by each update, not part of audited state
 }
 
 // RenterID returns the ID of the customer who has rented the car, 0 if the car is not rented.
@@ -44,16 +46,74 @@ func (car *Car) Return() error {
 	return nil
 }
 
+// Validate returns an error if the car has missing or invalid details.
+func (car *Car) Validate() error {
+	if car.Make == "" {
+		return ErrCarMakeEmpty
+	}
+	if car.Model == "" {
+		return ErrCarModelEmpty
+	}
+	if car.Year <= 0 {
+		return ErrInvalidCarYear
+	}
+	return nil
+}
+
+// CarPatch is a partial update of the details of a car, nil fields are left unchanged.
+type CarPatch struct {
+	Make  *string
+	Model *string
+	Year  *int
+}
+
+// Empty returns true if the patch doesn't change anything.
+func (patch CarPatch) Empty() bool {
+	return patch.Make == nil && patch.Model == nil && patch.Year == nil
+}
+
+// Apply applies a patch to the car.
+func (car *Car) Apply(patch CarPatch) {
+	if patch.Make != nil {
+		car.Make = *patch.Make
+	}
+	if patch.Model != nil {
+		car.Model = *patch.Model
+	}
+	if patch.Year != nil {
+		car.Year = *patch.Year
+	}
+}
+
+// CarCRUDService stores cars. Update, Patch and Delete only apply to the
+// version of the car they are given, and return ErrCarVersionMismatch if the
+// car was modified in the meantime, or ErrCarNotFound if it was deleted.
+// Delete returns ErrCarHasRentals if the car was ever rented, to keep its
+// history. Update and Patch increment the version of
+// the car. CreateMany creates either all the cars or none of them, and returns
+// their ids in the order of the cars, GetMany returns the cars found, ordered
+// by id, and ForEach calls fn with every car in id order, stopping at the
+// first error.
 type CarCRUDService interface {
-	Create(car Car) (int, error)
-	Get(id int) (Car, error)
-	Update(car Car) error
-	Delete(carID int) error
+	Create(ctx context.Context, car Car) (int, error)
+	CreateMany(ctx context.Context, cars []Car) ([]int, error)
+	Get(ctx context.Context, id int) (Car, error)
+	GetMany(ctx context.Context, ids []int) ([]Car, error)
+	ForEach(ctx context.Context, fn func(car Car) error) error
+	ListRentedBy(ctx context.Context, customerID int) ([]Car, error)
+	Update(ctx context.Context, car Car) error
+	Patch(ctx context.Context, carID int, version int, patch CarPatch) error
+	Delete(ctx context.Context, carID int, version int) error
 }
 
 var (
-	ErrCarNotFound      = fmt.Errorf("Car not found")
-	ErrCarNotRented     = fmt.Errorf("Car not rented")
-	ErrCarAlreadyRented = fmt.Errorf("Car already rented")
-	ErrCarAlreadyExists = fmt.Errorf("Car already exists")
+	ErrCarNotFound        = fmt.Errorf("Car not found")
+	ErrCarNotRented       = fmt.Errorf("Car not rented")
+	ErrCarAlreadyRented   = fmt.Errorf("Car already rented")
+	ErrCarAlreadyExists   = fmt.Errorf("Car already exists")
+	ErrCarVersionMismatch = fmt.Errorf("Car was modified since it was read")
+	ErrCarHasRentals      = fmt.Errorf("Car has rentals")
+	ErrCarMakeEmpty       = fmt.Errorf("Car make must not be empty")
+	ErrCarModelEmpty      = fmt.Errorf("Car model must not be empty")
+	ErrInvalidCarYear     = fmt.Errorf("Car year must be positive")
 )
@@ -1,22 +1,64 @@
 // Package rental provides the domain model of the rental service.
 package rental
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // Customer represents a customer.
 type Customer struct {
 	ID   int    `json:"id" sql:"id"`
 	Name string `json:"name" sql:"name"`
+	// Version is incremented by each update, it isn't part of the audited state
+	Version int `json:"-" sql:"version"`
+}
+
+// Validate returns an error if the customer has missing details.
+func (customer *Customer) Validate() error {
+	if customer.Name == "" {
+		return ErrCustomerNameEmpty
+	}
+	return nil
+}
+
+// CustomerPatch is a partial update of the details of a customer, nil fields are left unchanged.
+type CustomerPatch struct {
+	Name *string
+}
+
+// Empty returns true if the patch doesn't change anything.
+func (patch CustomerPatch) Empty() bool {
+	return patch.Name == nil
+}
+
+// Apply applies a patch to the customer.
+func (customer *Customer) Apply(patch CustomerPatch) {
+	if patch.Name != nil {
+		customer.Name = *patch.Name
+	}
 }
 
+// CustomerCRUDService stores customers. Update, Patch and Delete only apply to
+// the version of the customer they are given, and return
+// ErrCustomerVersionMismatch if the customer was modified in the meantime, or
+// ErrCustomerNotFound if it was deleted. Delete returns ErrCustomerHasRentals
+// if the customer ever rented a car, to keep its history. Update and Patch
+// increment the version of the customer. GetMany returns the customers found,
+// ordered by id.
 type CustomerCRUDService interface {
-	Create(customer Customer) (int, error)
-	Get(id int) (Customer, error)
-	Update(customer Customer) error
-	Delete(customerID int) error
+	Create(ctx context.Context, customer Customer) (int, error)
+	Get(ctx context.Context, id int) (Customer, error)
+	GetMany(ctx context.Context, ids []int) ([]Customer, error)
+	Update(ctx context.Context, customer Customer) error
+	Patch(ctx context.Context, customerID int, version int, patch CustomerPatch) error
+	Delete(ctx context.Context, customerID int, version int) error
 }
 
 var (
-	ErrCustomerNotFound      = fmt.Errorf("Customer not found")
-	ErrCustomerAlreadyExists = fmt.Errorf("Customer already exists")
+	ErrCustomerNotFound        = fmt.Errorf("Customer not found")
+	ErrCustomerAlreadyExists   = fmt.Errorf("Customer already exists")
+	ErrCustomerVersionMismatch = fmt.Errorf("Customer was modified since it was read")
+	ErrCustomerHasRentals      = fmt.Errorf("Customer has rentals")
+	ErrCustomerNameEmpty       = fmt.Errorf("Customer name must not be empty")
 )
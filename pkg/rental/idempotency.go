@@ -0,0 +1,51 @@
+// Package rental provides the domain model of the rental service.
+package rental
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// IdempotencyKey records a request made by a client with an Idempotency-Key
+// header, along with its response once completed, so that retries of the
+// request replay the response instead of performing the operation again.
+type IdempotencyKey struct {
+	ID       int    `json:"id" db:"id"`
+	TenantID int    `json:"tenant_id" db:"tenant_id"`
+	Client   string `json:"client" db:"client"` // Principal that made the request
+	Key      string `json:"key" db:"key"`
+	// Fingerprint is a hash of the request, retries must be identical requests.
+	Fingerprint string `json:"fingerprint" db:"fingerprint"`
+	// StatusCode, ResponseHeaders and ResponseBody are set once the request is completed.
+	StatusCode      null.Int  `json:"status_code" db:"status_code"`
+	ResponseHeaders []byte    `json:"-" db:"response_headers"` // JSON encoded
+	ResponseBody    []byte    `json:"-" db:"response_body"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt       time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// Completed returns true if the response of the request has been recorded.
+func (key *IdempotencyKey) Completed() bool {
+	return key.StatusCode.Valid
+}
+
+type IdempotencyKeyService interface {
+	// Create records a new key, or returns ErrIdempotencyKeyAlreadyExists
+	// if the client already used the key and it hasn't expired.
+	Create(ctx context.Context, key IdempotencyKey) (int, error)
+	Get(ctx context.Context, client, key string) (IdempotencyKey, error)
+	// Complete records the response of the request made with the key.
+	Complete(ctx context.Context, key IdempotencyKey) error
+	Delete(ctx context.Context, id int) error
+	// Purge deletes the keys of all tenants that expired before now,
+	// returns the number of deleted keys.
+	Purge(ctx context.Context, now time.Time) (int64, error)
+}
+
+var (
+	ErrIdempotencyKeyNotFound      = fmt.Errorf("Idempotency key not found")
+	ErrIdempotencyKeyAlreadyExists = fmt.Errorf("Idempotency key already exists")
+)
@@ -0,0 +1,39 @@
+package rental
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// Rental represents the rental of a car by a customer, active until the car is returned.
+type Rental struct {
+	ID         int       `json:"id" db:"id"`
+	CarID      int       `json:"car_id" db:"car_id"`
+	CustomerID int       `json:"customer_id" db:"customer_id"`
+	StartedAt  time.Time `json:"started_at" db:"started_at"`
+	ReturnedAt null.Time `json:"returned_at" db:"returned_at"`
+}
+
+// Active returns true if the car hasn't been returned yet.
+func (rental *Rental) Active() bool {
+	return !rental.ReturnedAt.Valid
+}
+
+// RentalService records the rentals of cars. The state of cars remains the
+// reference, cars are rented and returned through CarCRUDService.
+type RentalService interface {
+	Create(ctx context.Context, rental Rental) (int, error)
+	Get(ctx context.Context, id int) (Rental, error)
+	// GetActiveByCar fetches the active rental of a car.
+	GetActiveByCar(ctx context.Context, carID int) (Rental, error)
+	// End records the return of the car of an active rental.
+	End(ctx context.Context, id int, returnedAt time.Time) error
+}
+
+var (
+	ErrRentalNotFound        = fmt.Errorf("Rental not found")
+	ErrRentalAlreadyReturned = fmt.Errorf("Rental already returned")
+)
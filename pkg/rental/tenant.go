@@ -0,0 +1,65 @@
+// Package rental provides the domain model of the rental service.
+package rental
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// DefaultTenantID is the tenant owning the data created before multi-tenancy.
+const DefaultTenantID = 1
+
+// tenantSlug matches valid tenant slugs, which are DNS labels so that tenants can be addressed by subdomain.
+var tenantSlug = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Tenant is a franchise operator, whose fleet, customers and users are isolated from other tenants.
+type Tenant struct {
+	ID        int       `json:"id" db:"id"`
+	Slug      string    `json:"slug" db:"slug"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Validate returns an error if the tenant can't be provisioned.
+func (tenant *Tenant) Validate() error {
+	if !tenantSlug.MatchString(tenant.Slug) {
+		return ErrInvalidTenantSlug
+	}
+	if tenant.Name == "" {
+		return ErrTenantNameEmpty
+	}
+	return nil
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx scoped to the tenant tenantID. Services,
+// except TenantService, only act on the data of the tenant of their context.
+func WithTenant(ctx context.Context, tenantID int) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFrom returns the tenant ctx is scoped to.
+func TenantFrom(ctx context.Context) (int, bool) {
+	tenantID, ok := ctx.Value(tenantContextKey{}).(int)
+	return tenantID, ok
+}
+
+// TenantService provisions tenants. Unlike other services, it isn't scoped to a tenant.
+type TenantService interface {
+	Create(ctx context.Context, tenant Tenant) (int, error)
+	Get(ctx context.Context, id int) (Tenant, error)
+	GetBySlug(ctx context.Context, slug string) (Tenant, error)
+	List(ctx context.Context) ([]Tenant, error)
+}
+
+var (
+	ErrTenantNotFound      = fmt.Errorf("Tenant not found")
+	ErrTenantAlreadyExists = fmt.Errorf("Tenant already exists")
+	ErrInvalidTenantSlug   = fmt.Errorf("Tenant slug must be a lowercase DNS label")
+	ErrTenantNameEmpty     = fmt.Errorf("Tenant name must not be empty")
+	// ErrNoTenant is returned by tenant-scoped services when called with a context not scoped to a tenant.
+	ErrNoTenant = fmt.Errorf("No tenant")
+)
@@ -0,0 +1,35 @@
+package rental
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTenant_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant Tenant
+		want   error
+	}{
+		{name: "valid tenant", tenant: Tenant{Slug: "lyon-2", Name: "Lyon 2"}, want: nil},
+		{name: "uppercase slug", tenant: Tenant{Slug: "Lyon", Name: "Lyon"}, want: ErrInvalidTenantSlug},
+		{name: "slug ending with a hyphen", tenant: Tenant{Slug: "lyon-", Name: "Lyon"}, want: ErrInvalidTenantSlug},
+		{name: "empty name", tenant: Tenant{Slug: "lyon"}, want: ErrTenantNameEmpty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tenant.Validate(); err != tt.want {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTenant(t *testing.T) {
+	if _, ok := TenantFrom(context.Background()); ok {
+		t.Errorf("got tenant, want none")
+	}
+	if got, ok := TenantFrom(WithTenant(context.Background(), 2)); !ok || got != 2 {
+		t.Errorf("got tenant %d, want 2", got)
+	}
+}
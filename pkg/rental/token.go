@@ -0,0 +1,43 @@
+// Package rental provides the domain model of the rental service.
+package rental
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+// RefreshToken is a long-lived credential a user can exchange for new access tokens.
+// Only a hash of the token is stored, the token itself is only known to the client.
+type RefreshToken struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	TenantID  int       `json:"tenant_id" db:"tenant_id"`
+	TokenHash string    `json:"-" db:"token_hash"`
+	Scope     string    `json:"scope" db:"scope"` // Space-separated list of scopes granted to the token
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	RevokedAt null.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// Valid returns true if the token is neither expired nor revoked.
+func (token *RefreshToken) Valid(now time.Time) bool {
+	return !token.RevokedAt.Valid && now.Before(token.ExpiresAt)
+}
+
+type RefreshTokenService interface {
+	Create(ctx context.Context, token RefreshToken) (int, error)
+	// GetByHash isn't scoped to a tenant, the token determines the tenant.
+	GetByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	// Revoke fails with ErrRefreshTokenRevoked if the token is already
+	// revoked, so that a token can only be exchanged once.
+	Revoke(ctx context.Context, tokenID int) error
+	RevokeAllForUser(ctx context.Context, userID int) error
+}
+
+var (
+	ErrRefreshTokenNotFound = fmt.Errorf("Refresh token not found")
+	ErrRefreshTokenRevoked  = fmt.Errorf("Refresh token already revoked")
+)
@@ -0,0 +1,11 @@
+package rental
+
+import "context"
+
+// Transactor runs operations spanning several services in a transaction.
+// The calls of the car and customer services made with the context passed to
+// fn take part in the transaction, which is committed if fn returns nil and
+// rolled back otherwise.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,182 @@
+// Package rental provides the domain model of the rental service.
+package rental
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/guregu/null.v4"
+)
+
+// Role determines the operations a user is allowed to perform.
+type Role string
+
+const (
+	// RoleOperator is the role of the platform operators, who provision
+	// tenants and may act on behalf of any tenant.
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+	RoleAgent    Role = "agent"
+	RoleReadOnly Role = "read-only"
+	// RoleCustomer is the role of customers using the API themselves, their
+	// user is bound to a customer whose data is the only one they can access.
+	RoleCustomer Role = "customer"
+)
+
+// Scope grants access to a set of operations.
+type Scope string
+
+const (
+	ScopeCarsRead       Scope = "cars:read"
+	ScopeCarsWrite      Scope = "cars:write"
+	ScopeCustomersRead  Scope = "customers:read"
+	ScopeCustomersWrite Scope = "customers:write"
+	ScopeRentalsWrite   Scope = "rentals:write"
+	ScopeAuditRead      Scope = "audit:read"
+	ScopeUsersAdmin     Scope = "users:admin"
+	ScopeTenantsAdmin   Scope = "tenants:admin"
+	// Self-service scopes only grant access to the data of the customer a user is bound to.
+	ScopeCustomersSelf Scope = "customers:self"
+	ScopeRentalsSelf   Scope = "rentals:self"
+)
+
+// knownScopes lists all the existing scopes.
+var knownScopes = []Scope{
+	ScopeCarsRead, ScopeCarsWrite, ScopeCustomersRead, ScopeCustomersWrite, ScopeRentalsWrite,
+	ScopeAuditRead, ScopeUsersAdmin, ScopeTenantsAdmin, ScopeCustomersSelf, ScopeRentalsSelf,
+}
+
+var roleScopes = map[Role][]Scope{
+	RoleOperator: {
+		ScopeCarsRead, ScopeCarsWrite, ScopeCustomersRead, ScopeCustomersWrite, ScopeRentalsWrite,
+		ScopeAuditRead, ScopeUsersAdmin, ScopeTenantsAdmin,
+	},
+	RoleAdmin: {
+		ScopeCarsRead, ScopeCarsWrite, ScopeCustomersRead, ScopeCustomersWrite, ScopeRentalsWrite,
+		ScopeAuditRead, ScopeUsersAdmin,
+	},
+	RoleAgent:    {ScopeCarsRead, ScopeCarsWrite, ScopeCustomersRead, ScopeCustomersWrite, ScopeRentalsWrite},
+	RoleReadOnly: {ScopeCarsRead, ScopeCustomersRead},
+	RoleCustomer: {ScopeCarsRead, ScopeCustomersSelf, ScopeRentalsSelf},
+}
+
+// Valid returns true if the scope exists.
+func (scope Scope) Valid() bool {
+	for _, known := range knownScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+// unrestrictedScopes maps the self-service scopes to the scopes granting the
+// same operations on the data of every customer.
+var unrestrictedScopes = map[Scope]Scope{
+	ScopeCustomersSelf: ScopeCustomersRead,
+	ScopeRentalsSelf:   ScopeRentalsWrite,
+}
+
+// Unrestricted returns the scope granting the operations of a self-service
+// scope on the data of every customer, or scope itself for other scopes.
+func (scope Scope) Unrestricted() Scope {
+	if unrestricted, ok := unrestrictedScopes[scope]; ok {
+		return unrestricted
+	}
+	return scope
+}
+
+// Valid returns true if the role exists.
+func (role Role) Valid() bool {
+	_, ok := roleScopes[role]
+	return ok
+}
+
+// Scopes returns the scopes granted to the role.
+func (role Role) Scopes() []Scope {
+	return roleScopes[role]
+}
+
+// HasScope returns true if the role grants scope.
+func (role Role) HasScope(scope Scope) bool {
+	for _, granted := range roleScopes[role] {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// minPasswordLength is the minimum length of user passwords.
+const minPasswordLength = 8
+
+// maxPasswordLength is the maximum length of user passwords, in bytes, as
+// bcrypt only hashes up to 72 bytes.
+const maxPasswordLength = 72
+
+// User represents a user of the rental API.
+type User struct {
+	ID           int    `json:"id" db:"id"`
+	Username     string `json:"username" db:"username"`
+	PasswordHash string `json:"-" db:"password_hash"`
+	Role         Role   `json:"role" db:"role"`
+	TenantID     int    `json:"tenant_id" db:"tenant_id"`
+	// CustomerID is the customer the user acts as, set if and only if the user has the customer role.
+	CustomerID null.Int `json:"customer_id" db:"customer_id"`
+}
+
+// CheckCustomer returns an error if the user isn't bound to a customer as required by its role.
+func (user *User) CheckCustomer() error {
+	if user.Role == RoleCustomer && !user.CustomerID.Valid {
+		return ErrUserCustomerRequired
+	}
+	if user.Role != RoleCustomer && user.CustomerID.Valid {
+		return ErrUserCustomerNotAllowed
+	}
+	return nil
+}
+
+// SetPassword hashes password and stores it as the user's password.
+func (user *User) SetPassword(password string) error {
+	if len(password) < minPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > maxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hash)
+	return nil
+}
+
+// CheckPassword returns true if password matches the user's password.
+func (user *User) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+}
+
+type UserCRUDService interface {
+	Create(ctx context.Context, user User) (int, error)
+	Get(ctx context.Context, id int) (User, error)
+	// GetByUsername isn't scoped to a tenant, usernames are unique across
+	// tenants so that users can authenticate before their tenant is known.
+	GetByUsername(ctx context.Context, username string) (User, error)
+	List(ctx context.Context) ([]User, error)
+	Update(ctx context.Context, user User) error
+	Delete(ctx context.Context, userID int) error
+}
+
+var (
+	ErrUserNotFound      = fmt.Errorf("User not found")
+	ErrUserAlreadyExists = fmt.Errorf("User already exists")
+	ErrInvalidRole       = fmt.Errorf("Invalid role")
+	ErrPasswordTooShort  = fmt.Errorf("Password must be at least %d characters long", minPasswordLength)
+	ErrPasswordTooLong   = fmt.Errorf("Password must be at most %d bytes long", maxPasswordLength)
+	ErrLastAdmin         = fmt.Errorf("The last user managing the users of a tenant can't be deleted or lose the role managing them")
+
+	ErrUserCustomerRequired   = fmt.Errorf("Users with the customer role must be bound to a customer")
+	ErrUserCustomerNotAllowed = fmt.Errorf("Only users with the customer role can be bound to a customer")
+)
@@ -0,0 +1,99 @@
+package rental
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestUser_SetPassword(t *testing.T) {
+	t.Run("set a valid password", func(t *testing.T) {
+		user := User{ID: 1, Username: "jane", Role: RoleAgent}
+		if err := user.SetPassword("correct horse"); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+		if user.PasswordHash == "" || user.PasswordHash == "correct horse" {
+			t.Errorf("got password hash %q, want a bcrypt hash", user.PasswordHash)
+		}
+	})
+	t.Run("set a password that is too short", func(t *testing.T) {
+		user := User{ID: 1, Username: "jane", Role: RoleAgent}
+		if err := user.SetPassword("short"); err != ErrPasswordTooShort {
+			t.Errorf("got error %v, want %v", err, ErrPasswordTooShort)
+		}
+	})
+	t.Run("set a password that is too long", func(t *testing.T) {
+		user := User{ID: 1, Username: "jane", Role: RoleAgent}
+		if err := user.SetPassword(strings.Repeat("a", 73)); err != ErrPasswordTooLong {
+			t.Errorf("got error %v, want %v", err, ErrPasswordTooLong)
+		}
+	})
+}
+
+func TestUser_CheckPassword(t *testing.T) {
+	user := User{ID: 1, Username: "jane", Role: RoleAgent}
+	if err := user.SetPassword("correct horse"); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+	if !user.CheckPassword("correct horse") {
+		t.Errorf("got false, want true for the right password")
+	}
+	if user.CheckPassword("battery staple") {
+		t.Errorf("got true, want false for a wrong password")
+	}
+}
+
+func TestRole_Scopes(t *testing.T) {
+	t.Run("read-only users can't write", func(t *testing.T) {
+		for _, scope := range RoleReadOnly.Scopes() {
+			if scope == ScopeCarsWrite || scope == ScopeCustomersWrite || scope == ScopeRentalsWrite {
+				t.Errorf("got scope %s, want read scopes only", scope)
+			}
+		}
+	})
+	t.Run("unknown roles have no scopes", func(t *testing.T) {
+		role := Role("superuser")
+		if role.Valid() || len(role.Scopes()) != 0 {
+			t.Errorf("got valid role with scopes %v, want invalid role without scopes", role.Scopes())
+		}
+	})
+}
+
+func TestScope_Unrestricted(t *testing.T) {
+	tests := []struct {
+		scope Scope
+		want  Scope
+	}{
+		{scope: ScopeCustomersSelf, want: ScopeCustomersRead},
+		{scope: ScopeRentalsSelf, want: ScopeRentalsWrite},
+		{scope: ScopeTenantsAdmin, want: ScopeTenantsAdmin},
+	}
+	for _, tt := range tests {
+		if got := tt.scope.Unrestricted(); got != tt.want {
+			t.Errorf("got %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestUser_CheckCustomer(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       Role
+		customerID null.Int
+		want       error
+	}{
+		{name: "customer bound to a customer", role: RoleCustomer, customerID: null.IntFrom(1), want: nil},
+		{name: "customer not bound to a customer", role: RoleCustomer, want: ErrUserCustomerRequired},
+		{name: "agent bound to a customer", role: RoleAgent, customerID: null.IntFrom(1), want: ErrUserCustomerNotAllowed},
+		{name: "agent not bound to a customer", role: RoleAgent, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := User{ID: 1, Username: "jane", Role: tt.role, CustomerID: tt.customerID}
+			if err := user.CheckCustomer(); err != tt.want {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Package seed generates fleets, customers and rental histories through the
+// services of the rental package, for local development and load tests. The
+// data generated only depends on the seed of the random generator and on the
+// sizes requested, so that it can be reproduced.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// History is the period covered by the rental history, ending at Config.End.
+const History = 365 * 24 * time.Hour
+
+// models are the models of the fleet, by make.
+var models = map[string][]string{
+	"Citroën": {"C3", "C4", "Berlingo"},
+	"Fiat":    {"500", "Panda", "Tipo"},
+	"Ford":    {"Fiesta", "Focus", "Kuga", "Mustang"},
+	"Honda":   {"Civic", "Accord", "Jazz"},
+	"Nissan":  {"Micra", "Qashqai", "Leaf"},
+	"Peugeot": {"208", "308", "3008", "5008"},
+	"Renault": {"Clio", "Mégane", "Captur", "Zoe"},
+	"Tesla":   {"Model 3", "Model Y"},
+	"Toyota":  {"Yaris", "Corolla", "Prius", "RAV4"},
+}
+
+// makes are the keys of models, in a stable order so that the data generated
+// doesn't depend on the iteration order of maps.
+var makes = []string{"Citroën", "Fiat", "Ford", "Honda", "Nissan", "Peugeot", "Renault", "Tesla", "Toyota"}
+
+var (
+	firstNames = []string{"Ada", "Alan", "Barbara", "Claude", "Dennis", "Edsger", "Frances", "Grace", "Hedy", "John", "Ken", "Linus", "Margaret", "Niklaus", "Radia", "Rob", "Sophie", "Tim"}
+	lastNames  = []string{"Allen", "Backus", "Dijkstra", "Hamilton", "Hopper", "Kernighan", "Lamarr", "Liskov", "Lovelace", "McCarthy", "Perlman", "Pike", "Ritchie", "Shannon", "Thompson", "Turing", "Wilson", "Wirth"}
+)
+
+// Config is the size of the data to generate.
+type Config struct {
+	// Seed seeds the random generator, the same seed generating the same data.
+	Seed      int64
+	Cars      int
+	Customers int
+	// Rentals is the number of rentals of the history, spread over the cars.
+	// The latest rental of a car may still be active.
+	Rentals int
+	// End is the end of the rental history, when the active rentals are
+	// still going on.
+	End time.Time
+}
+
+// Validate returns an error if the data can't be generated.
+func (c Config) Validate() error {
+	if c.Cars < 0 || c.Customers < 0 || c.Rentals < 0 {
+		return fmt.Errorf("Number of cars, customers and rentals must not be negative")
+	}
+	if c.Rentals > 0 && (c.Cars == 0 || c.Customers == 0) {
+		return fmt.Errorf("Rentals need at least one car and one customer")
+	}
+	return nil
+}
+
+// Services are the services the data is created with.
+type Services struct {
+	Cars       rental.CarCRUDService
+	Customers  rental.CustomerCRUDService
+	Rentals    rental.RentalService
+	Transactor rental.Transactor
+}
+
+// Report counts the data created.
+type Report struct {
+	Cars          int
+	Customers     int
+	Rentals       int
+	ActiveRentals int
+}
+
+// plannedRental is a rental to create, of the car and customer of the given
+// indices in the cars and customers created.
+type plannedRental struct {
+	car        int
+	customer   int
+	startedAt  time.Time
+	returnedAt time.Time // Zero if the rental is active
+}
+
+// Seed generates data of the size of config, and creates it in the tenant of
+// ctx with services, in a single transaction.
+func Seed(ctx context.Context, services Services, config Config) (Report, error) {
+	if err := config.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	// Plan everything before creating anything, so that the random generator
+	// is drawn from in the same order whatever the services return
+	rng := rand.New(rand.NewSource(config.Seed))
+	cars := make([]rental.Car, config.Cars)
+	for i := range cars {
+		carMake := makes[rng.Intn(len(makes))]
+		cars[i] = rental.Car{
+			Make:  carMake,
+			Model: models[carMake][rng.Intn(len(models[carMake]))],
+			Year:  config.End.Year() - rng.Intn(15),
+		}
+	}
+	customers := make([]rental.Customer, config.Customers)
+	for i := range customers {
+		customers[i] = rental.Customer{Name: firstNames[rng.Intn(len(firstNames))] + " " + lastNames[rng.Intn(len(lastNames))]}
+	}
+	rentals := planRentals(rng, config)
+
+	var report Report
+	err := services.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		report = Report{}
+		carIDs := make([]int, len(cars))
+		for i, car := range cars {
+			id, err := services.Cars.Create(ctx, car)
+			if err != nil {
+				return fmt.Errorf("creating car: %w", err)
+			}
+			carIDs[i] = id
+			report.Cars++
+		}
+		customerIDs := make([]int, len(customers))
+		for i, customer := range customers {
+			id, err := services.Customers.Create(ctx, customer)
+			if err != nil {
+				return fmt.Errorf("creating customer: %w", err)
+			}
+			customerIDs[i] = id
+			report.Customers++
+		}
+
+		for _, r := range rentals {
+			carID, customerID := carIDs[r.car], customerIDs[r.customer]
+			id, err := services.Rentals.Create(ctx, rental.Rental{CarID: carID, CustomerID: customerID, StartedAt: r.startedAt})
+			if err != nil {
+				return fmt.Errorf("creating rental: %w", err)
+			}
+			report.Rentals++
+			if !r.returnedAt.IsZero() {
+				if err := services.Rentals.End(ctx, id, r.returnedAt); err != nil {
+					return fmt.Errorf("ending rental: %w", err)
+				}
+				continue
+			}
+
+			// The car of an active rental is rented to its customer
+			car, err := services.Cars.Get(ctx, carID)
+			if err != nil {
+				return fmt.Errorf("renting car: %w", err)
+			}
+			if err := car.Rent(customerID); err != nil {
+				return fmt.Errorf("renting car: %w", err)
+			}
+			if err := services.Cars.Update(ctx, car); err != nil {
+				return fmt.Errorf("renting car: %w", err)
+			}
+			report.ActiveRentals++
+		}
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+// planRentals spreads the rentals of config over the cars, and lays out the
+// rentals of each car one after the other over the History, so that a car
+// is never rented twice at the same time. The latest rental of a car is
+// active one time out of three.
+func planRentals(rng *rand.Rand, config Config) []plannedRental {
+	rentalsByCar := make([]int, config.Cars)
+	customers := make([]int, config.Rentals)
+	for i := range customers {
+		rentalsByCar[rng.Intn(config.Cars)]++
+		customers[i] = rng.Intn(config.Customers)
+	}
+
+	begin := config.End.Add(-History)
+	rentals := make([]plannedRental, 0, config.Rentals)
+	for car, n := range rentalsByCar {
+		// Each rental starts in the first quarter of its slot of the history
+		// and lasts between a quarter and three quarters of it
+		slot := History / time.Duration(max(n, 1))
+		for k := 0; k < n; k++ {
+			startedAt := begin.Add(time.Duration(k)*slot + time.Duration(rng.Int63n(int64(slot/4)+1)))
+			duration := slot/4 + time.Duration(rng.Int63n(int64(slot/2)+1))
+			r := plannedRental{car: car, customer: customers[len(rentals)], startedAt: startedAt}
+			if k < n-1 || rng.Intn(3) > 0 {
+				r.returnedAt = startedAt.Add(duration)
+			}
+			rentals = append(rentals, r)
+		}
+	}
+	return rentals
+}
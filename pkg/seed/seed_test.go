@@ -0,0 +1,153 @@
+package seed
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shidenkai0/rental/mock"
+	"github.com/shidenkai0/rental/pkg/rental"
+)
+
+// sequencedCars generates the ids of the cars created, like the database,
+// as the mock uses the id of the car.
+type sequencedCars struct {
+	*mock.MockCarCRUDService
+	lastID int
+}
+
+func (s *sequencedCars) Create(ctx context.Context, car rental.Car) (int, error) {
+	s.lastID++
+	car.ID = s.lastID
+	return s.MockCarCRUDService.Create(ctx, car)
+}
+
+// sequencedCustomers generates the ids of the customers created.
+type sequencedCustomers struct {
+	*mock.MockCustomerCRUDService
+	lastID int
+}
+
+func (s *sequencedCustomers) Create(ctx context.Context, customer rental.Customer) (int, error) {
+	s.lastID++
+	customer.ID = s.lastID
+	return s.MockCustomerCRUDService.Create(ctx, customer)
+}
+
+// newServices returns services over an empty Mock state.
+func newServices() Services {
+	return Services{
+		Cars:       &sequencedCars{MockCarCRUDService: mock.NewMockCarCRUDService()},
+		Customers:  &sequencedCustomers{MockCustomerCRUDService: mock.NewMockCustomerCRUDService()},
+		Rentals:    mock.NewMockRentalService(),
+		Transactor: mock.NewMockTransactor(),
+	}
+}
+
+// dump returns the cars, customers and rentals of services.
+func dump(t *testing.T, ctx context.Context, services Services, report Report) ([]rental.Car, []rental.Customer, []rental.Rental) {
+	var cars []rental.Car
+	services.Cars.ForEach(ctx, func(car rental.Car) error {
+		cars = append(cars, car)
+		return nil
+	})
+	var customers []rental.Customer
+	for id := 1; id <= report.Customers; id++ {
+		customer, err := services.Customers.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		customers = append(customers, customer)
+	}
+	var rentals []rental.Rental
+	for id := 1; id <= report.Rentals; id++ {
+		r, err := services.Rentals.Get(ctx, id)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+		rentals = append(rentals, r)
+	}
+	return cars, customers, rentals
+}
+
+func TestSeed(t *testing.T) {
+	// Setup
+
+	ctx := rental.WithTenant(context.Background(), rental.DefaultTenantID)
+	end := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	config := Config{Seed: 42, Cars: 20, Customers: 10, Rentals: 100, End: end}
+
+	// Test
+
+	services := newServices()
+	report, err := Seed(ctx, services, config)
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	if report.Cars != 20 || report.Customers != 10 || report.Rentals != 100 {
+		t.Errorf("got report %+v, want 20 cars, 10 customers and 100 rentals", report)
+	}
+	cars, customers, rentals := dump(t, ctx, services, report)
+
+	rented := 0
+	for _, car := range cars {
+		if err := car.Validate(); err != nil {
+			t.Errorf("got invalid car %+v: %v", car, err)
+		}
+		if car.Rented() {
+			rented++
+		}
+	}
+	if rented != report.ActiveRentals {
+		t.Errorf("got %d cars rented, want %d, one per active rental", rented, report.ActiveRentals)
+	}
+	lastReturn := map[int]time.Time{}
+	for _, r := range rentals {
+		if r.StartedAt.Before(end.Add(-History)) || r.StartedAt.After(end) {
+			t.Errorf("got rental %+v starting out of the history", r)
+		}
+		if r.StartedAt.Before(lastReturn[r.CarID]) {
+			t.Errorf("got rental %+v starting before the previous rental of the car is returned", r)
+		}
+		lastReturn[r.CarID] = r.ReturnedAt.Time
+		if r.Active() {
+			car, _ := services.Cars.Get(ctx, r.CarID)
+			if car.RenterID() != r.CustomerID {
+				t.Errorf("got car %+v, want car rented by the customer of active rental %+v", car, r)
+			}
+		} else if r.ReturnedAt.Time.After(end) {
+			t.Errorf("got rental %+v returned after the end of the history", r)
+		}
+	}
+
+	// The same seed generates the same data
+	other := newServices()
+	if _, err := Seed(ctx, other, config); err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	otherCars, otherCustomers, otherRentals := dump(t, ctx, other, report)
+	if !reflect.DeepEqual(cars, otherCars) || !reflect.DeepEqual(customers, otherCustomers) || !reflect.DeepEqual(rentals, otherRentals) {
+		t.Errorf("got different data for the same seed, want the same data")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{name: "Valid", config: Config{Cars: 1, Customers: 1, Rentals: 10}},
+		{name: "Empty", config: Config{}},
+		{name: "Negative", config: Config{Cars: -1}, wantErr: true},
+		{name: "Rentals without cars", config: Config{Customers: 1, Rentals: 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package tracing sets up the OpenTelemetry tracing of the rental service,
+// and traces the requests served by the API.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName names the tracers of the rental service.
+const InstrumentationName = "github.com/shidenkai0/rental"
+
+// Exporters of the spans.
+const (
+	// ExporterNone disables tracing, spans are still propagated to
+	// downstream services.
+	ExporterNone = "none"
+	// ExporterStdout writes spans to the standard output, for local debugging.
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends spans to an OpenTelemetry collector over OTLP/HTTP.
+	ExporterOTLP = "otlp"
+)
+
+// Config configures tracing.
+type Config struct {
+	// Exporter is the exporter of the spans, ExporterNone if empty.
+	Exporter string
+	// OTLPEndpoint is the host and port of the collector, eg.
+	// localhost:4318. If empty, the OTEL_EXPORTER_OTLP_* variables apply.
+	OTLPEndpoint string
+	// OTLPInsecure sends spans to the collector over plain HTTP.
+	OTLPInsecure bool
+	// SampleRatio is the ratio of the traces started by the service which
+	// are sampled, traces started upstream follow the upstream decision.
+	SampleRatio float64
+	// ServiceName and ServiceVersion identify the service in the traces,
+	// the version is optional.
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Setup installs the global tracer provider exporting spans as configured,
+// and the W3C trace context propagator. It returns a function flushing the
+// spans left and shutting down the exporter.
+func Setup(ctx context.Context, config Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch config.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		var options []otlptracehttp.Option
+		if config.OTLPEndpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(config.OTLPEndpoint))
+		}
+		if config.OTLPInsecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := []attribute.KeyValue{semconv.ServiceName(config.ServiceName)}
+	if config.ServiceVersion != "" {
+		attributes = append(attributes, semconv.ServiceVersion(config.ServiceVersion))
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attributes...))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Middleware returns a middleware tracing the requests in a server span
+// named after their route, eg. GET /v1/car/:carId, continuing the trace
+// of the W3C trace context of the request headers. It must be installed
+// first, so that the spans of the other middleware are children of the
+// request span.
+func Middleware() echo.MiddlewareFunc {
+	tracer := otel.Tracer(InstrumentationName)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracer.Start(ctx, req.Method+" "+c.Path(),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(c.Path()),
+					semconv.URLPath(req.URL.Path),
+				),
+			)
+			defer span.End()
+			c.SetRequest(req.WithContext(ctx))
+
+			// Handle the error here, so that the status code is known
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package versioning
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// BreakingChange is a change of an API spec breaking the clients of a
+// previous revision of the spec.
+type BreakingChange struct {
+	// Operation is the operation changed, eg. GET /car/{carId}.
+	Operation string
+	// Message describes the change.
+	Message string
+}
+
+func (c BreakingChange) String() string {
+	return c.Operation + ": " + c.Message
+}
+
+// BreakingChanges returns the changes of revision breaking the clients of
+// base, sorted by operation. Within a major version of the API, changes must
+// be additive, so a revision breaks its clients when it:
+//
+//   - removes an operation, or a content type of its request body or of a
+//     successful response;
+//   - adds a required parameter or request body, or requires an optional one;
+//   - changes the type of a parameter or of a property;
+//   - requires a new property in a request body, or restricts the values of
+//     an enum in a request;
+//   - removes a property from a response, makes a required property of a
+//     response optional or nullable, or adds a value to an enum in a response.
+//
+// Operations are matched by method and path, regardless of the name of the
+// path parameters.
+func BreakingChanges(base, revision *openapi3.T) []BreakingChange {
+	revisionPaths := map[string]*openapi3.PathItem{}
+	for path, item := range revision.Paths {
+		revisionPaths[pathParameter.ReplaceAllString(path, "{}")] = item
+	}
+
+	c := &compatChecker{}
+	for path, item := range base.Paths {
+		revisionItem := revisionPaths[pathParameter.ReplaceAllString(path, "{}")]
+		for method, operation := range item.Operations() {
+			c.operation = method + " " + path
+			var revisionOperation *openapi3.Operation
+			if revisionItem != nil {
+				revisionOperation = revisionItem.GetOperation(method)
+			}
+			if revisionOperation == nil {
+				c.add("operation removed")
+				continue
+			}
+			c.parameters(parameters(item, operation), parameters(revisionItem, revisionOperation))
+			c.requestBody(operation.RequestBody, revisionOperation.RequestBody)
+			c.responses(operation.Responses, revisionOperation.Responses)
+		}
+	}
+
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		return c.changes[i].Operation < c.changes[j].Operation
+	})
+	return c.changes
+}
+
+// compatChecker collects the breaking changes of the operation being compared.
+type compatChecker struct {
+	operation string
+	changes   []BreakingChange
+}
+
+// add records a breaking change of the current operation.
+func (c *compatChecker) add(format string, a ...interface{}) {
+	c.changes = append(c.changes, BreakingChange{Operation: c.operation, Message: fmt.Sprintf(format, a...)})
+}
+
+// parameters returns the parameters of an operation, including the parameters of its path.
+func parameters(item *openapi3.PathItem, operation *openapi3.Operation) map[string]*openapi3.Parameter {
+	params := map[string]*openapi3.Parameter{}
+	for _, ref := range append(item.Parameters, operation.Parameters...) {
+		params[ref.Value.In+" parameter "+ref.Value.Name] = ref.Value
+	}
+	return params
+}
+
+func (c *compatChecker) parameters(base, revision map[string]*openapi3.Parameter) {
+	for name, param := range revision {
+		baseParam, ok := base[name]
+		if param.In == openapi3.ParameterInPath {
+			// Path parameters are matched by position, and always required
+			continue
+		}
+		if param.Required && (!ok || !baseParam.Required) {
+			c.add("%s is required", name)
+		}
+	}
+	for name, param := range base {
+		revisionParam, ok := revision[name]
+		if !ok || param.Schema == nil || revisionParam.Schema == nil {
+			continue
+		}
+		c.schema(name, param.Schema, revisionParam.Schema, true, map[*openapi3.Schema]bool{})
+	}
+}
+
+func (c *compatChecker) requestBody(base, revision *openapi3.RequestBodyRef) {
+	if revision == nil {
+		return
+	}
+	if base == nil {
+		if revision.Value.Required {
+			c.add("request body is required")
+		}
+		return
+	}
+	if revision.Value.Required && !base.Value.Required {
+		c.add("request body is required")
+	}
+	c.content("request body", base.Value.Content, revision.Value.Content, true)
+}
+
+func (c *compatChecker) responses(base, revision openapi3.Responses) {
+	for status, response := range base {
+		if !strings.HasPrefix(status, "2") {
+			// Clients handle errors generically
+			continue
+		}
+		revisionResponse, ok := revision[status]
+		if !ok {
+			c.add("%s response removed", status)
+			continue
+		}
+		c.content(status+" response", response.Value.Content, revisionResponse.Value.Content, false)
+	}
+}
+
+// content compares the media types of a request body or response, request
+// is true for request bodies.
+func (c *compatChecker) content(where string, base, revision openapi3.Content, request bool) {
+	for mime, mediaType := range base {
+		revisionMediaType, ok := revision[mime]
+		if !ok {
+			c.add("%s content type %s removed", where, mime)
+			continue
+		}
+		if mediaType.Schema == nil || revisionMediaType.Schema == nil {
+			continue
+		}
+		c.schema(where, mediaType.Schema, revisionMediaType.Schema, request, map[*openapi3.Schema]bool{})
+	}
+}
+
+// schema compares the schema of a value sent by clients, if request is
+// true, or received by clients. visited holds the base schemas compared
+// already, so that recursive schemas are compared once.
+func (c *compatChecker) schema(where string, base, revision *openapi3.SchemaRef, request bool, visited map[*openapi3.Schema]bool) {
+	baseSchema, revisionSchema := base.Value, revision.Value
+	if visited[baseSchema] {
+		return
+	}
+	visited[baseSchema] = true
+
+	if baseSchema.Type != revisionSchema.Type {
+		c.add("%s changed type from %q to %q", where, baseSchema.Type, revisionSchema.Type)
+		return
+	}
+	if request {
+		if missing := missingValues(baseSchema.Enum, revisionSchema.Enum); len(missing) > 0 {
+			c.add("%s no longer accepts %v", where, missing)
+		}
+		for _, name := range missingStrings(revisionSchema.Required, baseSchema.Required) {
+			c.add("%s requires property %s", where, name)
+		}
+	} else {
+		if added := missingValues(revisionSchema.Enum, baseSchema.Enum); len(added) > 0 {
+			c.add("%s may be %v", where, added)
+		}
+		for _, name := range missingStrings(baseSchema.Required, revisionSchema.Required) {
+			c.add("%s no longer requires property %s", where, name)
+		}
+		if revisionSchema.Nullable && !baseSchema.Nullable {
+			c.add("%s is nullable", where)
+		}
+	}
+
+	for name, property := range baseSchema.Properties {
+		revisionProperty, ok := revisionSchema.Properties[name]
+		if !ok {
+			if !request {
+				c.add("%s property %s removed", where, name)
+			}
+			continue
+		}
+		c.schema(where+" property "+name, property, revisionProperty, request, visited)
+	}
+	if baseSchema.Items != nil && revisionSchema.Items != nil {
+		c.schema(where+" items", baseSchema.Items, revisionSchema.Items, request, visited)
+	}
+}
+
+// missingStrings returns the values of a missing from b.
+func missingStrings(a, b []string) []string {
+	var missing []string
+	for _, value := range a {
+		found := false
+		for _, other := range b {
+			found = found || other == value
+		}
+		if !found {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
+
+// missingValues returns the enum values of a missing from b, an empty enum
+// allowing any value.
+func missingValues(a, b []interface{}) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	as, bs := make([]string, len(a)), make([]string, len(b))
+	for i, value := range a {
+		as[i] = fmt.Sprint(value)
+	}
+	for i, value := range b {
+		bs[i] = fmt.Sprint(value)
+	}
+	if len(a) == 0 {
+		// Restricting any value to an enum
+		return []string{"any value"}
+	}
+	return missingStrings(as, bs)
+}
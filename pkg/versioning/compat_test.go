@@ -0,0 +1,126 @@
+package versioning
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// baseSpec is the spec revised by the tests of BreakingChanges.
+const baseSpec = `
+openapi: 3.0.0
+info: {title: test, version: 1.0.0}
+paths:
+  /car/{carId}:
+    parameters:
+      - {name: carId, in: path, required: true, schema: {type: integer}}
+    get:
+      parameters:
+        - {name: fields, in: query, schema: {type: string}}
+      responses:
+        '200':
+          description: car
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Car'}
+    put:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Car'}
+      responses:
+        '204': {description: updated}
+components:
+  schemas:
+    Car:
+      type: object
+      required: [make]
+      properties:
+        make: {type: string}
+        year: {type: integer}
+        status: {type: string, enum: [available, rented]}
+`
+
+func TestBreakingChanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		replacements []string
+		wantChanges  []string
+	}{
+		{name: "unchanged"},
+		{
+			name:         "path parameter renamed",
+			replacements: []string{"carId", "id"},
+		},
+		{
+			name:         "optional property added",
+			replacements: []string{"year: {type: integer}", "year: {type: integer}\n        color: {type: string}"},
+		},
+		{
+			name:         "operation removed",
+			replacements: []string{"    put:", "    post:"},
+			wantChanges:  []string{"PUT /car/{carId}: operation removed"},
+		},
+		{
+			name:         "query parameter required",
+			replacements: []string{"in: query,", "in: query, required: true,"},
+			wantChanges:  []string{"GET /car/{carId}: query parameter fields is required"},
+		},
+		{
+			name:         "property type changed",
+			replacements: []string{"year: {type: integer}", "year: {type: string}"},
+			wantChanges: []string{
+				`GET /car/{carId}: 200 response property year changed type from "integer" to "string"`,
+				`PUT /car/{carId}: request body property year changed type from "integer" to "string"`,
+			},
+		},
+		{
+			name:         "property required",
+			replacements: []string{"required: [make]", "required: [make, year]"},
+			wantChanges:  []string{"PUT /car/{carId}: request body requires property year"},
+		},
+		{
+			name:         "enum value added",
+			replacements: []string{"enum: [available, rented]", "enum: [available, rented, maintenance]"},
+			wantChanges:  []string{"GET /car/{carId}: 200 response property status may be [maintenance]"},
+		},
+		{
+			name:         "property removed",
+			replacements: []string{"        year: {type: integer}\n", ""},
+			wantChanges:  []string{"GET /car/{carId}: 200 response property year removed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			base, err := openapi3.NewLoader().LoadFromData([]byte(baseSpec))
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+			revised := baseSpec
+			for i := 0; i < len(tt.replacements); i += 2 {
+				revised = strings.ReplaceAll(revised, tt.replacements[i], tt.replacements[i+1])
+			}
+			revision, err := openapi3.NewLoader().LoadFromData([]byte(revised))
+			if err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+
+			// Test
+
+			var got []string
+			for _, change := range BreakingChanges(base, revision) {
+				got = append(got, change.String())
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantChanges) {
+				t.Errorf("got changes %q, want %q", got, tt.wantChanges)
+			}
+		})
+	}
+}
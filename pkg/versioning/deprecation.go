@@ -0,0 +1,60 @@
+// Package versioning supports serving several versions of the rental API
+// side by side, and signals the deprecation of operations and versions to
+// clients.
+package versioning
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// Deprecation headers, as defined by the IETF Deprecation header field draft.
+const (
+	HeaderDeprecation = "Deprecation"
+	HeaderLink        = "Link"
+)
+
+// successorExtension is the OpenAPI operation extension giving the URL of the
+// operation replacing a deprecated operation, eg. in a newer version.
+const successorExtension = "x-successor"
+
+// DeprecatedOperations returns a middleware setting the Deprecation header on
+// the responses of the operations of spec marked as deprecated, whose routes
+// are served under baseURL. The successor of an operation, given by the
+// x-successor extension, is linked with the successor-version relation.
+func DeprecatedOperations(spec *openapi3.T, baseURL string) echo.MiddlewareFunc {
+	successors := map[string]string{}
+	for path, item := range spec.Paths {
+		route := baseURL + pathParameter.ReplaceAllString(path, ":$1")
+		for method, operation := range item.Operations() {
+			if !operation.Deprecated {
+				continue
+			}
+			var successor string
+			if raw, ok := operation.Extensions[successorExtension].(json.RawMessage); ok {
+				_ = json.Unmarshal(raw, &successor)
+			}
+			successors[method+" "+route] = successor
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			successor, ok := successors[c.Request().Method+" "+c.Path()]
+			if ok {
+				header := c.Response().Header()
+				header.Set(HeaderDeprecation, "true")
+				if successor != "" {
+					header.Add(HeaderLink, "<"+successor+`>; rel="successor-version"`)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// pathParameter matches the parameters of OpenAPI paths, eg. {carId}.
+var pathParameter = regexp.MustCompile(`\{([^}]+)\}`)
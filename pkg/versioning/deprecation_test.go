@@ -0,0 +1,52 @@
+package versioning
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shidenkai0/rental/pkg/api/gen"
+)
+
+func TestDeprecatedOperations(t *testing.T) {
+	swagger, err := gen.GetSwagger()
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+
+	tests := []struct {
+		name            string
+		method          string
+		routePath       string
+		wantDeprecation string
+		wantLink        string
+	}{
+		{name: "rent a car", method: http.MethodGet, routePath: "/v1/car/:carId/rent", wantDeprecation: "true", wantLink: `</v2/rentals>; rel="successor-version"`},
+		{name: "return a car", method: http.MethodGet, routePath: "/v1/car/:carId/return", wantDeprecation: "true", wantLink: `</v2/rentals>; rel="successor-version"`},
+		{name: "read a car", method: http.MethodGet, routePath: "/v1/car/:carId"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+
+			e := echo.New()
+			e.Add(tt.method, tt.routePath, func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, DeprecatedOperations(swagger, "/v1"))
+
+			req := httptest.NewRequest(tt.method, tt.routePath, nil)
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			e.ServeHTTP(resp, req)
+			if got := resp.Header().Get(HeaderDeprecation); got != tt.wantDeprecation {
+				t.Errorf("got %s header %q, want %q", HeaderDeprecation, got, tt.wantDeprecation)
+			}
+			if got := resp.Header().Get(HeaderLink); got != tt.wantLink {
+				t.Errorf("got %s header %q, want %q", HeaderLink, got, tt.wantLink)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package versioning
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderSunset is the header giving the date after which a deprecated
+// version may stop being served, as defined by RFC 8594.
+const HeaderSunset = "Sunset"
+
+// versionName matches the names of major versions, eg. v1.
+var versionName = regexp.MustCompile(`^v[1-9][0-9]*$`)
+
+// Version is a major version of the API, served under its own base URL
+// from its own OpenAPI spec and generated server interface.
+type Version struct {
+	// Name is the name of the version, eg. v1, which is also its base URL.
+	Name string
+	// Spec is the OpenAPI spec of the version.
+	Spec *openapi3.T
+	// Register registers the handlers of the version on its route group.
+	Register func(g *echo.Group)
+	// Deprecated marks the version as deprecated in favour of the next
+	// version, it is still served until its sunset.
+	Deprecated bool
+	// Sunset is the date after which a deprecated version may stop being
+	// served, zero if it isn't scheduled yet.
+	Sunset time.Time
+}
+
+// BaseURL returns the path under which the version is served, eg. /v1.
+func (v Version) BaseURL() string {
+	return "/" + v.Name
+}
+
+// Registry holds the versions of the API served side by side, from the
+// oldest to the newest.
+type Registry struct {
+	versions []Version
+}
+
+// NewRegistry returns a Registry of versions, which must be given from the
+// oldest to the newest. The newest version can't be deprecated, and only
+// deprecated versions have a sunset.
+func NewRegistry(versions ...Version) (*Registry, error) {
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("No API version")
+	}
+	names := map[string]bool{}
+	for i, v := range versions {
+		if !versionName.MatchString(v.Name) {
+			return nil, fmt.Errorf("Invalid API version name %q", v.Name)
+		}
+		if names[v.Name] {
+			return nil, fmt.Errorf("Duplicate API version %s", v.Name)
+		}
+		names[v.Name] = true
+		if v.Spec == nil || v.Register == nil {
+			return nil, fmt.Errorf("API version %s has no spec or handlers", v.Name)
+		}
+		if v.Deprecated && i == len(versions)-1 {
+			return nil, fmt.Errorf("API version %s is deprecated but has no successor", v.Name)
+		}
+		if !v.Sunset.IsZero() && !v.Deprecated {
+			return nil, fmt.Errorf("API version %s has a sunset but isn't deprecated", v.Name)
+		}
+	}
+	return &Registry{versions: versions}, nil
+}
+
+// Versions returns the versions of the registry, from the oldest to the newest.
+func (r *Registry) Versions() []Version {
+	return r.versions
+}
+
+// Successor returns the version following the version name, if any.
+func (r *Registry) Successor(name string) (Version, bool) {
+	for i, v := range r.versions[:len(r.versions)-1] {
+		if v.Name == name {
+			return r.versions[i+1], true
+		}
+	}
+	return Version{}, false
+}
+
+// Mount serves every version of the registry on e under its base URL, with
+// the middleware returned for the version. Responses of deprecated versions
+// and operations carry the Deprecation, Sunset and Link headers.
+func (r *Registry) Mount(e *echo.Echo, middleware func(v Version) []echo.MiddlewareFunc) {
+	for _, v := range r.versions {
+		chain := []echo.MiddlewareFunc{DeprecatedOperations(v.Spec, v.BaseURL())}
+		if v.Deprecated {
+			successor, _ := r.Successor(v.Name)
+			chain = append(chain, DeprecatedVersion(v, successor))
+		}
+		v.Register(e.Group(v.BaseURL(), append(chain, middleware(v)...)...))
+	}
+}
+
+// DeprecatedVersion returns a middleware setting the Deprecation header on
+// the responses of the deprecated version v, and the Sunset header when it
+// is scheduled. The successor version is linked with the successor-version
+// relation.
+func DeprecatedVersion(v Version, successor Version) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set(HeaderDeprecation, "true")
+			if !v.Sunset.IsZero() {
+				header.Set(HeaderSunset, v.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if successor.Name != "" {
+				header.Add(HeaderLink, "<"+successor.BaseURL()+`>; rel="successor-version"`)
+			}
+			return next(c)
+		}
+	}
+}
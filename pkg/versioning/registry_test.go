@@ -0,0 +1,100 @@
+package versioning
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// testVersion returns a version serving GET /car.
+func testVersion(name string) Version {
+	return Version{
+		Name: name,
+		Spec: &openapi3.T{Paths: openapi3.Paths{}},
+		Register: func(g *echo.Group) {
+			g.GET("/car", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+		},
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	deprecated := testVersion("v1")
+	deprecated.Deprecated = true
+	sunset := testVersion("v1")
+	sunset.Sunset = time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		versions []Version
+		wantErr  bool
+	}{
+		{name: "versions", versions: []Version{deprecated, testVersion("v2")}},
+		{name: "no version", wantErr: true},
+		{name: "invalid name", versions: []Version{testVersion("1.0")}, wantErr: true},
+		{name: "duplicate version", versions: []Version{testVersion("v1"), testVersion("v1")}, wantErr: true},
+		{name: "newest version deprecated", versions: []Version{testVersion("v0"), deprecated}, wantErr: true},
+		{name: "sunset without deprecation", versions: []Version{sunset, testVersion("v2")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRegistry(tt.versions...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_Mount(t *testing.T) {
+	// Setup
+
+	v1 := testVersion("v1")
+	v1.Deprecated = true
+	v1.Sunset = time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	registry, err := NewRegistry(v1, testVersion("v2"))
+	if err != nil {
+		t.Fatalf("got error %v, want nil", err)
+	}
+	e := echo.New()
+	registry.Mount(e, func(v Version) []echo.MiddlewareFunc {
+		return nil
+	})
+
+	tests := []struct {
+		path            string
+		wantDeprecation string
+		wantSunset      string
+		wantLink        string
+	}{
+		{path: "/v1/car", wantDeprecation: "true", wantSunset: "Fri, 01 Jan 2027 00:00:00 GMT", wantLink: `</v2>; rel="successor-version"`},
+		{path: "/v2/car"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			resp := httptest.NewRecorder()
+
+			// Test
+
+			e.ServeHTTP(resp, req)
+			if resp.Code != http.StatusOK {
+				t.Errorf("got %d status code, want %d", resp.Code, http.StatusOK)
+			}
+			if got := resp.Header().Get(HeaderDeprecation); got != tt.wantDeprecation {
+				t.Errorf("got %s header %q, want %q", HeaderDeprecation, got, tt.wantDeprecation)
+			}
+			if got := resp.Header().Get(HeaderSunset); got != tt.wantSunset {
+				t.Errorf("got %s header %q, want %q", HeaderSunset, got, tt.wantSunset)
+			}
+			if got := resp.Header().Get(HeaderLink); got != tt.wantLink {
+				t.Errorf("got %s header %q, want %q", HeaderLink, got, tt.wantLink)
+			}
+		})
+	}
+}